@@ -7,18 +7,22 @@ import (
 	"net/http/pprof"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/kelseyhightower/envconfig"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"go.uber.org/zap"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
 
 	"github.com/kyma-project/kyma-metrics-collector/env"
 	"github.com/kyma-project/kyma-metrics-collector/options"
 	"github.com/kyma-project/kyma-metrics-collector/pkg/collector/edp"
 	"github.com/kyma-project/kyma-metrics-collector/pkg/config"
+	specscli "github.com/kyma-project/kyma-metrics-collector/pkg/config/cli"
 	"github.com/kyma-project/kyma-metrics-collector/pkg/keb"
 	log "github.com/kyma-project/kyma-metrics-collector/pkg/logger"
 	kmcmetrics "github.com/kyma-project/kyma-metrics-collector/pkg/metrics"
@@ -28,6 +32,9 @@ import (
 	"github.com/kyma-project/kyma-metrics-collector/pkg/resource/pvc"
 	"github.com/kyma-project/kyma-metrics-collector/pkg/resource/redis"
 	"github.com/kyma-project/kyma-metrics-collector/pkg/resource/vsc"
+	"github.com/kyma-project/kyma-metrics-collector/pkg/runtime"
+	"github.com/kyma-project/kyma-metrics-collector/pkg/runtime/crsource"
+	"github.com/kyma-project/kyma-metrics-collector/pkg/runtime/filesource"
 	"github.com/kyma-project/kyma-metrics-collector/pkg/runtime/kubeconfigprovider"
 	"github.com/kyma-project/kyma-metrics-collector/pkg/service"
 )
@@ -35,11 +42,18 @@ import (
 const (
 	metricsPath            = "/metrics"
 	healthzPath            = "/healthz"
-	edpCredentialsFile     = "/edp-credentials/token"
 	kubeconfigProviderName = "kubeconfig"
+	runtimeCRResyncPeriod  = 10 * time.Minute
+	// workerPoolsTTL is the duration for which the worker pools of a shoot are cached.
+	workerPoolsTTL = 15 * time.Minute
 )
 
 func main() {
+	// the specs subcommand validates and diffs public cloud specs files without starting KMC.
+	if len(os.Args) > 1 && os.Args[1] == specscli.Command {
+		os.Exit(specscli.Run(os.Args[0]+" "+specscli.Command, os.Args[2:], os.Stdout, os.Stderr))
+	}
+
 	opts := options.ParseArgs()
 	logger := log.NewLogger(opts.LogLevel)
 	logger.Infof("Starting application with options: %v", opts.String())
@@ -72,24 +86,38 @@ func main() {
 
 	logger.Debugf("public cloud spec: %v", publicCloudSpecs)
 
-	k8sConfig, err := rest.InClusterConfig()
+	evictionGuard, err := kmcprocess.LoadEvictionGuardConfig()
 	if err != nil {
-		logger.With(log.KeyResult, log.ValueFail).With(log.KeyError, err.Error()).Fatal("Load InCluster Config")
+		logger.With(log.KeyResult, log.ValueFail).With(log.KeyError, err.Error()).Fatal("Load eviction guard config")
 	}
 
-	secretCacheClient, err := kubernetes.NewForConfig(k8sConfig)
-	if err != nil {
-		logger.With(log.KeyResult, log.ValueFail).With(log.KeyError, err.Error()).Fatal("Setup secrets client")
-	}
+	var (
+		runtimeSource      runtime.Source
+		kubeconfigProvider runtime.ConfigProvider
+	)
 
-	// Create a client for KEB communication
-	kebConfig := new(keb.Config)
-	if err := envconfig.Process("", kebConfig); err != nil {
-		logger.With(log.KeyResult, log.ValueFail).With(log.KeyError, err.Error()).Fatal("Load KEB config")
+	if opts.RuntimeSource == options.RuntimeSourceFile {
+		// the file lists the kubeconfigs of the runtimes, and removing runtimes from it is intended,
+		// so the eviction guard is disabled.
+		for _, name := range []string{"EVICTION_GUARD_MAX_PERCENTAGE", "EVICTION_GUARD_CONFIRMATIONS", "KEB_MAX_EVICTION_PERCENTAGE", "KEB_EVICTION_CONFIRMATIONS"} {
+			if _, ok := os.LookupEnv(name); ok {
+				logger.Warnf("%s is ignored, as the eviction guard is disabled for the runtime source %s", name, options.RuntimeSourceFile)
+			}
+		}
+
+		fileSource := filesource.New(opts.RuntimeFile, opts.RuntimeFilePoll, logger)
+		runtimeSource = fileSource
+		kubeconfigProvider = fileSource
+		evictionGuard = kmcprocess.EvictionGuardConfig{}
+	} else {
+		runtimeSource = newRuntimeSource(opts, logger)
+		kubeconfigProvider = newKubeconfigProvider(opts, logger)
 	}
 
-	kebClient := keb.NewClient(kebConfig, logger)
-	logger.Debugf("keb config: %v", kebConfig)
+	trackingPolicy, err := kmcprocess.LoadTrackingPolicy(opts.TrackingPolicyFile)
+	if err != nil {
+		logger.With(log.KeyResult, log.ValueFail).With(log.KeyError, err.Error()).Fatal("Load tracking policy")
+	}
 
 	// Creating EDP client
 	edpConfig := new(edp.Config)
@@ -97,17 +125,34 @@ func main() {
 		logger.With(log.KeyResult, log.ValueFail).With(log.KeyError, err.Error()).Fatal("Load EDP config")
 	}
 
-	// read the token from the mounted secret
-	token, err := getEDPToken()
-	if err != nil {
-		logger.With(log.KeyResult, log.ValueFail).With(log.KeyError, err.Error()).Fatal("Load EDP token")
+	// read the token from the mounted secret, unless it is set directly
+	if edpConfig.Token == "" && edpConfig.TokenFile != "" {
+		token, err := getEDPToken(edpConfig.TokenFile)
+		if err != nil {
+			logger.With(log.KeyResult, log.ValueFail).With(log.KeyError, err.Error()).Fatal("Load EDP token")
+		}
+
+		edpConfig.Token = token
 	}
 
-	edpConfig.Token = token
+	if edpConfig.Token == "" {
+		logger.Warn("No EDP token configured, the requests to EDP are not authenticated")
+	}
 
 	edpClient := edp.NewClient(edpConfig, logger)
 
-	nodeScanner := node.NewScanner(publicCloudSpecs)
+	// the volume sizes and spot settings of the worker pools are read from Gardener if a Gardener project is configured
+	var workerPools node.WorkerPools
+	if opts.GardenerKubeconfig != "" && opts.GardenerProjectNamespace != "" {
+		workerPools = node.NewGardenerWorkerPools(newGardenerClient(opts, logger), opts.GardenerProjectNamespace, workerPoolsTTL)
+	}
+
+	nodeScanner := node.NewScanner(publicCloudSpecs, node.Policy{
+		ExcludeDeleting:   opts.ExcludeDeletingNodes,
+		NotReadyThreshold: opts.NotReadyNodeThreshold,
+		DetectGPUCapacity: opts.DetectGPUCapacity,
+		VolumeProviders:   opts.NodeVolumeProviders,
+	}, workerPools)
 	pvcScanner := pvc.NewScanner()
 	redisScanner := redis.NewScanner(publicCloudSpecs)
 	vscScanner := vsc.NewScanner()
@@ -119,10 +164,8 @@ func main() {
 		vscScanner,
 	)
 
-	kubeconfigProvider := kubeconfigprovider.New(secretCacheClient.CoreV1(), logger, opts.KubeconfigCacheTTL, kubeconfigProviderName)
-
 	kmcProcess, err := kmcprocess.New(
-		kebClient,
+		runtimeSource,
 		edpClient,
 		edpCollector,
 		kubeconfigProvider,
@@ -131,6 +174,8 @@ func main() {
 		opts.WorkerPoolSize,
 		logger,
 		opts.FilterRuntimeFile,
+		evictionGuard,
+		trackingPolicy,
 	)
 	if err != nil {
 		logger.With(log.KeyResult, log.ValueFail).With(log.KeyError, err.Error()).Fatal("Create KMC process")
@@ -141,7 +186,7 @@ func main() {
 
 	// add debug service.
 	if opts.DebugPort > 0 {
-		enableDebugging(opts.DebugPort, logger)
+		enableDebugging(opts.DebugPort, logger, kmcProcess)
 	}
 
 	router := mux.NewRouter()
@@ -160,7 +205,100 @@ func main() {
 	kmcSvr.Start()
 }
 
-func enableDebugging(debugPort int, log *zap.SugaredLogger) {
+// newGardenerClient creates the client of the Gardener project configured by the options.
+func newGardenerClient(opts *options.Options, logger *zap.SugaredLogger) dynamic.Interface {
+	gardenerConfig, err := clientcmd.BuildConfigFromFlags("", opts.GardenerKubeconfig)
+	if err != nil {
+		logger.With(log.KeyResult, log.ValueFail).With(log.KeyError, err.Error()).Fatal("Load Gardener kubeconfig")
+	}
+
+	gardenerClient, err := dynamic.NewForConfig(gardenerConfig)
+	if err != nil {
+		logger.With(log.KeyResult, log.ValueFail).With(log.KeyError, err.Error()).Fatal("Setup Gardener client")
+	}
+
+	return gardenerClient
+}
+
+// newKCPConfig loads the config of the KCP cluster in which KMC runs. It is only loaded by the runtime sources and
+// kubeconfig providers which read from the KCP cluster, so KMC can run outside a cluster with the others.
+func newKCPConfig(logger *zap.SugaredLogger) *rest.Config {
+	k8sConfig, err := rest.InClusterConfig()
+	if err != nil {
+		logger.With(log.KeyResult, log.ValueFail).With(log.KeyError, err.Error()).Fatal("Load InCluster Config")
+	}
+
+	return k8sConfig
+}
+
+// newKubeconfigProvider creates the provider of the kubeconfigs of the runtimes selected by the options.
+func newKubeconfigProvider(opts *options.Options, logger *zap.SugaredLogger) runtime.ConfigProvider {
+	switch opts.KubeconfigProvider {
+	case options.KubeconfigProviderDirectory:
+		return kubeconfigprovider.NewDirectory(opts.KubeconfigDir, kubeconfigProviderName)
+	case options.KubeconfigProviderGardener:
+		return kubeconfigprovider.NewGardener(newGardenerClient(opts, logger), opts.GardenerProjectNamespace,
+			opts.GardenerKubeconfigExpiration, logger, kubeconfigProviderName)
+	}
+
+	secrets := kubeconfigprovider.SecretConfig{
+		Namespace:    opts.KubeconfigSecretNamespace,
+		NameTemplate: opts.KubeconfigSecretNameTemplate,
+		DataKey:      opts.KubeconfigSecretDataKey,
+	}
+	if err := secrets.Validate(); err != nil {
+		logger.With(log.KeyResult, log.ValueFail).With(log.KeyError, err.Error()).Fatal("Load kubeconfig secret config")
+	}
+
+	client, err := kubernetes.NewForConfig(newKCPConfig(logger))
+	if err != nil {
+		logger.With(log.KeyResult, log.ValueFail).With(log.KeyError, err.Error()).Fatal("Setup secrets client")
+	}
+
+	if opts.KubeconfigProvider != options.KubeconfigProviderInformer {
+		return kubeconfigprovider.New(client.CoreV1(), secrets, logger, opts.KubeconfigCacheTTL, opts.KubeconfigNotFoundTTL,
+			kubeconfigProviderName)
+	}
+
+	// the informer watches the secrets, so no periodic resync is needed
+	provider := kubeconfigprovider.NewInformer(client, secrets, logger, 0, opts.KubeconfigLabelSelector, kubeconfigProviderName)
+	if err := provider.Start(context.Background()); err != nil {
+		logger.With(log.KeyResult, log.ValueFail).With(log.KeyError, err.Error()).Fatal("Start kubeconfig informer")
+	}
+
+	return provider
+}
+
+// newRuntimeSource creates the source of the runtimes to track selected by the options.
+func newRuntimeSource(opts *options.Options, logger *zap.SugaredLogger) runtime.Source {
+	if opts.RuntimeSource == options.RuntimeSourceRuntimeCR {
+		dynamicClient, err := dynamic.NewForConfig(newKCPConfig(logger))
+		if err != nil {
+			logger.With(log.KeyResult, log.ValueFail).With(log.KeyError, err.Error()).Fatal("Setup Runtime CRs client")
+		}
+
+		return crsource.New(dynamicClient, opts.RuntimeCRNamespace, runtimeCRResyncPeriod, logger)
+	}
+
+	// Create a client for KEB communication
+	kebConfig := new(keb.Config)
+	if err := envconfig.Process("", kebConfig); err != nil {
+		logger.With(log.KeyResult, log.ValueFail).With(log.KeyError, err.Error()).Fatal("Load KEB config")
+	}
+
+	kebClient, err := keb.NewClient(kebConfig, logger)
+	if err != nil {
+		logger.With(log.KeyResult, log.ValueFail).With(log.KeyError, err.Error()).Fatal("Setup KEB client")
+	}
+
+	logger.Debugf("keb config: %v", kebConfig)
+
+	return keb.NewRuntimeSource(kebClient)
+}
+
+// enableDebugging serves pprof, the tracking decisions and the node breakdowns, which contain the account IDs of all
+// runtimes, on the debug port.
+func enableDebugging(debugPort int, log *zap.SugaredLogger, kmcProcess *kmcprocess.Process) {
 	debugRouter := mux.NewRouter()
 	// for security reason we always listen on localhost
 	debugSvc := service.Server{
@@ -178,6 +316,8 @@ func enableDebugging(debugPort int, log *zap.SugaredLogger) {
 	debugRouter.Handle("/debug/pprof/goroutine", pprof.Handler("goroutine"))
 	debugRouter.Handle("/debug/pprof/heap", pprof.Handler("heap"))
 	debugRouter.Handle("/debug/pprof/threadcreate", pprof.Handler("threadcreate"))
+	debugRouter.Path(kmcprocess.TrackingDecisionsPath).Methods(http.MethodGet).HandlerFunc(kmcProcess.ServeTrackingDecisions)
+	debugRouter.Path(kmcprocess.NodeBreakdownPath).Methods(http.MethodGet).HandlerFunc(kmcProcess.ServeNodeBreakdowns)
 
 	go func() {
 		debugSvc.Start()
@@ -185,8 +325,8 @@ func enableDebugging(debugPort int, log *zap.SugaredLogger) {
 }
 
 // getEDPToken read the EDP token from the mounted secret file.
-func getEDPToken() (string, error) {
-	token, err := os.ReadFile(edpCredentialsFile)
+func getEDPToken(tokenFile string) (string, error) {
+	token, err := os.ReadFile(tokenFile)
 	if err != nil {
 		return "", err
 	}
@@ -5,18 +5,46 @@ import (
 	"fmt"
 	"log"
 	"net/url"
+	"slices"
+	"strings"
 	"time"
 
 	"go.uber.org/zap/zapcore"
+
+	"github.com/kyma-project/kyma-metrics-collector/pkg/config"
+	"github.com/kyma-project/kyma-metrics-collector/pkg/runtime/kubeconfigprovider"
 )
 
 const (
-	DefaultScrapeInterval     = 3 * time.Minute
-	DefaultWorkerPoolSize     = 5
-	DefaultDebugPort          = 0
-	DefaultListenAddr         = 8080
-	DefaultLogLevel           = zapcore.InfoLevel
-	DefaultKubeconfigCacheTTL = 30 * time.Minute
+	DefaultScrapeInterval        = 3 * time.Minute
+	DefaultWorkerPoolSize        = 5
+	DefaultDebugPort             = 0
+	DefaultListenAddr            = 8080
+	DefaultLogLevel              = zapcore.InfoLevel
+	DefaultKubeconfigCacheTTL    = 30 * time.Minute
+	DefaultKubeconfigNotFoundTTL = time.Minute
+	DefaultRuntimeSource         = RuntimeSourceKEB
+	DefaultRuntimeCRNamespace    = "kcp-system"
+	DefaultRuntimeFilePoll       = 30 * time.Second
+	DefaultKubeconfigProvider    = KubeconfigProviderCache
+
+	DefaultGardenerKubeconfigExpiration = time.Hour
+
+	// RuntimeSourceKEB polls the runtimes from KEB.
+	RuntimeSourceKEB = "keb"
+	// RuntimeSourceRuntimeCR watches the Runtime CRs of infrastructure-manager in the KCP cluster.
+	RuntimeSourceRuntimeCR = "runtime-cr"
+	// RuntimeSourceFile reads the runtimes and their kubeconfigs from a static file.
+	RuntimeSourceFile = "file"
+
+	// KubeconfigProviderCache fetches the kubeconfig secrets on demand and caches them with a TTL.
+	KubeconfigProviderCache = "cache"
+	// KubeconfigProviderInformer watches the kubeconfig secrets with an informer.
+	KubeconfigProviderInformer = "informer"
+	// KubeconfigProviderDirectory reads the kubeconfigs from <runtimeID>.yaml files in a local directory.
+	KubeconfigProviderDirectory = "directory"
+	// KubeconfigProviderGardener requests short-lived admin kubeconfigs of the shoots from Gardener.
+	KubeconfigProviderGardener = "gardener"
 )
 
 type Options struct {
@@ -29,7 +57,35 @@ type Options struct {
 	ListenAddr          int
 	LogLevel            zapcore.Level
 	KubeconfigCacheTTL  time.Duration
-	FilterRuntimeFile   string
+	// KubeconfigNotFoundTTL is the duration for which missing kubeconfig secrets are cached.
+	KubeconfigNotFoundTTL time.Duration
+	FilterRuntimeFile     string
+	RuntimeSource         string
+	RuntimeCRNamespace    string
+	RuntimeFile           string
+	RuntimeFilePoll       time.Duration
+	TrackingPolicyFile    string
+	KubeconfigProvider    string
+	// KubeconfigLabelSelector restricts the secrets watched by the informer kubeconfig provider.
+	KubeconfigLabelSelector string
+	// KubeconfigSecretNamespace, KubeconfigSecretNameTemplate and KubeconfigSecretDataKey describe the kubeconfig
+	// secrets read by the cache and informer kubeconfig providers.
+	KubeconfigSecretNamespace    string
+	KubeconfigSecretNameTemplate string
+	KubeconfigSecretDataKey      string
+	// KubeconfigDir is the directory of the kubeconfig files read by the directory kubeconfig provider.
+	KubeconfigDir string
+	// GardenerKubeconfig, GardenerProjectNamespace and GardenerKubeconfigExpiration configure the gardener kubeconfig provider.
+	GardenerKubeconfig           string
+	GardenerProjectNamespace     string
+	GardenerKubeconfigExpiration time.Duration
+	// ExcludeDeletingNodes and NotReadyNodeThreshold configure which nodes are excluded from billing.
+	ExcludeDeletingNodes  bool
+	NotReadyNodeThreshold time.Duration
+	// DetectGPUCapacity detects GPU nodes from their nvidia.com/gpu capacity in addition to the VM types in the specs.
+	DetectGPUCapacity bool
+	// NodeVolumeProviders are the providers whose runtimes are billed for the root volumes of their nodes.
+	NodeVolumeProviders []string
 }
 
 func ParseArgs() *Options {
@@ -42,26 +98,133 @@ func ParseArgs() *Options {
 	debugPort := flag.Int("debug-port", DefaultDebugPort, "The custom port to debug when needed")
 	filterRuntimeFile := flag.String("filter-runtime-file", "", "The file containing the list of runtimes to filter")
 	kubeconfigCacheTTL := flag.Duration("kubeconfig-cache-ttl", DefaultKubeconfigCacheTTL, "The TTL of the kubeconfig cache")
+	kubeconfigNotFoundTTL := flag.Duration("kubeconfig-not-found-ttl", DefaultKubeconfigNotFoundTTL, "The TTL of missing kubeconfig secrets in the kubeconfig cache")
+	runtimeSource := flag.String("runtime-source", DefaultRuntimeSource, "The source of the runtimes to track. One of keb, runtime-cr, file")
+	runtimeCRNamespace := flag.String("runtime-cr-namespace", DefaultRuntimeCRNamespace, "The namespace of the Runtime CRs if the runtime source is runtime-cr")
+	runtimeFile := flag.String("runtime-file", "", "The YAML or JSON file listing the runtimes to track if the runtime source is file")
+	runtimeFilePoll := flag.Duration("runtime-file-poll-interval", DefaultRuntimeFilePoll, "The interval to check the runtime file for changes")
+	trackingPolicyFile := flag.String("tracking-policy-file", "", "The YAML or JSON file containing the policy which runtimes to track. If not set, the default policy is used")
+	kubeconfigProvider := flag.String("kubeconfig-provider", DefaultKubeconfigProvider, "The provider of the kubeconfigs of the runtimes. One of cache, informer, directory, gardener")
+	kubeconfigLabelSelector := flag.String("kubeconfig-secret-label-selector", "", "The label selector of the kubeconfig secrets watched if the kubeconfig provider is informer")
+	kubeconfigSecretNamespace := flag.String("kubeconfig-secret-namespace", kubeconfigprovider.DefaultSecretNamespace, "The namespace of the kubeconfig secrets")
+	kubeconfigSecretNameTemplate := flag.String("kubeconfig-secret-name-template", kubeconfigprovider.DefaultSecretNameTemplate, "The name of the kubeconfig secrets, in which {runtimeID} is replaced by the runtime ID")
+	kubeconfigSecretDataKey := flag.String("kubeconfig-secret-data-key", kubeconfigprovider.DefaultSecretDataKey, "The data key of the kubeconfig in the kubeconfig secrets")
+	kubeconfigDir := flag.String("kubeconfig-dir", "", "The directory of the <runtimeID>.yaml kubeconfig files if the kubeconfig provider is directory")
+	gardenerKubeconfig := flag.String("gardener-kubeconfig", "", "The kubeconfig of the Gardener project if the kubeconfig provider is gardener or the worker pools of the runtimes are read from it")
+	gardenerProjectNamespace := flag.String("gardener-project-namespace", "", "The namespace of the Gardener project if the kubeconfig provider is gardener or the worker pools of the runtimes are read from it")
+	gardenerKubeconfigExpiration := flag.Duration("gardener-kubeconfig-expiration", DefaultGardenerKubeconfigExpiration, "The expiration of the admin kubeconfigs requested from Gardener")
+	excludeDeletingNodes := flag.Bool("exclude-deleting-nodes", false, "Exclude nodes which are being deleted or are tainted for deletion by the cluster autoscaler from billing")
+	notReadyNodeThreshold := flag.Duration("not-ready-node-threshold", 0, "Exclude nodes which are not ready for longer than the threshold from billing. 0 disables the exclusion")
+	detectGPUCapacity := flag.Bool("detect-gpu-capacity", false, "Detect GPU nodes from their nvidia.com/gpu capacity if their VM type has no accelerators in the specs")
+	nodeVolumeProviders := flag.String("node-volume-providers", "", "The comma-separated providers whose runtimes are billed for the root volumes of their nodes, e.g. aws,gcp")
 	flag.Parse()
 
+	switch *kubeconfigProvider {
+	case KubeconfigProviderCache, KubeconfigProviderInformer:
+	case KubeconfigProviderDirectory:
+		if *kubeconfigDir == "" {
+			log.Fatalf("kubeconfig provider %s requires --kubeconfig-dir", KubeconfigProviderDirectory)
+		}
+	case KubeconfigProviderGardener:
+		if *gardenerKubeconfig == "" || *gardenerProjectNamespace == "" {
+			log.Fatalf("kubeconfig provider %s requires --gardener-kubeconfig and --gardener-project-namespace", KubeconfigProviderGardener)
+		}
+	default:
+		log.Fatalf("unknown kubeconfig provider: %s", *kubeconfigProvider)
+	}
+
+	switch *runtimeSource {
+	case RuntimeSourceKEB, RuntimeSourceRuntimeCR:
+	case RuntimeSourceFile:
+		if *runtimeFile == "" {
+			log.Fatalf("runtime source %s requires --runtime-file", RuntimeSourceFile)
+		}
+
+		// the file lists the kubeconfigs of the runtimes, so a kubeconfig provider would be ignored
+		if isFlagSet("kubeconfig-provider") {
+			log.Fatalf("runtime source %s does not support --kubeconfig-provider", RuntimeSourceFile)
+		}
+	default:
+		log.Fatalf("unknown runtime source: %s", *runtimeSource)
+	}
+
+	if *runtimeFilePoll <= 0 {
+		log.Fatalf("--runtime-file-poll-interval must be positive: %v", *runtimeFilePoll)
+	}
+
+	if *gardenerKubeconfig != "" && *gardenerProjectNamespace == "" {
+		log.Fatalf("reading the worker pools from Gardener requires --gardener-project-namespace")
+	}
+
 	err := logLevel.Set(*logLevelStr)
 	if err != nil {
 		log.Fatalf("failed to parse log level: %v", logLevel)
 	}
 
 	return &Options{
-		ScrapeInterval:     *scrapeInterval,
-		WorkerPoolSize:     *workerPoolSize,
-		DebugPort:          *debugPort,
-		LogLevel:           logLevel,
-		ListenAddr:         *listenAddr,
-		KubeconfigCacheTTL: *kubeconfigCacheTTL,
-		FilterRuntimeFile:  *filterRuntimeFile,
+		ScrapeInterval:               *scrapeInterval,
+		WorkerPoolSize:               *workerPoolSize,
+		DebugPort:                    *debugPort,
+		LogLevel:                     logLevel,
+		ListenAddr:                   *listenAddr,
+		KubeconfigCacheTTL:           *kubeconfigCacheTTL,
+		KubeconfigNotFoundTTL:        *kubeconfigNotFoundTTL,
+		FilterRuntimeFile:            *filterRuntimeFile,
+		RuntimeSource:                *runtimeSource,
+		RuntimeCRNamespace:           *runtimeCRNamespace,
+		RuntimeFile:                  *runtimeFile,
+		RuntimeFilePoll:              *runtimeFilePoll,
+		TrackingPolicyFile:           *trackingPolicyFile,
+		KubeconfigProvider:           *kubeconfigProvider,
+		KubeconfigLabelSelector:      *kubeconfigLabelSelector,
+		KubeconfigSecretNamespace:    *kubeconfigSecretNamespace,
+		KubeconfigSecretNameTemplate: *kubeconfigSecretNameTemplate,
+		KubeconfigSecretDataKey:      *kubeconfigSecretDataKey,
+		KubeconfigDir:                *kubeconfigDir,
+		GardenerKubeconfig:           *gardenerKubeconfig,
+		GardenerProjectNamespace:     *gardenerProjectNamespace,
+		GardenerKubeconfigExpiration: *gardenerKubeconfigExpiration,
+		ExcludeDeletingNodes:         *excludeDeletingNodes,
+		NotReadyNodeThreshold:        *notReadyNodeThreshold,
+		DetectGPUCapacity:            *detectGPUCapacity,
+		NodeVolumeProviders:          parseProviders(*nodeVolumeProviders),
+	}
+}
+
+// isFlagSet reports whether the flag was set on the command line.
+func isFlagSet(name string) bool {
+	set := false
+
+	flag.Visit(func(f *flag.Flag) {
+		set = set || f.Name == name
+	})
+
+	return set
+}
+
+// parseProviders parses a comma-separated list of providers and fails on unknown ones.
+func parseProviders(providers string) []string {
+	var parsed []string
+
+	for _, provider := range strings.Split(providers, ",") {
+		provider = strings.TrimSpace(provider)
+		if provider == "" {
+			continue
+		}
+
+		if !slices.Contains([]string{config.AWS, config.Azure, config.GCP, config.CCEE}, provider) {
+			log.Fatalf("unknown provider: %s", provider)
+		}
+
+		parsed = append(parsed, provider)
 	}
+
+	return parsed
 }
 
 func (o *Options) String() string {
 	return fmt.Sprintf("--scrape-interval=%v "+
-		"--worker-pool-size=%d --log-level=%s --listen-addr=%d, --debug-port=%d",
-		o.ScrapeInterval, o.WorkerPoolSize, o.LogLevel, o.ListenAddr, o.DebugPort)
+		"--worker-pool-size=%d --log-level=%s --listen-addr=%d, --debug-port=%d --runtime-source=%s --runtime-file=%s --kubeconfig-provider=%s",
+		o.ScrapeInterval, o.WorkerPoolSize, o.LogLevel, o.ListenAddr, o.DebugPort, o.RuntimeSource, o.RuntimeFile,
+		o.KubeconfigProvider)
 }
@@ -56,7 +56,10 @@ func (eClient Client) NewRequest(dataTenant string) (*http.Request, error) {
 
 	req.Header.Set(userAgentKeyHeader, userAgentKMC)
 	req.Header.Add(contentTypeKeyHeader, contentType)
-	req.Header.Add(authorizationKeyHeader, fmt.Sprintf("Bearer %s", eClient.Config.Token))
+
+	if eClient.Config.Token != "" {
+		req.Header.Add(authorizationKeyHeader, fmt.Sprintf("Bearer %s", eClient.Config.Token))
+	}
 
 	return req, nil
 }
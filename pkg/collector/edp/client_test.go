@@ -146,6 +146,16 @@ func TestClientRetry(t *testing.T) {
 	g.Expect(statusLabel.GetValue()).Should(gomega.Equal(fmt.Sprint(http.StatusInternalServerError)))
 }
 
+func TestClient_NewRequestWithoutToken(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	config := NewTestConfig("http://localhost")
+	config.Token = ""
+
+	req, err := NewClient(config, logger.NewLogger(zapcore.InfoLevel)).NewRequest(testTenant)
+	g.Expect(err).Should(gomega.BeNil())
+	g.Expect(req.Header).ShouldNot(gomega.HaveKey("Authorization"))
+}
+
 func NewTestConfig(url string) *Config {
 	return &Config{
 		URL:               url,
@@ -22,7 +22,7 @@ type Collector struct {
 	scanners  []resource.Scanner
 }
 
-var errNoMeasurementsSent = errors.New("no measurements sent to EDP")
+var errNoMeasurementsSent = fmt.Errorf("%w: no measurements to send to EDP", collector.ErrNotSent)
 
 var _ collector.CollectorSender = &Collector{}
 
@@ -70,11 +70,13 @@ func (c *Collector) CollectAndSend(ctx context.Context, runtime *runtime.Info, c
 		runtime.ShootName,
 		currentTimestamp,
 		EDPMeasurements,
+		specsVersionOf(scans),
+		c.usage(scans, previousScans),
 	)
 
 	err = c.sendPayload(payload, runtime.SubAccountID)
 	if err != nil {
-		errs = append(errs, fmt.Errorf("failed to send payload to EDP: %w", err))
+		errs = append(errs, fmt.Errorf("%w: failed to send payload to EDP: %w", collector.ErrNotSent, err))
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
 	}
@@ -159,6 +161,17 @@ func (c *Collector) convertScansToEDPMeasurements(currentScans collector.ScanMap
 	return convertableScans, EDPMeasurements, errors.Join(errs...)
 }
 
+// usage returns the time-weighted usage of the scans since the previous scans, or nil if it is disabled.
+func (c *Collector) usage(scans, previousScans collector.ScanMap) *resource.Usage {
+	if c.EDPClient == nil || c.EDPClient.Config == nil || !c.EDPClient.Config.TimeWeightedUsage {
+		return nil
+	}
+
+	usage := integrateUsage(scans, previousScans, c.EDPClient.Config.UsageMaxPeriod)
+
+	return &usage
+}
+
 // sendPayload sends the payload to the EDP backend.
 func (c *Collector) sendPayload(payload payload, subAccountID string) error {
 	payloadJSON, err := json.Marshal(payload)
@@ -422,6 +422,83 @@ func checkPrometheusMetrics(t *testing.T, scannerID1, scannerID2 resource.Scanne
 	require.InEpsilon(t, float64(1), testutil.ToFloat64(gotMetrics), kmctesting.Delta)
 }
 
+func TestSpecsVersionOf(t *testing.T) {
+	tests := []struct {
+		name     string
+		scans    collector.ScanMap
+		expected string
+	}{
+		{
+			name:     "no scans",
+			scans:    collector.ScanMap{},
+			expected: "",
+		},
+		{
+			name: "scans without version",
+			scans: collector.ScanMap{
+				"scanner1": stubs.Scan{},
+			},
+			expected: "",
+		},
+		{
+			name: "scans with the same version",
+			scans: collector.ScanMap{
+				"scanner1": stubs.Scan{Version: "2025-01"},
+				"scanner2": stubs.Scan{Version: "2025-01"},
+				"scanner3": stubs.Scan{},
+			},
+			expected: "2025-01",
+		},
+		{
+			name: "previous scan converted with an older version",
+			scans: collector.ScanMap{
+				"scanner1": stubs.Scan{Version: "2025-02"},
+				"scanner2": stubs.Scan{Version: "2025-01"},
+			},
+			expected: "2025-01,2025-02",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, specsVersionOf(tc.scans))
+		})
+	}
+}
+
+func TestAggregateEDPMeasurements_GPUs(t *testing.T) {
+	t.Run("without GPUs", func(t *testing.T) {
+		aggregated := aggregateEDPMeasurements([]resource.EDPMeasurement{{ProvisionedCPUs: 2}, {ProvisionedRAMGb: 8}})
+		require.Nil(t, aggregated.ProvisionedGPUs)
+	})
+
+	t.Run("with GPUs", func(t *testing.T) {
+		aggregated := aggregateEDPMeasurements([]resource.EDPMeasurement{
+			{ProvisionedGPUs: &resource.ProvisionedGPUs{Nodes: 1, Count: 1, MemoryGb: 16, Types: []resource.GPUType{{Name: "nvidia-t4", Count: 1}}}},
+			{ProvisionedCPUs: 2},
+			{ProvisionedGPUs: &resource.ProvisionedGPUs{Nodes: 2, Count: 3, Types: []resource.GPUType{{Name: "nvidia-t4", Count: 2}, {Name: "nvidia.com/gpu", Count: 1}}}},
+		})
+		require.Equal(t, &resource.ProvisionedGPUs{
+			Nodes:    3,
+			Count:    4,
+			MemoryGb: 16,
+			Types:    []resource.GPUType{{Name: "nvidia-t4", Count: 3}, {Name: "nvidia.com/gpu", Count: 1}},
+		}, aggregated.ProvisionedGPUs)
+	})
+}
+
+func TestAggregateEDPMeasurements_NodeCapacityTypes(t *testing.T) {
+	aggregated := aggregateEDPMeasurements([]resource.EDPMeasurement{{ProvisionedCPUs: 2}})
+	require.Nil(t, aggregated.NodeCapacityTypes)
+
+	aggregated = aggregateEDPMeasurements([]resource.EDPMeasurement{
+		{NodeCapacityTypes: &resource.NodeCapacityTypes{OnDemandNodes: 1, SpotNodes: 2, SpotDiscountCPUs: 1.5, SpotDiscountRAMGb: 6}},
+		{ProvisionedCPUs: 2},
+		{NodeCapacityTypes: &resource.NodeCapacityTypes{OnDemandNodes: 3, SpotNodes: 1, SpotDiscountCPUs: 0.5, SpotDiscountRAMGb: 2}},
+	})
+	require.Equal(t, &resource.NodeCapacityTypes{OnDemandNodes: 4, SpotNodes: 3, SpotDiscountCPUs: 2, SpotDiscountRAMGb: 8}, aggregated.NodeCapacityTypes)
+}
+
 func expectedHeadersInEDPReq() http.Header {
 	return http.Header{
 		"Authorization":   []string{fmt.Sprintf("Bearer %s", testToken)},
@@ -10,5 +10,12 @@ type Config struct {
 	DataStreamEnv     string        `default:"dev"                      envconfig:"EDP_DATASTREAM_ENV"     required:"true"`
 	Timeout           time.Duration `default:"30s"                      envconfig:"EDP_TIMEOUT"`
 	EventRetry        int           `default:"3"                        envconfig:"EDP_RETRY"`
-	Token             string
+	// TimeWeightedUsage adds the usage of the resources since the previous scan, e.g. node hours, to the payload.
+	TimeWeightedUsage bool `default:"false" envconfig:"EDP_TIME_WEIGHTED_USAGE"`
+	// UsageMaxPeriod is the longest period between two scans for which usage is added.
+	UsageMaxPeriod time.Duration `default:"1h" envconfig:"EDP_USAGE_MAX_PERIOD"`
+	// Token authenticates the requests to EDP. If it is not set, it is read from TokenFile. An empty TokenFile
+	// disables the authentication, e.g. to send to a local EDP mock.
+	Token     string `envconfig:"EDP_TOKEN"`
+	TokenFile string `default:"/edp-credentials/token" envconfig:"EDP_TOKEN_FILE"`
 }
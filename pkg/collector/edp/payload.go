@@ -1,6 +1,12 @@
 package edp
 
-import "github.com/kyma-project/kyma-metrics-collector/pkg/resource"
+import (
+	"slices"
+	"strings"
+
+	"github.com/kyma-project/kyma-metrics-collector/pkg/collector"
+	"github.com/kyma-project/kyma-metrics-collector/pkg/resource"
+)
 
 type payload struct {
 	RuntimeID    string                  `json:"runtime_id"           validate:"required"`
@@ -9,6 +15,9 @@ type payload struct {
 	Timestamp    string                  `json:"timestamp"            validate:"required"`
 	Compute      resource.EDPMeasurement `json:"compute"              validate:"required"`
 	Networking   *networking             `json:"networking,omitempty"`
+	SpecsVersion string                  `json:"specs_version,omitempty"`
+	// Usage is the time-weighted usage since the previous payload. It is only set if enabled in the config.
+	Usage *resource.Usage `json:"usage,omitempty"`
 }
 
 type networking struct {
@@ -16,7 +25,7 @@ type networking struct {
 	ProvisionedIPs   int `json:"provisioned_ips"   validate:"numeric"`
 }
 
-func newPayload(runtimeID, subAccountID, shootName, timeStamp string, EDPMeasuremnets []resource.EDPMeasurement) payload {
+func newPayload(runtimeID, subAccountID, shootName, timeStamp string, EDPMeasuremnets []resource.EDPMeasurement, specsVersion string, usage *resource.Usage) payload {
 	aggregatedEDPMeasurement := aggregateEDPMeasurements(EDPMeasuremnets)
 
 	return payload{
@@ -25,7 +34,30 @@ func newPayload(runtimeID, subAccountID, shootName, timeStamp string, EDPMeasure
 		ShootName:    shootName,
 		Timestamp:    timeStamp,
 		Compute:      aggregatedEDPMeasurement,
+		SpecsVersion: specsVersion,
+		Usage:        usage,
+	}
+}
+
+// specsVersionOf returns the versions of the public cloud specs applied to the scans.
+// Scans falling back to a previous scan can have been converted with an older version, so all distinct versions are joined.
+func specsVersionOf(scans collector.ScanMap) string {
+	var versions []string
+
+	for _, scan := range scans {
+		versioned, ok := scan.(resource.SpecsVersioned)
+		if !ok || versioned.SpecsVersion() == "" {
+			continue
+		}
+
+		if !slices.Contains(versions, versioned.SpecsVersion()) {
+			versions = append(versions, versioned.SpecsVersion())
+		}
 	}
+
+	slices.Sort(versions)
+
+	return strings.Join(versions, ",")
 }
 
 func aggregateEDPMeasurements(EDPMeasurements []resource.EDPMeasurement) resource.EDPMeasurement {
@@ -40,6 +72,25 @@ func aggregateEDPMeasurements(EDPMeasurements []resource.EDPMeasurement) resourc
 		aggregatedEDPMeasurement.ProvisionedVolumes.SizeGbTotal += m.ProvisionedVolumes.SizeGbTotal
 		aggregatedEDPMeasurement.ProvisionedVolumes.Count += m.ProvisionedVolumes.Count
 		aggregatedEDPMeasurement.ProvisionedVolumes.SizeGbRounded += m.ProvisionedVolumes.SizeGbRounded
+
+		if m.ProvisionedGPUs != nil {
+			if aggregatedEDPMeasurement.ProvisionedGPUs == nil {
+				aggregatedEDPMeasurement.ProvisionedGPUs = &resource.ProvisionedGPUs{}
+			}
+
+			aggregatedEDPMeasurement.ProvisionedGPUs.Add(*m.ProvisionedGPUs)
+		}
+
+		if m.NodeCapacityTypes != nil {
+			if aggregatedEDPMeasurement.NodeCapacityTypes == nil {
+				aggregatedEDPMeasurement.NodeCapacityTypes = &resource.NodeCapacityTypes{}
+			}
+
+			aggregatedEDPMeasurement.NodeCapacityTypes.OnDemandNodes += m.NodeCapacityTypes.OnDemandNodes
+			aggregatedEDPMeasurement.NodeCapacityTypes.SpotNodes += m.NodeCapacityTypes.SpotNodes
+			aggregatedEDPMeasurement.NodeCapacityTypes.SpotDiscountCPUs += m.NodeCapacityTypes.SpotDiscountCPUs
+			aggregatedEDPMeasurement.NodeCapacityTypes.SpotDiscountRAMGb += m.NodeCapacityTypes.SpotDiscountRAMGb
+		}
 	}
 
 	return aggregatedEDPMeasurement
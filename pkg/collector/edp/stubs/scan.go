@@ -9,6 +9,9 @@ import (
 type Scan struct {
 	EDPMeasurement resource.EDPMeasurement
 	EDPError       error
+	Version        string
+	At             time.Time
+	UsageRates     []resource.Rate
 }
 
 func NewScan(EDPMeasurement resource.EDPMeasurement, EDPError error) Scan {
@@ -25,3 +28,15 @@ func (s Scan) UM(duration time.Duration) (resource.UMMeasurement, error) {
 func (s Scan) EDP() (resource.EDPMeasurement, error) {
 	return s.EDPMeasurement, s.EDPError
 }
+
+func (s Scan) SpecsVersion() string {
+	return s.Version
+}
+
+func (s Scan) ScannedAt() time.Time {
+	return s.At
+}
+
+func (s Scan) Rates() ([]resource.Rate, error) {
+	return s.UsageRates, nil
+}
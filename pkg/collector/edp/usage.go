@@ -0,0 +1,47 @@
+package edp
+
+import (
+	"time"
+
+	"github.com/kyma-project/kyma-metrics-collector/pkg/collector"
+	"github.com/kyma-project/kyma-metrics-collector/pkg/resource"
+)
+
+// integrateUsage returns the usage of the resources of the scans since the previous scans of the runtime.
+// Scans which are not time weighted are ignored, and for every other scanner:
+//   - without a previous scan, e.g. for a new runtime or after a restart of KMC, the scan is only the baseline for the next one.
+//   - if the scan falls back to the previous scan, no usage is added. The next successful scan covers the gap.
+//   - periods longer than maxPeriod are capped to the last maxPeriod, as the resources in between are unknown.
+//
+// The scans were converted to EDP measurements with the same specs before, so resolving their rates does not fail.
+func integrateUsage(scans, previousScans collector.ScanMap, maxPeriod time.Duration) resource.Usage {
+	var usage resource.Usage
+
+	for id, scan := range scans {
+		current, ok := scan.(resource.TimeWeighted)
+		if !ok {
+			continue
+		}
+
+		previous, ok := previousScans[id].(resource.TimeWeighted)
+		if !ok {
+			continue
+		}
+
+		from, to := previous.ScannedAt(), current.ScannedAt()
+		if !to.After(from) {
+			continue
+		}
+
+		if maxPeriod > 0 && to.Sub(from) > maxPeriod {
+			from = to.Add(-maxPeriod)
+		}
+
+		previousRates, _ := previous.Rates()
+		currentRates, _ := current.Rates()
+
+		usage.Add(resource.Integrate(previousRates, currentRates, from, to))
+	}
+
+	return usage
+}
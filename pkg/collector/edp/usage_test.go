@@ -0,0 +1,74 @@
+package edp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kyma-project/kyma-metrics-collector/pkg/collector"
+	"github.com/kyma-project/kyma-metrics-collector/pkg/collector/edp/stubs"
+	"github.com/kyma-project/kyma-metrics-collector/pkg/resource"
+)
+
+func TestIntegrateUsage(t *testing.T) {
+	previousAt := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	nodes := []resource.Rate{{Key: "node", PerHour: resource.Usage{NodeHours: 1, CPUHours: 2, RAMGbHours: 8}}}
+	volumes := []resource.Rate{{Key: "volume", PerHour: resource.Usage{StorageGbHours: 10}}}
+
+	tests := []struct {
+		name          string
+		scans         collector.ScanMap
+		previousScans collector.ScanMap
+		expected      resource.Usage
+	}{
+		{
+			name: "no previous scans",
+			scans: collector.ScanMap{
+				"node": stubs.Scan{At: previousAt.Add(30 * time.Minute), UsageRates: nodes},
+			},
+			previousScans: collector.ScanMap{},
+			expected:      resource.Usage{},
+		},
+		{
+			name: "consecutive scans",
+			scans: collector.ScanMap{
+				"node":   stubs.Scan{At: previousAt.Add(30 * time.Minute), UsageRates: nodes},
+				"volume": stubs.Scan{At: previousAt.Add(30 * time.Minute), UsageRates: volumes},
+			},
+			previousScans: collector.ScanMap{
+				"node":   stubs.Scan{At: previousAt, UsageRates: nodes},
+				"volume": stubs.Scan{At: previousAt, UsageRates: volumes},
+			},
+			expected: resource.Usage{NodeHours: 0.5, CPUHours: 1, RAMGbHours: 4, StorageGbHours: 5},
+		},
+		{
+			name: "scan fell back to the previous scan",
+			scans: collector.ScanMap{
+				"node":   stubs.Scan{At: previousAt, UsageRates: nodes},
+				"volume": stubs.Scan{At: previousAt.Add(30 * time.Minute), UsageRates: volumes},
+			},
+			previousScans: collector.ScanMap{
+				"node":   stubs.Scan{At: previousAt, UsageRates: nodes},
+				"volume": stubs.Scan{At: previousAt, UsageRates: volumes},
+			},
+			expected: resource.Usage{StorageGbHours: 5},
+		},
+		{
+			name: "gap longer than the max period",
+			scans: collector.ScanMap{
+				"node": stubs.Scan{At: previousAt.Add(5 * time.Hour), UsageRates: nodes},
+			},
+			previousScans: collector.ScanMap{
+				"node": stubs.Scan{At: previousAt, UsageRates: nodes},
+			},
+			expected: resource.Usage{NodeHours: 1, CPUHours: 2, RAMGbHours: 8},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, integrateUsage(tc.scans, tc.previousScans, time.Hour))
+		})
+	}
+}
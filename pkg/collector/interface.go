@@ -2,6 +2,7 @@ package collector
 
 import (
 	"context"
+	"errors"
 
 	"github.com/kyma-project/kyma-metrics-collector/pkg/resource"
 	"github.com/kyma-project/kyma-metrics-collector/pkg/runtime"
@@ -9,7 +10,12 @@ import (
 
 type ScanMap map[resource.ScannerID]resource.ScanConverter
 
+// ErrNotSent is wrapped by the errors of CollectAndSend if no measures were sent to the backend.
+var ErrNotSent = errors.New("measures not sent")
+
 type CollectorSender interface {
 	// CollectAndSend collects and sends the measures to the backend. It returns the measures collected.
+	// Unless the error wraps ErrNotSent, the returned measures were sent, even if one or more scans failed and their
+	// previous scans were sent instead.
 	CollectAndSend(context context.Context, runtime *runtime.Info, clients runtime.Interface, previousScans ScanMap) (ScanMap, error)
 }
@@ -0,0 +1,218 @@
+// Package cli implements the specs subcommand of KMC to validate and diff public cloud specs files
+// before they are rolled out.
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/kyma-project/kyma-metrics-collector/pkg/config"
+)
+
+const (
+	// Command is the name of the subcommand of the KMC binary.
+	Command = "specs"
+
+	exitOK      = 0
+	exitInvalid = 1
+	exitUsage   = 2
+
+	usage = `Usage:
+  %[1]s validate [--at <RFC3339 time>] [--instance-types <file>] <specs file>
+      Validates a public cloud specs file. The optional instance types file lists the
+      VM types observed in the fleet as "<provider> <vm type>" per line, which must all be defined.
+  %[1]s diff [--at <RFC3339 time>] <old specs file> <new specs file>
+      Shows the added, removed and changed VM types and Redis tiers between two specs files.
+
+Specs files can be written in JSON or YAML. For versioned specs files, the version effective at --at (default: now) is used.
+`
+)
+
+// Run executes the specs subcommand with the given arguments and returns the exit code.
+func Run(name string, args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprintf(stderr, usage, name)
+		return exitUsage
+	}
+
+	switch args[0] {
+	case "validate":
+		return runValidate(name, args[1:], stdout, stderr)
+	case "diff":
+		return runDiff(name, args[1:], stdout, stderr)
+	default:
+		fmt.Fprintf(stderr, "unknown command %q\n", args[0])
+		fmt.Fprintf(stderr, usage, name)
+
+		return exitUsage
+	}
+}
+
+func runValidate(name string, args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet(name+" validate", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	at := flags.String("at", "", "The time at which the specs are validated in RFC3339 format (default: now)")
+	instanceTypesFile := flags.String("instance-types", "", "File listing the observed VM types as '<provider> <vm type>' per line")
+
+	if err := flags.Parse(args); err != nil || flags.NArg() != 1 {
+		fmt.Fprintf(stderr, usage, name)
+		return exitUsage
+	}
+
+	timestamp, err := parseTime(*at)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitUsage
+	}
+
+	specsJSON, err := config.ReadPublicCloudSpecsFile(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitUsage
+	}
+
+	errs := config.ValidatePublicCloudSpecs(specsJSON, timestamp)
+
+	if *instanceTypesFile != "" && len(errs) == 0 {
+		unknown, err := checkInstanceTypes(specsJSON, *instanceTypesFile, timestamp)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitUsage
+		}
+
+		for _, vmType := range unknown {
+			errs = append(errs, fmt.Errorf("observed VM type %s is not defined", vmType))
+		}
+	}
+
+	if len(errs) > 0 {
+		for _, err := range errs {
+			fmt.Fprintln(stdout, err)
+		}
+
+		return exitInvalid
+	}
+
+	fmt.Fprintf(stdout, "%s is valid\n", flags.Arg(0))
+
+	return exitOK
+}
+
+func runDiff(name string, args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet(name+" diff", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	at := flags.String("at", "", "The time at which the effective versions are compared in RFC3339 format (default: now)")
+
+	if err := flags.Parse(args); err != nil || flags.NArg() != 2 { //nolint:mnd // old and new specs file
+		fmt.Fprintf(stderr, usage, name)
+		return exitUsage
+	}
+
+	timestamp, err := parseTime(*at)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitUsage
+	}
+
+	oldSpecs, err := loadSpecs(flags.Arg(0), timestamp)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitInvalid
+	}
+
+	newSpecs, err := loadSpecs(flags.Arg(1), timestamp)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitInvalid
+	}
+
+	for _, change := range config.DiffPublicCloudSpecs(oldSpecs.At(timestamp), newSpecs.At(timestamp)) {
+		fmt.Fprintln(stdout, change)
+	}
+
+	return exitOK
+}
+
+func loadSpecs(file string, timestamp time.Time) (*config.PublicCloudSpecs, error) {
+	specsJSON, err := config.ReadPublicCloudSpecsFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read public cloud specs file %s: %w", file, err)
+	}
+
+	specs, err := config.ParsePublicCloudSpecs(specsJSON, timestamp)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public cloud specs file %s: %w", file, err)
+	}
+
+	return specs, nil
+}
+
+// checkInstanceTypes returns the observed VM types listed in the file which are not defined in the specs.
+func checkInstanceTypes(specsJSON []byte, file string, timestamp time.Time) ([]string, error) {
+	specs, err := config.ParsePublicCloudSpecs(specsJSON, timestamp)
+	if err != nil {
+		return nil, err
+	}
+
+	observed, err := readInstanceTypes(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var unknown []string
+
+	for provider, vmTypes := range observed {
+		for _, vmType := range specs.At(timestamp).UnknownVMTypes(provider, vmTypes) {
+			unknown = append(unknown, provider+" "+vmType)
+		}
+	}
+
+	return unknown, nil
+}
+
+// readInstanceTypes reads the observed VM types per provider. Empty lines and lines starting with # are ignored.
+func readInstanceTypes(file string) (map[string][]string, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read instance types file: %w", err)
+	}
+
+	observed := make(map[string][]string)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 2 { //nolint:mnd // provider and VM type
+			return nil, fmt.Errorf("instance types file line %d: expected '<provider> <vm type>', got %q", lineNum, line)
+		}
+
+		provider := strings.ToLower(fields[0])
+		observed[provider] = append(observed[provider], fields[1])
+	}
+
+	return observed, scanner.Err()
+}
+
+func parseTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Now(), nil
+	}
+
+	timestamp, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q: %w", value, err)
+	}
+
+	return timestamp, nil
+}
@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testPublicCloudSpecsPath           = "../../testing/fixtures/public_cloud_specs_small.json"
+	testPublicCloudSpecsPathFractional = "../../testing/fixtures/public_cloud_specs_fractional.json"
+	testPublicCloudSpecsPathYAML       = "../../testing/fixtures/public_cloud_specs_small.yaml"
+)
+
+func TestRun(t *testing.T) {
+	instanceTypesFile := filepath.Join(t.TempDir(), "instance-types")
+	err := os.WriteFile(instanceTypesFile, []byte("# observed in the fleet\naws m4.large\nAWS M5.Large\n\ngcp n1-standard-4\n"), 0o600)
+	require.NoError(t, err)
+
+	invalidInstanceTypesFile := filepath.Join(t.TempDir(), "invalid-instance-types")
+	err = os.WriteFile(invalidInstanceTypesFile, []byte("m4.large\n"), 0o600)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name             string
+		args             []string
+		expectedExitCode int
+		expectedStdout   string
+	}{
+		{
+			name:             "no command",
+			args:             []string{},
+			expectedExitCode: exitUsage,
+		},
+		{
+			name:             "unknown command",
+			args:             []string{"foo"},
+			expectedExitCode: exitUsage,
+		},
+		{
+			name:             "validate valid specs",
+			args:             []string{"validate", testPublicCloudSpecsPath},
+			expectedExitCode: exitOK,
+			expectedStdout:   testPublicCloudSpecsPath + " is valid\n",
+		},
+		{
+			name:             "validate valid YAML specs",
+			args:             []string{"validate", testPublicCloudSpecsPathYAML},
+			expectedExitCode: exitOK,
+			expectedStdout:   testPublicCloudSpecsPathYAML + " is valid\n",
+		},
+		{
+			name:             "diff JSON and YAML specs without changes",
+			args:             []string{"diff", testPublicCloudSpecsPath, testPublicCloudSpecsPathYAML},
+			expectedExitCode: exitOK,
+		},
+		{
+			name:             "validate missing specs file",
+			args:             []string{"validate", "does-not-exist.json"},
+			expectedExitCode: exitUsage,
+		},
+		{
+			name:             "validate with unknown observed instance types",
+			args:             []string{"validate", "--instance-types", instanceTypesFile, testPublicCloudSpecsPath},
+			expectedExitCode: exitInvalid,
+			expectedStdout:   "observed VM type aws m5.large is not defined\n",
+		},
+		{
+			name:             "validate with invalid instance types file",
+			args:             []string{"validate", "--instance-types", invalidInstanceTypesFile, testPublicCloudSpecsPath},
+			expectedExitCode: exitUsage,
+		},
+		{
+			name:             "validate with invalid time",
+			args:             []string{"validate", "--at", "yesterday", testPublicCloudSpecsPath},
+			expectedExitCode: exitUsage,
+		},
+		{
+			name:             "diff",
+			args:             []string{"diff", testPublicCloudSpecsPath, testPublicCloudSpecsPathFractional},
+			expectedExitCode: exitOK,
+			expectedStdout: "~ aws m4.large: cpu_cores=2 memory=8 -> cpu_cores=2.2 memory=8.2\n" +
+				"~ azure standard_a1_v2: cpu_cores=1 memory=2 -> cpu_cores=1.1 memory=2.1\n" +
+				"~ gcp n1-standard-4: cpu_cores=4 memory=15 -> cpu_cores=4.3 memory=15.3\n" +
+				"~ sapconvergedcloud g_c12_m48: cpu_cores=12 memory=48 -> cpu_cores=12.4 memory=48.4\n",
+		},
+		{
+			name:             "diff of identical specs",
+			args:             []string{"diff", testPublicCloudSpecsPath, testPublicCloudSpecsPath},
+			expectedExitCode: exitOK,
+		},
+		{
+			name:             "diff with a single file",
+			args:             []string{"diff", testPublicCloudSpecsPath},
+			expectedExitCode: exitUsage,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+
+			exitCode := Run("kmc specs", tc.args, &stdout, &stderr)
+			require.Equal(t, tc.expectedExitCode, exitCode, stderr.String())
+			require.Equal(t, tc.expectedStdout, stdout.String())
+		})
+	}
+}
@@ -0,0 +1,27 @@
+// Command schemagen writes the JSON schema of the public cloud specs file to the given path.
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/kyma-project/kyma-metrics-collector/pkg/config"
+)
+
+func main() {
+	if len(os.Args) != 2 { //nolint:mnd // command and output path
+		fmt.Fprintln(os.Stderr, "usage: schemagen <output file>")
+		os.Exit(2) //nolint:mnd // usage error
+	}
+
+	schemaJSON, err := config.GenerateJSONSchema()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	if err := os.WriteFile(os.Args[1], schemaJSON, 0o644); err != nil { //nolint:gosec // the schema is public
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -1,11 +1,16 @@
 package config
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
 
 	"github.com/kyma-project/kyma-metrics-collector/env"
 )
@@ -17,9 +22,29 @@ const (
 	CCEE  = "sapconvergedcloud"
 )
 
+// PublicCloudSpecs contains the features of the VM types and the prices of the Redis tiers.
+// The specs either define the values directly, or contain multiple versions which become effective at a scheduled instant.
 type PublicCloudSpecs struct {
-	Providers Providers            `json:"providers"`
-	Redis     map[string]RedisInfo `json:"redis_tiers"`
+	Version       string               `json:"version,omitempty"`
+	EffectiveFrom time.Time            `json:"effective_from,omitzero"`
+	Providers     Providers            `json:"providers"`
+	Redis         map[string]RedisInfo `json:"redis_tiers"`
+	// SpotDiscounts are the discounts of spot and preemptible nodes per provider, as fraction of the CPU and memory of their VM type.
+	SpotDiscounts map[string]float64 `json:"spot_discounts,omitempty"`
+	Overrides     []Override         `json:"overrides,omitempty"`
+	Versions      []PublicCloudSpecs `json:"versions,omitempty"`
+}
+
+// Override replaces the default VM type features, Redis tier prices and spot discount of a provider for runtimes
+// in a specific region, with a specific plan, or both. Region and plan are optional, but at least one must be set.
+type Override struct {
+	Provider string               `json:"provider" jsonschema:"required,enum=azure,enum=aws,enum=gcp,enum=sapconvergedcloud"`
+	Region   string               `json:"region,omitempty"`
+	Plan     string               `json:"plan,omitempty"`
+	VMTypes  map[string]Feature   `json:"vm_types,omitempty"`
+	Redis    map[string]RedisInfo `json:"redis_tiers,omitempty"`
+	// SpotDiscount replaces the spot discount of the provider if set.
+	SpotDiscount *float64 `json:"spot_discount,omitempty"`
 }
 
 type Providers struct {
@@ -29,14 +54,68 @@ type Providers struct {
 	OpenStack map[string]Feature `json:"sapconvergedcloud"`
 }
 
+// byName returns the VM types of every provider keyed by the provider name used in the specs file.
+func (p Providers) byName() map[string]map[string]Feature {
+	return map[string]map[string]Feature{
+		Azure: p.Azure,
+		AWS:   p.AWS,
+		GCP:   p.GCP,
+		CCEE:  p.OpenStack,
+	}
+}
+
+func (p Providers) hasVMTypes() bool {
+	return len(p.Azure) > 0 || len(p.AWS) > 0 || len(p.GCP) > 0 || len(p.OpenStack) > 0
+}
+
 type Feature struct {
-	CpuCores float64 `json:"cpu_cores"`
-	Memory   float64 `json:"memory"`
+	CpuCores float64 `json:"cpu_cores" jsonschema:"required,minimum=0"`
+	Memory   float64 `json:"memory"    jsonschema:"required,minimum=0"`
+	// AcceleratorType, AcceleratorCount and AcceleratorMemory describe the GPUs of GPU VM types.
+	// AcceleratorMemory is the memory of a single accelerator in GB.
+	AcceleratorType   string  `json:"accelerator_type,omitempty"`
+	AcceleratorCount  int     `json:"accelerator_count,omitempty"  jsonschema:"minimum=0"`
+	AcceleratorMemory float64 `json:"accelerator_memory,omitempty" jsonschema:"minimum=0"`
+	// VolumeSizeGb is the size of the root volume of the nodes in GB, which is billed for the providers with node volumes enabled.
+	VolumeSizeGb int64 `json:"volume_size_gb,omitempty" jsonschema:"minimum=0"`
 }
 
 type RedisInfo struct {
-	PriceStorageGB     int `json:"price_storage_gb"`
-	PriceCapacityUnits int `json:"price_cu"`
+	PriceStorageGB     int `json:"price_storage_gb" jsonschema:"required,minimum=0"`
+	PriceCapacityUnits int `json:"price_cu"         jsonschema:"required,minimum=0"`
+}
+
+// At returns the specification version effective at the given time, which is the version with the latest
+// effective_from not after the timestamp. If no version is effective yet, the earliest version is returned.
+// Unversioned specs are effective at any time and return themselves.
+func (pcs *PublicCloudSpecs) At(timestamp time.Time) *PublicCloudSpecs {
+	if len(pcs.Versions) == 0 {
+		return pcs
+	}
+
+	var active, earliest *PublicCloudSpecs
+
+	for i := range pcs.Versions {
+		version := &pcs.Versions[i]
+
+		if earliest == nil || version.EffectiveFrom.Before(earliest.EffectiveFrom) {
+			earliest = version
+		}
+
+		if version.EffectiveFrom.After(timestamp) {
+			continue
+		}
+
+		if active == nil || version.EffectiveFrom.After(active.EffectiveFrom) {
+			active = version
+		}
+	}
+
+	if active == nil {
+		return earliest
+	}
+
+	return active
 }
 
 func (pcs *PublicCloudSpecs) GetFeature(cloudProvider, vmType string) *Feature {
@@ -70,41 +149,237 @@ func (pcs *PublicCloudSpecs) GetRedisInfo(tier string) *RedisInfo {
 	return nil
 }
 
-// LoadPublicCloudSpecs loads string data to Providers object from an env var.
+// ResolveFeature returns the feature of the VM type for a runtime in the given region and plan.
+// The overrides are resolved in the following order, falling back to the provider defaults of GetFeature:
+// 1. provider, region and plan
+// 2. provider and plan
+// 3. provider and region.
+func (pcs *PublicCloudSpecs) ResolveFeature(cloudProvider, region, plan, vmType string) *Feature {
+	for _, override := range pcs.matchingOverrides(cloudProvider, region, plan) {
+		if feature, ok := override.VMTypes[vmType]; ok {
+			return &feature
+		}
+	}
+
+	return pcs.GetFeature(cloudProvider, vmType)
+}
+
+// ResolveSpotDiscount returns the discount of spot and preemptible nodes for a runtime in the given region and plan,
+// or 0 if they are not discounted. The overrides are resolved in the same order as in ResolveFeature, falling back
+// to the discount of the provider.
+func (pcs *PublicCloudSpecs) ResolveSpotDiscount(cloudProvider, region, plan string) float64 {
+	for _, override := range pcs.matchingOverrides(cloudProvider, region, plan) {
+		if override.SpotDiscount != nil {
+			return *override.SpotDiscount
+		}
+	}
+
+	return pcs.SpotDiscounts[cloudProvider]
+}
+
+// ResolveRedisInfo returns the Redis tier prices for a runtime in the given region and plan.
+// The overrides are resolved in the same order as in ResolveFeature, falling back to the defaults of GetRedisInfo.
+func (pcs *PublicCloudSpecs) ResolveRedisInfo(cloudProvider, region, plan, tier string) *RedisInfo {
+	for _, override := range pcs.matchingOverrides(cloudProvider, region, plan) {
+		if redisInfo, ok := override.Redis[tier]; ok {
+			return &redisInfo
+		}
+	}
+
+	return pcs.GetRedisInfo(tier)
+}
+
+// matchingOverrides returns the overrides matching the provider, region and plan ordered by precedence.
+func (pcs *PublicCloudSpecs) matchingOverrides(cloudProvider, region, plan string) []Override {
+	var regionAndPlan, planOnly, regionOnly []Override
+
+	for _, override := range pcs.Overrides {
+		if override.Provider != cloudProvider {
+			continue
+		}
+
+		switch {
+		case override.Region != "" && override.Plan != "":
+			if override.Region == region && override.Plan == plan {
+				regionAndPlan = append(regionAndPlan, override)
+			}
+		case override.Plan != "":
+			if override.Plan == plan {
+				planOnly = append(planOnly, override)
+			}
+		case override.Region != "":
+			if override.Region == region {
+				regionOnly = append(regionOnly, override)
+			}
+		}
+	}
+
+	return append(append(regionAndPlan, planOnly...), regionOnly...)
+}
+
+// LoadPublicCloudSpecs loads the public cloud specs from the JSON or YAML file configured in the env var.
 func LoadPublicCloudSpecs(cfg *env.Config) (*PublicCloudSpecs, error) {
 	if cfg.PublicCloudSpecsPath == "" {
 		return nil, fmt.Errorf("public cloud specification path is not configured")
 	}
 
-	specsJSON, err := os.ReadFile(cfg.PublicCloudSpecsPath)
+	specsJSON, err := ReadPublicCloudSpecsFile(cfg.PublicCloudSpecsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParsePublicCloudSpecs(specsJSON, time.Now())
+}
+
+// ReadPublicCloudSpecsFile reads a public cloud specs file and returns its content as JSON.
+// Files with a .yaml or .yml extension are converted from YAML, files with a .json extension are returned as they are.
+// For any other extension, the format is detected from the content.
+func ReadPublicCloudSpecsFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to read public cloud specs file")
 	}
 
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return data, nil
+	case ".yaml", ".yml":
+		return yamlToJSON(data)
+	}
+
+	if isJSON(data) {
+		return data, nil
+	}
+
+	return yamlToJSON(data)
+}
+
+// yamlToJSON converts YAML specs to JSON. Duplicate keys are rejected, as YAML would silently keep the last value.
+func yamlToJSON(data []byte) ([]byte, error) {
+	specsJSON, err := yaml.YAMLToJSONStrict(data)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to convert public cloud specs from YAML")
+	}
+
+	return specsJSON, nil
+}
+
+// isJSON reports whether the content is a JSON object. JSON is valid YAML, but the JSON parser reports
+// more precise errors, so it is preferred for content starting like a JSON object.
+func isJSON(data []byte) bool {
+	return bytes.HasPrefix(bytes.TrimSpace(data), []byte("{"))
+}
+
+// ParsePublicCloudSpecs parses and validates the content of a public cloud specs file in JSON format.
+// The content is validated against the JSON schema of the specs. Versioned specs must contain a version
+// effective at the given time.
+func ParsePublicCloudSpecs(specsJSON []byte, now time.Time) (*PublicCloudSpecs, error) {
 	var specs PublicCloudSpecs
-	if err = json.Unmarshal(specsJSON, &specs); err != nil {
+	if err := json.Unmarshal(specsJSON, &specs); err != nil {
 		return nil, errors.Wrapf(err, "failed to unmarshal public cloud specs")
 	}
 
-	if len(specs.Redis) == 0 {
-		return nil, fmt.Errorf("public cloud specs do not contain Redis tiers")
+	if err := validateSchema(specsJSON); err != nil {
+		return nil, err
 	}
 
-	if len(specs.Providers.AWS) == 0 {
-		return nil, fmt.Errorf("public cloud specs do not contain AWS VM types")
+	if err := specs.validateSpecs(now); err != nil {
+		return nil, err
 	}
 
-	if len(specs.Providers.Azure) == 0 {
-		return nil, fmt.Errorf("public cloud specs do not contain Azure VM types")
+	return &specs, nil
+}
+
+// validateSpecs checks the content of unversioned or versioned specs which cannot be expressed in the JSON schema.
+func (pcs *PublicCloudSpecs) validateSpecs(now time.Time) error {
+	if len(pcs.Versions) == 0 {
+		return pcs.validate()
 	}
 
-	if len(specs.Providers.GCP) == 0 {
-		return nil, fmt.Errorf("public cloud specs do not contain GCP VM types")
+	return pcs.validateVersions(now)
+}
+
+// validate checks that a single specification version contains all required values.
+func (pcs *PublicCloudSpecs) validate() error {
+	if len(pcs.Redis) == 0 {
+		return fmt.Errorf("public cloud specs do not contain Redis tiers")
 	}
 
-	if len(specs.Providers.OpenStack) == 0 {
-		return nil, fmt.Errorf("public cloud specs do not contain OpenStack VM types")
+	if len(pcs.Providers.AWS) == 0 {
+		return fmt.Errorf("public cloud specs do not contain AWS VM types")
 	}
 
-	return &specs, nil
+	if len(pcs.Providers.Azure) == 0 {
+		return fmt.Errorf("public cloud specs do not contain Azure VM types")
+	}
+
+	if len(pcs.Providers.GCP) == 0 {
+		return fmt.Errorf("public cloud specs do not contain GCP VM types")
+	}
+
+	if len(pcs.Providers.OpenStack) == 0 {
+		return fmt.Errorf("public cloud specs do not contain OpenStack VM types")
+	}
+
+	for provider, discount := range pcs.SpotDiscounts {
+		if discount < 0 || discount > 1 {
+			return fmt.Errorf("public cloud specs spot discount %v of provider %s is not between 0 and 1", discount, provider)
+		}
+	}
+
+	for i, override := range pcs.Overrides {
+		if override.Provider == "" {
+			return fmt.Errorf("public cloud specs override %d does not define a provider", i)
+		}
+
+		if override.Region == "" && override.Plan == "" {
+			return fmt.Errorf("public cloud specs override %d for provider %s defines neither a region nor a plan", i, override.Provider)
+		}
+
+		if override.SpotDiscount != nil && (*override.SpotDiscount < 0 || *override.SpotDiscount > 1) {
+			return fmt.Errorf("public cloud specs override %d spot discount %v is not between 0 and 1", i, *override.SpotDiscount)
+		}
+	}
+
+	return nil
+}
+
+// validateVersions checks that versioned specs only define values within their versions,
+// that every version is valid and identifiable, and that one version is effective at the given time.
+func (pcs *PublicCloudSpecs) validateVersions(now time.Time) error {
+	if len(pcs.Redis) > 0 || pcs.Providers.hasVMTypes() || len(pcs.Overrides) > 0 || len(pcs.SpotDiscounts) > 0 {
+		return fmt.Errorf("versioned public cloud specs must not define providers, Redis tiers, spot discounts or overrides outside of versions")
+	}
+
+	versions := make(map[string]struct{})
+
+	for i, version := range pcs.Versions {
+		if version.Version == "" {
+			return fmt.Errorf("public cloud specs version %d does not define a version name", i)
+		}
+
+		if _, ok := versions[version.Version]; ok {
+			return fmt.Errorf("public cloud specs version %s is defined more than once", version.Version)
+		}
+
+		versions[version.Version] = struct{}{}
+
+		if version.EffectiveFrom.IsZero() {
+			return fmt.Errorf("public cloud specs version %s does not define effective_from", version.Version)
+		}
+
+		if len(version.Versions) > 0 {
+			return fmt.Errorf("public cloud specs version %s must not contain nested versions", version.Version)
+		}
+
+		if err := version.validate(); err != nil {
+			return fmt.Errorf("public cloud specs version %s is invalid: %w", version.Version, err)
+		}
+	}
+
+	if pcs.At(now).EffectiveFrom.After(now) {
+		return fmt.Errorf("public cloud specs do not contain a version effective at %s", now.Format(time.RFC3339))
+	}
+
+	return nil
 }
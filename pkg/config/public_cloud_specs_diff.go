@@ -0,0 +1,125 @@
+package config
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+type ChangeType string
+
+const (
+	ChangeAdded   ChangeType = "added"
+	ChangeRemoved ChangeType = "removed"
+	ChangeChanged ChangeType = "changed"
+
+	redisTiersSection    = "redis_tiers"
+	spotDiscountsSection = "spot_discounts"
+)
+
+// SpecsChange describes a single difference of a VM type, Redis tier or spot discount between two public cloud specs.
+type SpecsChange struct {
+	Type ChangeType
+	// Section is either the name of the provider, redis_tiers or spot_discounts.
+	Section string
+	Key     string
+	Old     string
+	New     string
+}
+
+func (c SpecsChange) String() string {
+	switch c.Type {
+	case ChangeAdded:
+		return fmt.Sprintf("+ %s %s: %s", c.Section, c.Key, c.New)
+	case ChangeRemoved:
+		return fmt.Sprintf("- %s %s: %s", c.Section, c.Key, c.Old)
+	case ChangeChanged:
+		return fmt.Sprintf("~ %s %s: %s -> %s", c.Section, c.Key, c.Old, c.New)
+	}
+
+	return ""
+}
+
+// DiffPublicCloudSpecs returns the added, removed and changed VM types, Redis tiers and spot discounts between two specification versions,
+// sorted by section and key. Versioned specs must be resolved with At before.
+func DiffPublicCloudSpecs(oldSpecs, newSpecs *PublicCloudSpecs) []SpecsChange {
+	var changes []SpecsChange
+
+	oldProviders := oldSpecs.Providers.byName()
+	newProviders := newSpecs.Providers.byName()
+
+	for provider := range oldProviders {
+		changes = append(changes, diffMaps(provider, oldProviders[provider], newProviders[provider], formatFeature)...)
+	}
+
+	changes = append(changes, diffMaps(redisTiersSection, oldSpecs.Redis, newSpecs.Redis, formatRedisInfo)...)
+	changes = append(changes, diffMaps(spotDiscountsSection, oldSpecs.SpotDiscounts, newSpecs.SpotDiscounts, formatSpotDiscount)...)
+
+	slices.SortFunc(changes, func(a, b SpecsChange) int {
+		return cmp.Or(strings.Compare(a.Section, b.Section), strings.Compare(a.Key, b.Key))
+	})
+
+	return changes
+}
+
+func diffMaps[V comparable](section string, oldValues, newValues map[string]V, format func(V) string) []SpecsChange {
+	var changes []SpecsChange
+
+	for key, oldValue := range oldValues {
+		newValue, ok := newValues[key]
+
+		switch {
+		case !ok:
+			changes = append(changes, SpecsChange{Type: ChangeRemoved, Section: section, Key: key, Old: format(oldValue)})
+		case oldValue != newValue:
+			changes = append(changes, SpecsChange{Type: ChangeChanged, Section: section, Key: key, Old: format(oldValue), New: format(newValue)})
+		}
+	}
+
+	for key, newValue := range newValues {
+		if _, ok := oldValues[key]; !ok {
+			changes = append(changes, SpecsChange{Type: ChangeAdded, Section: section, Key: key, New: format(newValue)})
+		}
+	}
+
+	return changes
+}
+
+func formatFeature(feature Feature) string {
+	formatted := fmt.Sprintf("cpu_cores=%v memory=%v", feature.CpuCores, feature.Memory)
+
+	if feature.AcceleratorCount > 0 {
+		formatted += fmt.Sprintf(" accelerator_type=%s accelerator_count=%d accelerator_memory=%v",
+			feature.AcceleratorType, feature.AcceleratorCount, feature.AcceleratorMemory)
+	}
+
+	if feature.VolumeSizeGb > 0 {
+		formatted += fmt.Sprintf(" volume_size_gb=%d", feature.VolumeSizeGb)
+	}
+
+	return formatted
+}
+
+func formatSpotDiscount(discount float64) string {
+	return fmt.Sprintf("discount=%v", discount)
+}
+
+func formatRedisInfo(info RedisInfo) string {
+	return fmt.Sprintf("price_storage_gb=%d price_cu=%d", info.PriceStorageGB, info.PriceCapacityUnits)
+}
+
+// UnknownVMTypes returns the VM types of the given provider which are not defined in the specs.
+// The VM types are lowercased the same way as by the node scanner.
+func (pcs *PublicCloudSpecs) UnknownVMTypes(cloudProvider string, vmTypes []string) []string {
+	var unknown []string
+
+	for _, vmType := range vmTypes {
+		vmType = strings.ToLower(vmType)
+		if pcs.GetFeature(cloudProvider, vmType) == nil && !slices.Contains(unknown, vmType) {
+			unknown = append(unknown, vmType)
+		}
+	}
+
+	return unknown
+}
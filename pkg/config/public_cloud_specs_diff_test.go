@@ -0,0 +1,76 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffPublicCloudSpecs(t *testing.T) {
+	oldSpecs := &PublicCloudSpecs{
+		Providers: Providers{
+			AWS: map[string]Feature{
+				"m4.large": {CpuCores: 2, Memory: 8},
+				"m5.large": {CpuCores: 2, Memory: 8},
+			},
+			Azure: map[string]Feature{
+				"standard_a1_v2": {CpuCores: 1, Memory: 2},
+			},
+		},
+		Redis: map[string]RedisInfo{
+			"S1": {PriceStorageGB: 182, PriceCapacityUnits: 74},
+			"S2": {PriceStorageGB: 364, PriceCapacityUnits: 148},
+		},
+		SpotDiscounts: map[string]float64{"gcp": 0.5},
+	}
+	newSpecs := &PublicCloudSpecs{
+		Providers: Providers{
+			AWS: map[string]Feature{
+				"m5.large":  {CpuCores: 2, Memory: 8},
+				"m5.xlarge": {CpuCores: 4, Memory: 16, VolumeSizeGb: 80},
+			},
+			Azure: map[string]Feature{
+				"standard_a1_v2": {CpuCores: 1, Memory: 2.5},
+			},
+		},
+		Redis: map[string]RedisInfo{
+			"S1": {PriceStorageGB: 200, PriceCapacityUnits: 74},
+			"S2": {PriceStorageGB: 364, PriceCapacityUnits: 148},
+			"P1": {PriceStorageGB: 1903, PriceCapacityUnits: 773},
+		},
+		SpotDiscounts: map[string]float64{"aws": 0.6, "gcp": 0.7},
+	}
+
+	changes := DiffPublicCloudSpecs(oldSpecs, newSpecs)
+
+	var got []string
+	for _, change := range changes {
+		got = append(got, change.String())
+	}
+
+	require.Equal(t, []string{
+		"- aws m4.large: cpu_cores=2 memory=8",
+		"+ aws m5.xlarge: cpu_cores=4 memory=16 volume_size_gb=80",
+		"~ azure standard_a1_v2: cpu_cores=1 memory=2 -> cpu_cores=1 memory=2.5",
+		"+ redis_tiers P1: price_storage_gb=1903 price_cu=773",
+		"~ redis_tiers S1: price_storage_gb=182 price_cu=74 -> price_storage_gb=200 price_cu=74",
+		"+ spot_discounts aws: discount=0.6",
+		"~ spot_discounts gcp: discount=0.5 -> discount=0.7",
+	}, got)
+
+	require.Empty(t, DiffPublicCloudSpecs(oldSpecs, oldSpecs))
+}
+
+func TestUnknownVMTypes(t *testing.T) {
+	specs := &PublicCloudSpecs{
+		Providers: Providers{
+			AWS: map[string]Feature{
+				"m5.large": {CpuCores: 2, Memory: 8},
+			},
+		},
+	}
+
+	require.Empty(t, specs.UnknownVMTypes(AWS, []string{"m5.large", "M5.Large"}))
+	require.Equal(t, []string{"m5.xlarge"}, specs.UnknownVMTypes(AWS, []string{"m5.large", "m5.xlarge", "M5.XLarge"}))
+	require.Equal(t, []string{"m5.large"}, specs.UnknownVMTypes(GCP, []string{"m5.large"}))
+}
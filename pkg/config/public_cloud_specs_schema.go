@@ -0,0 +1,76 @@
+package config
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/invopop/jsonschema"
+	jsonschemavalidator "github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+//go:generate go run ./internal/schemagen public_cloud_specs.schema.json
+
+const publicCloudSpecsSchemaID = "https://github.com/kyma-project/kyma-metrics-collector/pkg/config/public_cloud_specs.schema.json"
+
+// publicCloudSpecsSchema is the JSON schema generated from PublicCloudSpecs by GenerateJSONSchema.
+// It is published next to the source so that editors can validate specs files while they are written.
+//
+//go:embed public_cloud_specs.schema.json
+var publicCloudSpecsSchema []byte
+
+var compileSchema = sync.OnceValues(func() (*jsonschemavalidator.Schema, error) {
+	doc, err := jsonschemavalidator.UnmarshalJSON(bytes.NewReader(publicCloudSpecsSchema))
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal public cloud specs schema: %w", err)
+	}
+
+	compiler := jsonschemavalidator.NewCompiler()
+	compiler.AssertFormat()
+
+	if err := compiler.AddResource(publicCloudSpecsSchemaID, doc); err != nil {
+		return nil, fmt.Errorf("failed to add public cloud specs schema: %w", err)
+	}
+
+	return compiler.Compile(publicCloudSpecsSchemaID)
+})
+
+// GenerateJSONSchema generates the JSON schema of the public cloud specs file from PublicCloudSpecs.
+func GenerateJSONSchema() ([]byte, error) {
+	reflector := &jsonschema.Reflector{
+		RequiredFromJSONSchemaTags: true,
+	}
+
+	schema := reflector.Reflect(&PublicCloudSpecs{})
+	schema.ID = publicCloudSpecsSchemaID
+	schema.Title = "Public cloud specs"
+	schema.Description = "Features of the VM types and prices of the Redis tiers used by KMC to calculate the consumption of runtimes."
+
+	schemaJSON, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal public cloud specs schema: %w", err)
+	}
+
+	return append(schemaJSON, '\n'), nil
+}
+
+// validateSchema validates the JSON content of a public cloud specs file against the published JSON schema.
+func validateSchema(specsJSON []byte) error {
+	schema, err := compileSchema()
+	if err != nil {
+		return err
+	}
+
+	instance, err := jsonschemavalidator.UnmarshalJSON(bytes.NewReader(specsJSON))
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal public cloud specs: %w", err)
+	}
+
+	if err := schema.Validate(instance); err != nil {
+		return fmt.Errorf("public cloud specs do not match the schema: %w", err)
+	}
+
+	return nil
+}
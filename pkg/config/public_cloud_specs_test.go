@@ -2,9 +2,13 @@ package config
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/onsi/gomega"
+	"github.com/stretchr/testify/require"
 
 	"github.com/kyma-project/kyma-metrics-collector/env"
 )
@@ -12,6 +16,10 @@ import (
 const (
 	testPublicCloudSpecsPath           = "../testing/fixtures/public_cloud_specs.json"
 	testPublicCloudSpecsPathFractional = "../testing/fixtures/public_cloud_specs_fractional.json"
+	testPublicCloudSpecsPathOverrides  = "../testing/fixtures/public_cloud_specs_overrides.json"
+	testPublicCloudSpecsPathVersioned  = "../testing/fixtures/public_cloud_specs_versioned.json"
+	testPublicCloudSpecsPathSmall      = "../testing/fixtures/public_cloud_specs_small.json"
+	testPublicCloudSpecsPathYAML       = "../testing/fixtures/public_cloud_specs_small.yaml"
 )
 
 func TestGetFeature(t *testing.T) {
@@ -248,6 +256,27 @@ func TestGetFeatureFractional(t *testing.T) {
 	}
 }
 
+func TestResolveSpotDiscount(t *testing.T) {
+	regionDiscount := 0.3
+	noDiscount := 0.0
+	specs := &PublicCloudSpecs{
+		SpotDiscounts: map[string]float64{AWS: 0.6},
+		Overrides: []Override{
+			{Provider: AWS, Region: "eu-central-1", SpotDiscount: &regionDiscount},
+			{Provider: AWS, Plan: "trial", SpotDiscount: &noDiscount},
+			{Provider: AWS, Region: "us-east-1", VMTypes: map[string]Feature{"m5.large": {CpuCores: 2}}},
+		},
+	}
+
+	require.InDelta(t, 0.6, specs.ResolveSpotDiscount(AWS, "eu-west-1", "aws"), 1e-9)
+	require.InDelta(t, 0.3, specs.ResolveSpotDiscount(AWS, "eu-central-1", "aws"), 1e-9)
+	// plan overrides take precedence over region overrides
+	require.Zero(t, specs.ResolveSpotDiscount(AWS, "eu-central-1", "trial"))
+	// overrides without a spot discount keep the discount of the provider
+	require.InDelta(t, 0.6, specs.ResolveSpotDiscount(AWS, "us-east-1", "aws"), 1e-9)
+	require.Zero(t, specs.ResolveSpotDiscount(GCP, "europe-west3", "gcp"))
+}
+
 func TestGetRedisInfo(t *testing.T) {
 	g := gomega.NewGomegaWithT(t)
 	config := &env.Config{PublicCloudSpecsPath: testPublicCloudSpecsPath}
@@ -292,3 +321,333 @@ func TestGetRedisInfo(t *testing.T) {
 		})
 	}
 }
+
+func TestResolveFeature(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	config := &env.Config{PublicCloudSpecsPath: testPublicCloudSpecsPathOverrides}
+	specs, err := LoadPublicCloudSpecs(config)
+	g.Expect(err).Should(gomega.BeNil())
+
+	testCases := []struct {
+		name            string
+		cloudProvider   string
+		region          string
+		plan            string
+		vmType          string
+		expectedFeature Feature
+		wantNil         bool
+	}{
+		{
+			name:            "no override matches",
+			cloudProvider:   "aws",
+			region:          "us-east-1",
+			plan:            "aws",
+			vmType:          "m4.large",
+			expectedFeature: Feature{CpuCores: 2, Memory: 8},
+		},
+		{
+			name:            "region override",
+			cloudProvider:   "aws",
+			region:          "eu-central-1",
+			plan:            "aws",
+			vmType:          "m4.large",
+			expectedFeature: Feature{CpuCores: 2.5, Memory: 10},
+		},
+		{
+			name:            "plan override takes precedence over region override",
+			cloudProvider:   "aws",
+			region:          "eu-central-1",
+			plan:            "trial",
+			vmType:          "m4.large",
+			expectedFeature: Feature{CpuCores: 0, Memory: 0},
+		},
+		{
+			name:            "region and plan override takes precedence over plan and region overrides",
+			cloudProvider:   "aws",
+			region:          "eu-central-1",
+			plan:            "trial",
+			vmType:          "m5.large",
+			expectedFeature: Feature{CpuCores: 1, Memory: 4},
+		},
+		{
+			name:            "override of other provider is ignored",
+			cloudProvider:   "azure",
+			region:          "eu-central-1",
+			plan:            "trial",
+			vmType:          "standard_a1_v2",
+			expectedFeature: Feature{CpuCores: 1, Memory: 2},
+		},
+		{
+			name:          "unknown VM type",
+			cloudProvider: "aws",
+			region:        "eu-central-1",
+			plan:          "trial",
+			vmType:        "m5.foo",
+			wantNil:       true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			gotFeature := specs.ResolveFeature(tc.cloudProvider, tc.region, tc.plan, tc.vmType)
+			if tc.wantNil {
+				g.Expect(gotFeature).Should(gomega.BeNil())
+				return
+			}
+
+			g.Expect(*gotFeature).Should(gomega.Equal(tc.expectedFeature))
+		})
+	}
+}
+
+func TestResolveRedisInfo(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	config := &env.Config{PublicCloudSpecsPath: testPublicCloudSpecsPathOverrides}
+	specs, err := LoadPublicCloudSpecs(config)
+	g.Expect(err).Should(gomega.BeNil())
+
+	// the region override defines the tier
+	gotRedis := specs.ResolveRedisInfo("aws", "eu-central-1", "aws", "S1")
+	g.Expect(*gotRedis).Should(gomega.Equal(RedisInfo{PriceStorageGB: 200, PriceCapacityUnits: 80}))
+
+	// the matching plan override does not define the tier, so the region override is used
+	gotRedis = specs.ResolveRedisInfo("aws", "eu-central-1", "trial", "S1")
+	g.Expect(*gotRedis).Should(gomega.Equal(RedisInfo{PriceStorageGB: 200, PriceCapacityUnits: 80}))
+
+	// no override matches, so the default is used
+	gotRedis = specs.ResolveRedisInfo("gcp", "europe-west3", "gcp", "S1")
+	g.Expect(*gotRedis).Should(gomega.Equal(RedisInfo{PriceStorageGB: 182, PriceCapacityUnits: 74}))
+}
+
+func TestAt(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	config := &env.Config{PublicCloudSpecsPath: testPublicCloudSpecsPathVersioned}
+	specs, err := LoadPublicCloudSpecs(config)
+	g.Expect(err).Should(gomega.BeNil())
+
+	testCases := []struct {
+		name            string
+		timestamp       time.Time
+		expectedVersion string
+		expectedFeature Feature
+	}{
+		{
+			name:            "before all versions",
+			timestamp:       time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC),
+			expectedVersion: "2020-01",
+			expectedFeature: Feature{CpuCores: 2, Memory: 8},
+		},
+		{
+			name:            "exactly at the effective time of the first version",
+			timestamp:       time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+			expectedVersion: "2020-01",
+			expectedFeature: Feature{CpuCores: 2, Memory: 8},
+		},
+		{
+			name:            "before the second version becomes effective",
+			timestamp:       time.Date(2099, 12, 31, 23, 59, 59, 0, time.UTC),
+			expectedVersion: "2020-01",
+			expectedFeature: Feature{CpuCores: 2, Memory: 8},
+		},
+		{
+			name:            "after the second version became effective",
+			timestamp:       time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC),
+			expectedVersion: "2100-01",
+			expectedFeature: Feature{CpuCores: 3, Memory: 9},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			version := specs.At(tc.timestamp)
+			g.Expect(version.Version).Should(gomega.Equal(tc.expectedVersion))
+			g.Expect(*version.GetFeature("aws", "m4.large")).Should(gomega.Equal(tc.expectedFeature))
+		})
+	}
+
+	// unversioned specs are effective at any time
+	unversioned := &PublicCloudSpecs{}
+	g.Expect(unversioned.At(time.Now())).Should(gomega.BeIdenticalTo(unversioned))
+}
+
+func TestValidateVersions(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	validVersion := func(name string, effectiveFrom time.Time) PublicCloudSpecs {
+		return PublicCloudSpecs{
+			Version:       name,
+			EffectiveFrom: effectiveFrom,
+			Providers: Providers{
+				Azure:     map[string]Feature{"standard_a1_v2": {CpuCores: 1, Memory: 2}},
+				AWS:       map[string]Feature{"m4.large": {CpuCores: 2, Memory: 8}},
+				GCP:       map[string]Feature{"n1-standard-4": {CpuCores: 4, Memory: 15}},
+				OpenStack: map[string]Feature{"g_c12_m48": {CpuCores: 12, Memory: 48}},
+			},
+			Redis: map[string]RedisInfo{"S1": {PriceStorageGB: 182, PriceCapacityUnits: 74}},
+		}
+	}
+
+	testCases := []struct {
+		name        string
+		specs       PublicCloudSpecs
+		expectedErr string
+	}{
+		{
+			name: "valid versions",
+			specs: PublicCloudSpecs{Versions: []PublicCloudSpecs{
+				validVersion("v1", now.Add(-time.Hour)),
+				validVersion("v2", now.Add(time.Hour)),
+			}},
+		},
+		{
+			name: "values outside of versions",
+			specs: PublicCloudSpecs{
+				Redis:    map[string]RedisInfo{"S1": {}},
+				Versions: []PublicCloudSpecs{validVersion("v1", now.Add(-time.Hour))},
+			},
+			expectedErr: "versioned public cloud specs must not define providers, Redis tiers, spot discounts or overrides outside of versions",
+		},
+		{
+			name: "missing version name",
+			specs: PublicCloudSpecs{Versions: []PublicCloudSpecs{
+				validVersion("", now.Add(-time.Hour)),
+			}},
+			expectedErr: "public cloud specs version 0 does not define a version name",
+		},
+		{
+			name: "duplicate version name",
+			specs: PublicCloudSpecs{Versions: []PublicCloudSpecs{
+				validVersion("v1", now.Add(-time.Hour)),
+				validVersion("v1", now.Add(time.Hour)),
+			}},
+			expectedErr: "public cloud specs version v1 is defined more than once",
+		},
+		{
+			name: "missing effective_from",
+			specs: PublicCloudSpecs{Versions: []PublicCloudSpecs{
+				validVersion("v1", time.Time{}),
+			}},
+			expectedErr: "public cloud specs version v1 does not define effective_from",
+		},
+		{
+			name: "invalid version",
+			specs: PublicCloudSpecs{Versions: []PublicCloudSpecs{
+				{Version: "v1", EffectiveFrom: now.Add(-time.Hour)},
+			}},
+			expectedErr: "public cloud specs version v1 is invalid: public cloud specs do not contain Redis tiers",
+		},
+		{
+			name: "no version effective yet",
+			specs: PublicCloudSpecs{Versions: []PublicCloudSpecs{
+				validVersion("v1", now.Add(time.Hour)),
+			}},
+			expectedErr: "public cloud specs do not contain a version effective at 2025-01-01T00:00:00Z",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.specs.validateVersions(now)
+			if tc.expectedErr == "" {
+				require.NoError(t, err)
+				return
+			}
+
+			require.EqualError(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestLoadPublicCloudSpecsYAML(t *testing.T) {
+	jsonSpecs, err := LoadPublicCloudSpecs(&env.Config{PublicCloudSpecsPath: testPublicCloudSpecsPathSmall})
+	require.NoError(t, err)
+
+	yamlSpecs, err := LoadPublicCloudSpecs(&env.Config{PublicCloudSpecsPath: testPublicCloudSpecsPathYAML})
+	require.NoError(t, err)
+	require.Equal(t, jsonSpecs, yamlSpecs)
+}
+
+func TestReadPublicCloudSpecsFile(t *testing.T) {
+	const (
+		yamlSpecs = "redis_tiers:\n  S1: {price_storage_gb: 182, price_cu: 74}\n"
+		jsonSpecs = `{"redis_tiers": {"S1": {"price_storage_gb": 182, "price_cu": 74}}}`
+	)
+
+	testCases := []struct {
+		name        string
+		file        string
+		content     string
+		expectedErr string
+	}{
+		{name: "JSON by extension", file: "specs.json", content: jsonSpecs},
+		{name: "YAML by extension", file: "specs.yaml", content: yamlSpecs},
+		{name: "YML by extension", file: "specs.yml", content: yamlSpecs},
+		{name: "JSON by content", file: "specs", content: jsonSpecs},
+		{name: "YAML by content", file: "specs", content: yamlSpecs},
+		{
+			name:        "YAML with duplicate keys",
+			file:        "specs.yaml",
+			content:     yamlSpecs + "redis_tiers: {}\n",
+			expectedErr: "failed to convert public cloud specs from YAML",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tc.file)
+			require.NoError(t, os.WriteFile(path, []byte(tc.content), 0o600))
+
+			specsJSON, err := ReadPublicCloudSpecsFile(path)
+			if tc.expectedErr != "" {
+				require.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.JSONEq(t, jsonSpecs, string(specsJSON))
+		})
+	}
+}
+
+func TestParsePublicCloudSpecsSchema(t *testing.T) {
+	testCases := []struct {
+		name        string
+		specs       string
+		expectedErr string
+	}{
+		{
+			name:        "unknown field",
+			specs:       `{"redis_tiers": {"S1": {"price_storage_gb": 182, "price_cu": 74, "price": 1}}}`,
+			expectedErr: "additional properties 'price' not allowed",
+		},
+		{
+			name:        "negative value",
+			specs:       `{"redis_tiers": {"S1": {"price_storage_gb": -1, "price_cu": 74}}}`,
+			expectedErr: "minimum: got -1, want 0",
+		},
+		{
+			name:        "missing value",
+			specs:       `{"providers": {"aws": {"m4.large": {"cpu_cores": 2}}}}`,
+			expectedErr: "missing property 'memory'",
+		},
+		{
+			name:        "unknown override provider",
+			specs:       `{"overrides": [{"provider": "alicloud", "region": "eu-1"}]}`,
+			expectedErr: "/overrides/0/provider",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParsePublicCloudSpecs([]byte(tc.specs), time.Now())
+			require.ErrorContains(t, err, "public cloud specs do not match the schema")
+			require.ErrorContains(t, err, tc.expectedErr)
+		})
+	}
+}
+
+// TestJSONSchemaUpToDate ensures that the published schema was regenerated after changing PublicCloudSpecs.
+func TestJSONSchemaUpToDate(t *testing.T) {
+	schemaJSON, err := GenerateJSONSchema()
+	require.NoError(t, err)
+	require.Equal(t, string(publicCloudSpecsSchema), string(schemaJSON), "run 'go generate ./pkg/config' to update the schema")
+}
@@ -0,0 +1,207 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// ValidatePublicCloudSpecs checks the content of a public cloud specs file more strictly than ParsePublicCloudSpecs.
+// In addition to the checks done on load, it reports unknown fields, duplicate keys, negative values,
+// unknown providers in overrides and keys which are not lowercase. All problems are returned instead of only the first one.
+func ValidatePublicCloudSpecs(specsJSON []byte, now time.Time) []error {
+	var errs []error
+
+	duplicates, err := findDuplicateKeys(specsJSON)
+	if err != nil {
+		return []error{fmt.Errorf("public cloud specs are not valid JSON: %w", err)}
+	}
+
+	for _, duplicate := range duplicates {
+		errs = append(errs, fmt.Errorf("public cloud specs define the key %s more than once", duplicate))
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(specsJSON))
+	decoder.DisallowUnknownFields()
+
+	var specs PublicCloudSpecs
+	if err := decoder.Decode(&specs); err != nil {
+		return append(errs, fmt.Errorf("public cloud specs do not match the schema: %w", err))
+	}
+
+	// The schema checks of ParsePublicCloudSpecs are skipped, as the checks below report the same problems
+	// with more context and do not stop at the first one.
+	if err := specs.validateSpecs(now); err != nil {
+		errs = append(errs, err)
+	}
+
+	if len(specs.Versions) == 0 {
+		return append(errs, specs.validateValues("")...)
+	}
+
+	for _, version := range specs.Versions {
+		errs = append(errs, version.validateValues(fmt.Sprintf("version %s: ", version.Version))...)
+	}
+
+	return errs
+}
+
+// validateValues checks that all values of a single specification version are non-negative and all keys are lowercase.
+// Redis tiers are excluded from the lowercase check, as they are referenced in uppercase by the Redis instances.
+func (pcs *PublicCloudSpecs) validateValues(prefix string) []error {
+	var errs []error
+
+	for provider, vmTypes := range pcs.Providers.byName() {
+		errs = append(errs, validateVMTypes(fmt.Sprintf("%sprovider %s", prefix, provider), vmTypes)...)
+	}
+
+	errs = append(errs, validateRedisTiers(prefix+"redis_tiers", pcs.Redis)...)
+
+	for provider := range pcs.SpotDiscounts {
+		if _, ok := pcs.Providers.byName()[provider]; !ok {
+			errs = append(errs, fmt.Errorf("%sspot_discounts: unknown provider %q", prefix, provider))
+		}
+	}
+
+	for i, override := range pcs.Overrides {
+		section := fmt.Sprintf("%soverride %d", prefix, i)
+
+		if _, ok := pcs.Providers.byName()[override.Provider]; !ok {
+			errs = append(errs, fmt.Errorf("%s: unknown provider %q", section, override.Provider))
+		}
+
+		for field, value := range map[string]string{"provider": override.Provider, "region": override.Region, "plan": override.Plan} {
+			if value != strings.ToLower(value) {
+				errs = append(errs, fmt.Errorf("%s: %s %q is not lowercase", section, field, value))
+			}
+		}
+
+		errs = append(errs, validateVMTypes(section, override.VMTypes)...)
+		errs = append(errs, validateRedisTiers(section+" redis_tiers", override.Redis)...)
+	}
+
+	return errs
+}
+
+func validateVMTypes(section string, vmTypes map[string]Feature) []error {
+	var errs []error
+
+	for vmType, feature := range vmTypes {
+		if vmType != strings.ToLower(vmType) {
+			errs = append(errs, fmt.Errorf("%s: VM type %q is not lowercase", section, vmType))
+		}
+
+		if feature.CpuCores < 0 {
+			errs = append(errs, fmt.Errorf("%s: VM type %q has negative cpu_cores %v", section, vmType, feature.CpuCores))
+		}
+
+		if feature.Memory < 0 {
+			errs = append(errs, fmt.Errorf("%s: VM type %q has negative memory %v", section, vmType, feature.Memory))
+		}
+
+		if feature.AcceleratorCount < 0 {
+			errs = append(errs, fmt.Errorf("%s: VM type %q has negative accelerator_count %d", section, vmType, feature.AcceleratorCount))
+		}
+
+		if feature.AcceleratorMemory < 0 {
+			errs = append(errs, fmt.Errorf("%s: VM type %q has negative accelerator_memory %v", section, vmType, feature.AcceleratorMemory))
+		}
+
+		if feature.VolumeSizeGb < 0 {
+			errs = append(errs, fmt.Errorf("%s: VM type %q has negative volume_size_gb %d", section, vmType, feature.VolumeSizeGb))
+		}
+
+		if (feature.AcceleratorType != "") != (feature.AcceleratorCount > 0) {
+			errs = append(errs, fmt.Errorf("%s: VM type %q must define both accelerator_type and accelerator_count or none of them", section, vmType))
+		}
+	}
+
+	return errs
+}
+
+func validateRedisTiers(section string, tiers map[string]RedisInfo) []error {
+	var errs []error
+
+	for tier, info := range tiers {
+		if info.PriceStorageGB < 0 {
+			errs = append(errs, fmt.Errorf("%s: tier %q has negative price_storage_gb %d", section, tier, info.PriceStorageGB))
+		}
+
+		if info.PriceCapacityUnits < 0 {
+			errs = append(errs, fmt.Errorf("%s: tier %q has negative price_cu %d", section, tier, info.PriceCapacityUnits))
+		}
+	}
+
+	return errs
+}
+
+// findDuplicateKeys returns the paths of all object keys which are defined more than once in the JSON document.
+// json.Unmarshal silently keeps the last value of a duplicate key, so they have to be detected on the token stream.
+func findDuplicateKeys(data []byte) ([]string, error) {
+	var duplicates []string
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if err := walkJSON(decoder, "$", &duplicates); err != nil {
+		return nil, err
+	}
+
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("unexpected data after the top-level value")
+	}
+
+	return duplicates, nil
+}
+
+func walkJSON(decoder *json.Decoder, path string, duplicates *[]string) error {
+	token, err := decoder.Token()
+	if errors.Is(err, io.EOF) {
+		return io.ErrUnexpectedEOF
+	}
+
+	if err != nil {
+		return err
+	}
+
+	delim, ok := token.(json.Delim)
+	if !ok {
+		return nil
+	}
+
+	switch delim {
+	case '{':
+		keys := make(map[string]struct{})
+
+		for decoder.More() {
+			keyToken, err := decoder.Token()
+			if err != nil {
+				return err
+			}
+
+			key := keyToken.(string)
+			if _, ok := keys[key]; ok {
+				*duplicates = append(*duplicates, path+"."+key)
+			}
+
+			keys[key] = struct{}{}
+
+			if err := walkJSON(decoder, path+"."+key, duplicates); err != nil {
+				return err
+			}
+		}
+	case '[':
+		for i := 0; decoder.More(); i++ {
+			if err := walkJSON(decoder, fmt.Sprintf("%s[%d]", path, i), duplicates); err != nil {
+				return err
+			}
+		}
+	}
+
+	// consume the closing delimiter
+	_, err = decoder.Token()
+
+	return err
+}
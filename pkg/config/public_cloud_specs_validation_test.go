@@ -0,0 +1,161 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	kmctesting "github.com/kyma-project/kyma-metrics-collector/pkg/testing"
+)
+
+const validSpecs = `{
+  "providers": {
+    "azure": {"standard_a1_v2": {"cpu_cores": 1, "memory": 2}},
+    "aws": {"m4.large": {"cpu_cores": 2, "memory": 8}},
+    "gcp": {"n1-standard-4": {"cpu_cores": 4, "memory": 15}},
+    "sapconvergedcloud": {"g_c12_m48": {"cpu_cores": 12, "memory": 48}}
+  },
+  "redis_tiers": {"S1": {"price_storage_gb": 182, "price_cu": 74}}
+}`
+
+func TestValidatePublicCloudSpecs(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name         string
+		specs        string
+		expectedErrs []string
+	}{
+		{
+			name:  "valid specs",
+			specs: validSpecs,
+		},
+		{
+			name:         "invalid JSON",
+			specs:        `{"providers": `,
+			expectedErrs: []string{"public cloud specs are not valid JSON: unexpected EOF"},
+		},
+		{
+			name: "duplicate keys",
+			specs: `{
+  "providers": {
+    "azure": {"standard_a1_v2": {"cpu_cores": 1, "memory": 2}},
+    "aws": {"m4.large": {"cpu_cores": 2, "memory": 8}, "m4.large": {"cpu_cores": 3, "memory": 8}},
+    "gcp": {"n1-standard-4": {"cpu_cores": 4, "memory": 15}},
+    "sapconvergedcloud": {"g_c12_m48": {"cpu_cores": 12, "memory": 48}}
+  },
+  "redis_tiers": {"S1": {"price_storage_gb": 182, "price_cu": 74}}
+}`,
+			expectedErrs: []string{"public cloud specs define the key $.providers.aws.m4.large more than once"},
+		},
+		{
+			name:         "unknown field",
+			specs:        `{"provider": {}}`,
+			expectedErrs: []string{`public cloud specs do not match the schema: json: unknown field "provider"`},
+		},
+		{
+			name: "negative values and uppercase keys",
+			specs: `{
+  "providers": {
+    "azure": {"Standard_A1_v2": {"cpu_cores": 1, "memory": 2}},
+    "aws": {"m4.large": {"cpu_cores": -2, "memory": 8}},
+    "gcp": {"n1-standard-4": {"cpu_cores": 4, "memory": 15}},
+    "sapconvergedcloud": {"g_c12_m48": {"cpu_cores": 12, "memory": 48}}
+  },
+  "redis_tiers": {"S1": {"price_storage_gb": 182, "price_cu": -74}},
+  "overrides": [{"provider": "alicloud", "region": "EU-1", "vm_types": {"m4.large": {"cpu_cores": 2, "memory": -8}}}]
+}`,
+			expectedErrs: []string{
+				`provider azure: VM type "Standard_A1_v2" is not lowercase`,
+				`provider aws: VM type "m4.large" has negative cpu_cores -2`,
+				`redis_tiers: tier "S1" has negative price_cu -74`,
+				`override 0: unknown provider "alicloud"`,
+				`override 0: region "EU-1" is not lowercase`,
+				`override 0: VM type "m4.large" has negative memory -8`,
+			},
+		},
+		{
+			name: "valid GPU VM type",
+			specs: `{
+  "providers": {
+    "azure": {"standard_a1_v2": {"cpu_cores": 1, "memory": 2}},
+    "aws": {"g4dn.xlarge": {"cpu_cores": 4, "memory": 16, "accelerator_type": "nvidia-t4", "accelerator_count": 1, "accelerator_memory": 16}},
+    "gcp": {"n1-standard-4": {"cpu_cores": 4, "memory": 15}},
+    "sapconvergedcloud": {"g_c12_m48": {"cpu_cores": 12, "memory": 48}}
+  },
+  "redis_tiers": {"S1": {"price_storage_gb": 182, "price_cu": 74}}
+}`,
+		},
+		{
+			name: "invalid GPU VM types",
+			specs: `{
+  "providers": {
+    "azure": {"standard_a1_v2": {"cpu_cores": 1, "memory": 2, "accelerator_type": "nvidia-t4"}},
+    "aws": {"g4dn.xlarge": {"cpu_cores": 4, "memory": 16, "accelerator_count": 1}},
+    "gcp": {"n1-standard-4": {"cpu_cores": 4, "memory": 15}},
+    "sapconvergedcloud": {"g_c12_m48": {"cpu_cores": 12, "memory": 48}}
+  },
+  "redis_tiers": {"S1": {"price_storage_gb": 182, "price_cu": 74}}
+}`,
+			expectedErrs: []string{
+				`provider azure: VM type "standard_a1_v2" must define both accelerator_type and accelerator_count or none of them`,
+				`provider aws: VM type "g4dn.xlarge" must define both accelerator_type and accelerator_count or none of them`,
+			},
+		},
+		{
+			name: "invalid spot discounts",
+			specs: `{
+  "providers": {
+    "azure": {"standard_a1_v2": {"cpu_cores": 1, "memory": 2}},
+    "aws": {"m4.large": {"cpu_cores": 2, "memory": 8}},
+    "gcp": {"n1-standard-4": {"cpu_cores": 4, "memory": 15}},
+    "sapconvergedcloud": {"g_c12_m48": {"cpu_cores": 12, "memory": 48}}
+  },
+  "redis_tiers": {"S1": {"price_storage_gb": 182, "price_cu": 74}},
+  "spot_discounts": {"aws": 1.5, "alicloud": 0.5}
+}`,
+			expectedErrs: []string{
+				"public cloud specs spot discount 1.5 of provider aws is not between 0 and 1",
+				`spot_discounts: unknown provider "alicloud"`,
+			},
+		},
+		{
+			name: "invalid version",
+			specs: `{"versions": [{"version": "v1", "effective_from": "2024-01-01T00:00:00Z", "providers": {
+    "azure": {"standard_a1_v2": {"cpu_cores": 1, "memory": -2}}}}]}`,
+			expectedErrs: []string{
+				"public cloud specs version v1 is invalid: public cloud specs do not contain Redis tiers",
+				`version v1: provider azure: VM type "standard_a1_v2" has negative memory -2`,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			errs := ValidatePublicCloudSpecs([]byte(tc.specs), now)
+
+			var gotErrs []string
+			for _, err := range errs {
+				gotErrs = append(gotErrs, err.Error())
+			}
+
+			require.ElementsMatch(t, tc.expectedErrs, gotErrs)
+		})
+	}
+}
+
+func TestValidatePublicCloudSpecsFixtures(t *testing.T) {
+	for _, fixture := range []string{
+		testPublicCloudSpecsPath,
+		testPublicCloudSpecsPathFractional,
+		testPublicCloudSpecsPathOverrides,
+		testPublicCloudSpecsPathVersioned,
+	} {
+		t.Run(fixture, func(t *testing.T) {
+			specsJSON, err := kmctesting.LoadFixtureFromFile(fixture)
+			require.NoError(t, err)
+			require.Empty(t, ValidatePublicCloudSpecs(specsJSON, time.Now()))
+		})
+	}
+}
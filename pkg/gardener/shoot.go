@@ -0,0 +1,7 @@
+// Package gardener contains the Gardener API definitions shared by the packages reading from a Gardener project.
+package gardener
+
+import "k8s.io/apimachinery/pkg/runtime/schema"
+
+// ShootGVR is the group version resource of the Gardener shoots.
+var ShootGVR = schema.GroupVersionResource{Group: "core.gardener.cloud", Version: "v1beta1", Resource: "shoots"}
@@ -0,0 +1,164 @@
+package keb
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// newTransport returns the transport for the KEB client with the authentication configured in the config.
+// Credentials are read from their files when they are used, so rotated secrets are picked up without a restart.
+func newTransport(config *Config) (http.RoundTripper, error) {
+	if err := config.validateAuth(); err != nil {
+		return nil, err
+	}
+
+	transport := http.DefaultTransport
+
+	if config.TLSCertFile != "" || config.TLSCAFile != "" {
+		tlsConfig, err := newTLSConfig(config)
+		if err != nil {
+			return nil, err
+		}
+
+		defaultTransport := http.DefaultTransport.(*http.Transport).Clone()
+		defaultTransport.TLSClientConfig = tlsConfig
+		transport = defaultTransport
+	}
+
+	switch {
+	case config.OAuth2ClientID != "":
+		source := &clientCredentialsTokenSource{
+			config: config,
+			// the token requests use the same TLS settings as the KEB requests
+			httpClient: &http.Client{Transport: transport, Timeout: config.Timeout},
+		}
+
+		return &oauth2.Transport{
+			Source: oauth2.ReuseTokenSource(nil, source),
+			Base:   transport,
+		}, nil
+	case config.BearerTokenFile != "":
+		return &bearerTokenTransport{tokenFile: config.BearerTokenFile, base: transport}, nil
+	}
+
+	return transport, nil
+}
+
+// validateAuth checks that at most one token based authentication is configured and that it is complete.
+func (c *Config) validateAuth() error {
+	if c.OAuth2ClientID != "" && c.BearerTokenFile != "" {
+		return fmt.Errorf("KEB OAuth2 client credentials and bearer token file must not be configured together")
+	}
+
+	if c.OAuth2ClientID != "" && (c.OAuth2TokenURL == "" || c.OAuth2ClientSecretFile == "") {
+		return fmt.Errorf("KEB OAuth2 client credentials require a token URL and a client secret file")
+	}
+
+	if (c.TLSCertFile == "") != (c.TLSKeyFile == "") {
+		return fmt.Errorf("KEB client certificate and key files must be configured together")
+	}
+
+	return nil
+}
+
+func newTLSConfig(config *Config) (*tls.Config, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if config.TLSCAFile != "" {
+		caPEM, err := os.ReadFile(config.TLSCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read KEB CA file: %w", err)
+		}
+
+		rootCAs := x509.NewCertPool()
+		if !rootCAs.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("KEB CA file %s does not contain a PEM encoded certificate", config.TLSCAFile)
+		}
+
+		tlsConfig.RootCAs = rootCAs
+	}
+
+	if config.TLSCertFile != "" {
+		// fail early on a broken certificate instead of on the first request
+		if _, err := tls.LoadX509KeyPair(config.TLSCertFile, config.TLSKeyFile); err != nil {
+			return nil, fmt.Errorf("failed to load KEB client certificate: %w", err)
+		}
+
+		tlsConfig.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			cert, err := tls.LoadX509KeyPair(config.TLSCertFile, config.TLSKeyFile)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load KEB client certificate: %w", err)
+			}
+
+			return &cert, nil
+		}
+	}
+
+	return tlsConfig, nil
+}
+
+// clientCredentialsTokenSource fetches a new token with the OAuth2 client credentials flow.
+// It is wrapped in a ReuseTokenSource, which caches the token until it expires.
+type clientCredentialsTokenSource struct {
+	config     *Config
+	httpClient *http.Client
+}
+
+func (s *clientCredentialsTokenSource) Token() (*oauth2.Token, error) {
+	secret, err := readSecretFile(s.config.OAuth2ClientSecretFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read KEB OAuth2 client secret: %w", err)
+	}
+
+	credentials := clientcredentials.Config{
+		ClientID:     s.config.OAuth2ClientID,
+		ClientSecret: secret,
+		TokenURL:     s.config.OAuth2TokenURL,
+		Scopes:       s.config.OAuth2Scopes,
+	}
+
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, s.httpClient)
+
+	token, err := credentials.Token(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch KEB OAuth2 token: %w", err)
+	}
+
+	return token, nil
+}
+
+// bearerTokenTransport adds the token from the file to every request.
+type bearerTokenTransport struct {
+	tokenFile string
+	base      http.RoundTripper
+}
+
+func (t *bearerTokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := readSecretFile(t.tokenFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read KEB bearer token: %w", err)
+	}
+
+	// a RoundTripper must not modify the original request
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	return t.base.RoundTrip(req)
+}
+
+func readSecretFile(file string) (string, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(data)), nil
+}
@@ -0,0 +1,169 @@
+package keb
+
+import (
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/kyma-project/kyma-metrics-collector/pkg/logger"
+)
+
+const emptyRuntimesPage = `{"data": [], "count": 0, "totalCount": 0}`
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	file := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(file, []byte(content), 0o600))
+
+	return file
+}
+
+func newAuthTestClient(t *testing.T, config *Config) *Client {
+	t.Helper()
+
+	config.Timeout = 3 * time.Second
+	config.RetryCount = 1
+
+	client, err := NewClient(config, logger.NewLogger(zapcore.InfoLevel))
+	require.NoError(t, err)
+
+	return client
+}
+
+func getRuntimes(t *testing.T, client *Client) error {
+	t.Helper()
+
+	req, err := client.NewRequest()
+	require.NoError(t, err)
+
+	_, err = client.GetAllRuntimes(req)
+
+	return err
+}
+
+func TestBearerTokenAuth(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Authorization") != "Bearer secret-token" {
+			rw.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		_, _ = rw.Write([]byte(emptyRuntimesPage))
+	}))
+	defer srv.Close()
+
+	tokenFile := writeFile(t, "token", "secret-token\n")
+	client := newAuthTestClient(t, &Config{URL: srv.URL, BearerTokenFile: tokenFile})
+
+	require.NoError(t, getRuntimes(t, client))
+
+	// a rotated token is picked up with the next request
+	require.NoError(t, os.WriteFile(tokenFile, []byte("rotated-token"), 0o600))
+	require.ErrorContains(t, getRuntimes(t, client), "KEB returned status code: 401")
+}
+
+func TestOAuth2ClientCredentialsAuth(t *testing.T) {
+	var tokenRequests atomic.Int32
+
+	tokenSrv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		clientID, clientSecret, ok := req.BasicAuth()
+		if !ok || clientID != "kmc" || clientSecret != "client-secret" {
+			rw.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		tokenRequests.Add(1)
+		rw.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(rw, `{"access_token": "token-%d", "token_type": "bearer", "expires_in": 3600}`, tokenRequests.Load())
+	}))
+	defer tokenSrv.Close()
+
+	kebSrv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Authorization") != "Bearer token-1" {
+			rw.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		_, _ = rw.Write([]byte(emptyRuntimesPage))
+	}))
+	defer kebSrv.Close()
+
+	client := newAuthTestClient(t, &Config{
+		URL:                    kebSrv.URL,
+		OAuth2TokenURL:         tokenSrv.URL,
+		OAuth2ClientID:         "kmc",
+		OAuth2ClientSecretFile: writeFile(t, "client-secret", "client-secret\n"),
+	})
+
+	require.NoError(t, getRuntimes(t, client))
+	require.NoError(t, getRuntimes(t, client))
+	// the token is cached until it expires
+	require.Equal(t, int32(1), tokenRequests.Load())
+}
+
+func TestTLSAuth(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		_, _ = rw.Write([]byte(emptyRuntimesPage))
+	}))
+	defer srv.Close()
+
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+
+	client := newAuthTestClient(t, &Config{URL: srv.URL, TLSCAFile: writeFile(t, "ca.crt", string(caPEM))})
+	require.NoError(t, getRuntimes(t, client))
+
+	// without the CA, the server certificate is not trusted
+	client = newAuthTestClient(t, &Config{URL: srv.URL})
+	require.ErrorContains(t, getRuntimes(t, client), "certificate")
+}
+
+func TestNewClientInvalidAuth(t *testing.T) {
+	testCases := []struct {
+		name        string
+		config      *Config
+		expectedErr string
+	}{
+		{
+			name:        "OAuth2 and bearer token",
+			config:      &Config{OAuth2ClientID: "kmc", BearerTokenFile: "token"},
+			expectedErr: "must not be configured together",
+		},
+		{
+			name:        "OAuth2 without token URL",
+			config:      &Config{OAuth2ClientID: "kmc", OAuth2ClientSecretFile: "secret"},
+			expectedErr: "require a token URL and a client secret file",
+		},
+		{
+			name:        "certificate without key",
+			config:      &Config{TLSCertFile: "tls.crt"},
+			expectedErr: "certificate and key files must be configured together",
+		},
+		{
+			name:        "missing certificate",
+			config:      &Config{TLSCertFile: "does-not-exist.crt", TLSKeyFile: "does-not-exist.key"},
+			expectedErr: "failed to load KEB client certificate",
+		},
+		{
+			name:        "invalid CA",
+			config:      &Config{TLSCAFile: writeFile(t, "ca.crt", "not a certificate")},
+			expectedErr: "does not contain a PEM encoded certificate",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewClient(tc.config, logger.NewLogger(zapcore.InfoLevel))
+			require.ErrorContains(t, err, tc.expectedErr)
+		})
+	}
+}
@@ -2,6 +2,7 @@ package keb
 
 import (
 	"encoding/json"
+	stderrors "errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -12,6 +13,7 @@ import (
 	kebruntime "github.com/kyma-project/kyma-environment-broker/common/runtime"
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
 
 	log "github.com/kyma-project/kyma-metrics-collector/pkg/logger"
 )
@@ -27,9 +29,16 @@ const (
 	retryInterval = 10 * time.Second
 )
 
-func NewClient(config *Config, logger *zap.SugaredLogger) *Client {
+// NewClient creates a KEB client which authenticates with the OAuth2 client credentials, bearer token
+// or client certificate configured in the config.
+func NewClient(config *Config, logger *zap.SugaredLogger) (*Client, error) {
+	transport, err := newTransport(config)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to configure KEB authentication")
+	}
+
 	kebHTTPClient := &http.Client{
-		Transport: http.DefaultTransport,
+		Transport: transport,
 		Timeout:   config.Timeout,
 	}
 
@@ -37,7 +46,7 @@ func NewClient(config *Config, logger *zap.SugaredLogger) *Client {
 		HTTPClient: kebHTTPClient,
 		Logger:     logger,
 		Config:     config,
-	}
+	}, nil
 }
 
 func (c *Client) NewRequest() (*http.Request, error) {
@@ -54,46 +63,113 @@ func (c *Client) NewRequest() (*http.Request, error) {
 	return req, nil
 }
 
+// GetAllRuntimes fetches all runtimes matching the filter configured in the client config.
 func (c *Client) GetAllRuntimes(req *http.Request) (*kebruntime.RuntimesPage, error) {
-	morePages := true
-	pageNum := 1
-	recordsSeen := 0
-	finalRuntimesPage := new(kebruntime.RuntimesPage)
+	return c.GetRuntimes(req, c.Config.Filter())
+}
 
-	for morePages {
-		runtimesPage, err := c.getRuntimesPerPage(req, pageNum)
-		if err != nil {
-			return nil, errors.Wrapf(err, "failed to get runtimes from KEB")
+// GetRuntimes fetches all pages of the runtimes matching the filter.
+// The first page is fetched to learn the total count, the remaining pages are fetched concurrently with
+// at most Config.PageConcurrency requests in flight. Every page is retried on its own, and the fetch only
+// fails if a page still fails after all retries, as an incomplete list would drop runtimes from billing.
+// Runtimes which KEB returns although they do not match the filter are dropped from the result.
+func (c *Client) GetRuntimes(req *http.Request, filter Filter) (*kebruntime.RuntimesPage, error) {
+	firstPage, err := c.getPage(req, 1, filter)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get runtimes from KEB")
+	}
+
+	pages := []*kebruntime.RuntimesPage{firstPage}
+
+	if firstPage.Count > 0 && firstPage.Count < firstPage.TotalCount {
+		pageCount := (firstPage.TotalCount + firstPage.Count - 1) / firstPage.Count
+		pages = append(pages, make([]*kebruntime.RuntimesPage, pageCount-1)...)
+		pageErrs := make([]error, pageCount)
+
+		var group errgroup.Group
+
+		group.SetLimit(max(c.Config.PageConcurrency, 1))
+
+		for pageNum := 2; pageNum <= pageCount; pageNum++ {
+			group.Go(func() error {
+				pages[pageNum-1], pageErrs[pageNum-1] = c.getPage(req, pageNum, filter)
+				return nil
+			})
 		}
 
-		finalRuntimesPage.Data = append(finalRuntimesPage.Data, runtimesPage.Data...)
-		finalRuntimesPage.Count = len(finalRuntimesPage.Data)
-		recordsSeen += runtimesPage.Count
-		c.namedLogger().Debugf("count: %d, records-seen: %d, page-num: %d, total-count: %d",
-			runtimesPage.Count, recordsSeen, pageNum, runtimesPage.TotalCount)
+		_ = group.Wait()
 
-		if recordsSeen >= runtimesPage.TotalCount {
-			morePages = false
-			continue
+		if err := stderrors.Join(pageErrs...); err != nil {
+			return nil, errors.Wrapf(err, "failed to get runtimes from KEB")
 		}
+	}
+
+	finalRuntimesPage := new(kebruntime.RuntimesPage)
+	recordsSeen := 0
+	// runtimes can move to the next page while the pages are fetched, so they are deduplicated before they are counted
+	seenRuntimes := make(map[string]struct{})
+
+	for _, runtimesPage := range pages {
+		for _, runtime := range runtimesPage.Data {
+			key := runtimeKey(runtime)
+			if _, ok := seenRuntimes[key]; ok && key != "" {
+				continue
+			}
+
+			seenRuntimes[key] = struct{}{}
+			recordsSeen++
 
-		pageNum += 1
+			if filter.Matches(runtime) {
+				finalRuntimesPage.Data = append(finalRuntimesPage.Data, runtime)
+			}
+		}
 	}
 
+	finalRuntimesPage.Count = len(finalRuntimesPage.Data)
 	finalRuntimesPage.TotalCount = recordsSeen
+	c.namedLogger().Debugf("count: %d, records-seen: %d, pages: %d, total-count: %d",
+		finalRuntimesPage.Count, recordsSeen, len(pages), firstPage.TotalCount)
 
 	return finalRuntimesPage, nil
 }
 
-func (c *Client) getRuntimesPerPage(req *http.Request, pageNum int) (*kebruntime.RuntimesPage, error) {
-	// define URL.
-	c.Logger.Debugf("polling for runtimes with URL: %s", req.URL.String())
+// runtimeKey identifies a runtime by its runtime ID, or by its instance ID if the runtime ID is not set yet.
+func runtimeKey(runtime kebruntime.RuntimeDTO) string {
+	if runtime.RuntimeID != "" {
+		return runtime.RuntimeID
+	}
+
+	return runtime.InstanceID
+}
+
+// getPage fetches a single page on its own copy of the request, so pages can be fetched concurrently.
+func (c *Client) getPage(req *http.Request, pageNum int, filter Filter) (*kebruntime.RuntimesPage, error) {
+	pageStartTime := time.Now()
+	runtimesPage, err := c.getRuntimesPerPage(req.Clone(req.Context()), pageNum, filter)
+	duration := time.Since(pageStartTime)
 
-	query := url.Values{
-		"page": []string{fmt.Sprintf("%d", pageNum)},
+	recordKEBPageLatency(duration, err == nil)
+
+	if err != nil {
+		c.namedLogger().With(log.KeyResult, log.ValueFail).With(log.KeyError, err.Error()).
+			Errorf("get page %d of runtimes from KEB after %v", pageNum, duration)
+
+		return nil, fmt.Errorf("page %d: %w", pageNum, err)
 	}
+
+	c.namedLogger().Debugf("fetched page %d with %d runtimes in %v", pageNum, runtimesPage.Count, duration)
+
+	return runtimesPage, nil
+}
+
+func (c *Client) getRuntimesPerPage(req *http.Request, pageNum int, filter Filter) (*kebruntime.RuntimesPage, error) {
+	// define URL.
+	query := filter.query()
+	query.Set("page", fmt.Sprintf("%d", pageNum))
 	req.URL.RawQuery = query.Encode()
 
+	c.Logger.Debugf("polling for runtimes with URL: %s", req.URL.String())
+
 	// define request retry options.
 	retryOptions := []retry.Option{
 		retry.Attempts(uint(c.Config.RetryCount)),
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"sync"
 	"testing"
 	"time"
 
@@ -214,6 +215,52 @@ func TestGetAllRuntimes(t *testing.T) {
 		g.Expect(statusLabel.GetValue()).Should(gomega.Equal(fmt.Sprint(http.StatusOK)))
 	})
 
+	t.Run("when runtimes are filtered", func(t *testing.T) {
+		g := gomega.NewGomegaWithT(t)
+		// given
+		latencyMetric.Reset()
+
+		runtimesResponse, err := kmctesting.LoadFixtureFromFile(kebRuntimeResponseFilePath)
+		g.Expect(err).Should(gomega.BeNil())
+
+		allRuntimes := new(runtime.RuntimesPage)
+		err = json.Unmarshal(runtimesResponse, allRuntimes)
+		g.Expect(err).Should(gomega.BeNil())
+
+		getRuntimesHandler := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			g.Expect(req.URL.Query()["state"]).To(gomega.Equal([]string{"succeeded"}))
+			g.Expect(req.URL.Query()).NotTo(gomega.HaveKey("provider"))
+			g.Expect(req.URL.Query().Get("page")).To(gomega.Equal("1"))
+
+			// KEB does not support the provider filter, so the client has to drop the runtimes
+			_, err := rw.Write(runtimesResponse)
+			g.Expect(err).Should(gomega.BeNil())
+		})
+
+		srv := kmctesting.StartTestServer(expectedPathPrefixWith1Page, getRuntimesHandler, g)
+
+		g.Eventually(func() int {
+			healthResp, err := http.Get(fmt.Sprintf("%s/health", srv.URL))
+			t.Logf("retrying :%v", err)
+			return healthResp.StatusCode
+		}, timeout).Should(gomega.Equal(http.StatusOK))
+
+		kebClient := getKEBClient(fmt.Sprintf("%s%s", srv.URL, expectedPathPrefixWith1Page))
+		kebClient.Config.FilterStates = []string{"succeeded"}
+		kebClient.Config.FilterProviders = []string{"foo"}
+
+		req, err := kebClient.NewRequest()
+		g.Expect(err).Should(gomega.BeNil())
+
+		// when
+		gotRuntimes, err := kebClient.GetAllRuntimes(req)
+
+		// then
+		g.Expect(err).Should(gomega.BeNil())
+		g.Expect(gotRuntimes.Data).To(gomega.BeEmpty())
+		g.Expect(gotRuntimes.TotalCount).To(gomega.Equal(allRuntimes.TotalCount))
+	})
+
 	t.Run("when HTTP non 2xx is returned by KEB", func(t *testing.T) {
 		g := gomega.NewGomegaWithT(t)
 		// given
@@ -282,3 +329,156 @@ func getKEBClient(url string) *Client {
 		Config:     config,
 	}
 }
+
+func TestGetAllRuntimesConcurrentPages(t *testing.T) {
+	const (
+		pageSize  = 2
+		pageCount = 5
+		path      = "/runtimes/concurrent"
+	)
+
+	newPage := func(pageNum int) []byte {
+		page := runtime.RuntimesPage{Count: pageSize, TotalCount: pageSize * pageCount}
+		for i := range pageSize {
+			id := fmt.Sprintf("page-%d-runtime-%d", pageNum, i)
+			page.Data = append(page.Data, runtime.RuntimeDTO{InstanceID: id, SubAccountID: id})
+		}
+
+		data, err := json.Marshal(page)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return data
+	}
+
+	testCases := []struct {
+		name        string
+		failingPage string
+		expectedErr string
+	}{
+		{
+			name: "all pages are fetched",
+		},
+		{
+			name:        "a page fails",
+			failingPage: "3",
+			expectedErr: "page 3: ",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := gomega.NewGomegaWithT(t)
+			pageLatencyMetric.Reset()
+
+			var (
+				mu            sync.Mutex
+				inFlight      int
+				maxInFlight   int
+				requestCounts = make(map[string]int)
+			)
+
+			handler := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				pageNum := req.URL.Query().Get("page")
+
+				mu.Lock()
+				inFlight++
+				maxInFlight = max(maxInFlight, inFlight)
+				requestCounts[pageNum]++
+				mu.Unlock()
+
+				time.Sleep(10 * time.Millisecond)
+
+				mu.Lock()
+				inFlight--
+				mu.Unlock()
+
+				if pageNum == tc.failingPage {
+					rw.WriteHeader(http.StatusInternalServerError)
+					return
+				}
+
+				var num int
+				_, err := fmt.Sscan(pageNum, &num)
+				g.Expect(err).Should(gomega.BeNil())
+
+				_, err = rw.Write(newPage(num))
+				g.Expect(err).Should(gomega.BeNil())
+			})
+
+			srv := kmctesting.StartTestServer(path, handler, g)
+			kebClient := getKEBClient(srv.URL + path)
+			kebClient.Config.PageConcurrency = 2
+
+			req, err := kebClient.NewRequest()
+			g.Expect(err).Should(gomega.BeNil())
+
+			gotRuntimes, err := kebClient.GetAllRuntimes(req)
+
+			// every page is requested exactly once on its own request
+			g.Expect(requestCounts).To(gomega.HaveLen(pageCount))
+
+			for pageNum := 1; pageNum <= pageCount; pageNum++ {
+				g.Expect(requestCounts[fmt.Sprint(pageNum)]).To(gomega.Equal(1))
+			}
+
+			g.Expect(maxInFlight).To(gomega.BeNumerically("<=", 2))
+			g.Expect(testutil.CollectAndCount(pageLatencyMetric)).Should(gomega.BeNumerically(">", 0))
+
+			if tc.expectedErr != "" {
+				g.Expect(err).Should(gomega.MatchError(gomega.ContainSubstring(tc.expectedErr)))
+				g.Expect(err.Error()).Should(gomega.ContainSubstring("KEB returned status code: 500"))
+
+				return
+			}
+
+			g.Expect(err).Should(gomega.BeNil())
+			g.Expect(gotRuntimes.Data).To(gomega.HaveLen(pageSize * pageCount))
+			g.Expect(gotRuntimes.Count).To(gomega.Equal(pageSize * pageCount))
+			// the pages are assembled in order
+			g.Expect(gotRuntimes.Data[0].InstanceID).To(gomega.Equal("page-1-runtime-0"))
+			g.Expect(gotRuntimes.Data[pageSize*pageCount-1].InstanceID).To(gomega.Equal("page-5-runtime-1"))
+		})
+	}
+}
+
+func TestGetAllRuntimesShiftedPages(t *testing.T) {
+	const path = "/runtimes/shifted"
+
+	g := gomega.NewGomegaWithT(t)
+
+	// runtime-2 moved to the second page while the pages were fetched, so it is returned on both pages
+	pages := map[string]runtime.RuntimesPage{
+		"1": {Count: 2, TotalCount: 4, Data: []runtime.RuntimeDTO{
+			{RuntimeID: "runtime-1", InstanceID: "instance-1"},
+			{RuntimeID: "runtime-2", InstanceID: "instance-2"},
+		}},
+		"2": {Count: 2, TotalCount: 4, Data: []runtime.RuntimeDTO{
+			{RuntimeID: "runtime-2", InstanceID: "instance-2"},
+			{RuntimeID: "runtime-3", InstanceID: "instance-3"},
+		}},
+	}
+
+	handler := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		data, err := json.Marshal(pages[req.URL.Query().Get("page")])
+		g.Expect(err).Should(gomega.BeNil())
+
+		_, err = rw.Write(data)
+		g.Expect(err).Should(gomega.BeNil())
+	})
+
+	srv := kmctesting.StartTestServer(path, handler, g)
+	kebClient := getKEBClient(srv.URL + path)
+
+	req, err := kebClient.NewRequest()
+	g.Expect(err).Should(gomega.BeNil())
+
+	gotRuntimes, err := kebClient.GetAllRuntimes(req)
+	g.Expect(err).Should(gomega.BeNil())
+
+	g.Expect(gotRuntimes.Count).To(gomega.Equal(3))
+	g.Expect(gotRuntimes.TotalCount).To(gomega.Equal(3))
+	g.Expect(gotRuntimes.Data).To(gomega.HaveLen(3))
+	g.Expect(gotRuntimes.Data[2].RuntimeID).To(gomega.Equal("runtime-3"))
+}
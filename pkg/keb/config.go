@@ -7,4 +7,38 @@ type Config struct {
 	Timeout          time.Duration `default:"30s"       envconfig:"KEB_TIMEOUT"`
 	RetryCount       int           `default:"5"         envconfig:"KEB_RETRY_COUNT"`
 	PollWaitDuration time.Duration `default:"10m"       envconfig:"KEB_POLL_WAIT_DURATION"`
+	// PageConcurrency is the maximum number of pages of runtimes fetched from KEB concurrently.
+	PageConcurrency int `default:"5" envconfig:"KEB_PAGE_CONCURRENCY"`
+	// IncrementalPollInterval enables incremental polling if set. KMC then fetches only the runtimes
+	// updated since the previous poll at this interval, and does a full resync every PollWaitDuration.
+	IncrementalPollInterval time.Duration `envconfig:"KEB_INCREMENTAL_POLL_INTERVAL"`
+	FilterStates            []string      `envconfig:"KEB_FILTER_STATES"`
+	FilterProviders         []string      `envconfig:"KEB_FILTER_PROVIDERS"`
+	FilterPlans             []string      `envconfig:"KEB_FILTER_PLANS"`
+
+	// OAuth2 client credentials used to fetch a token for KEB. The client secret is read from a file.
+	OAuth2TokenURL         string   `envconfig:"KEB_OAUTH2_TOKEN_URL"`
+	OAuth2ClientID         string   `envconfig:"KEB_OAUTH2_CLIENT_ID"`
+	OAuth2ClientSecretFile string   `envconfig:"KEB_OAUTH2_CLIENT_SECRET_FILE"`
+	OAuth2Scopes           []string `envconfig:"KEB_OAUTH2_SCOPES"`
+	// BearerTokenFile is the file of a static token for KEB, e.g. mounted from a secret.
+	BearerTokenFile string `envconfig:"KEB_BEARER_TOKEN_FILE"`
+	// Client certificate for mTLS and CA to verify KEB.
+	TLSCertFile string `envconfig:"KEB_TLS_CERT_FILE"`
+	TLSKeyFile  string `envconfig:"KEB_TLS_KEY_FILE"`
+	TLSCAFile   string `envconfig:"KEB_TLS_CA_FILE"`
+}
+
+// Filter returns the filter for a full fetch of all runtimes configured to be tracked.
+func (c *Config) Filter() Filter {
+	return Filter{
+		States:    c.FilterStates,
+		Providers: c.FilterProviders,
+		Plans:     c.FilterPlans,
+	}
+}
+
+// IncrementalPollingEnabled reports whether runtimes are polled incrementally between full resyncs.
+func (c *Config) IncrementalPollingEnabled() bool {
+	return c.IncrementalPollInterval > 0 && c.IncrementalPollInterval < c.PollWaitDuration
 }
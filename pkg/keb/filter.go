@@ -0,0 +1,92 @@
+package keb
+
+import (
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	kebruntime "github.com/kyma-project/kyma-environment-broker/common/runtime"
+)
+
+// UpdatedSinceParam restricts the runtimes to the ones updated since the given time. KEB versions which do not support
+// it return all runtimes, so the client filters the returned runtimes by their update time as well.
+const UpdatedSinceParam = "updated_since"
+
+// Filter restricts the runtimes fetched from KEB. Empty fields do not restrict the result.
+type Filter struct {
+	States    []string
+	Providers []string
+	Plans     []string
+	// UpdatedSince restricts the result to runtimes which were created or modified at or after the given time.
+	UpdatedSince time.Time
+}
+
+// query returns the states, plans and update time of the filter as query parameters of the KEB runtimes endpoint.
+// KEB does not support filtering by provider, so providers are only filtered by Matches.
+func (f Filter) query() url.Values {
+	query := url.Values{}
+
+	for _, state := range f.States {
+		query.Add(kebruntime.StateParam, state)
+	}
+
+	for _, plan := range f.Plans {
+		query.Add(kebruntime.PlanParam, plan)
+	}
+
+	if !f.UpdatedSince.IsZero() {
+		query.Set(UpdatedSinceParam, f.UpdatedSince.UTC().Format(time.RFC3339))
+	}
+
+	return query
+}
+
+// Matches reports whether the runtime matches the provider, plan and updated since criteria of the filter.
+// States are only filtered by KEB, as KEB derives the state of a runtime from its operations.
+func (f Filter) Matches(runtime kebruntime.RuntimeDTO) bool {
+	if len(f.Providers) > 0 && !containsFold(f.Providers, runtime.Provider) {
+		return false
+	}
+
+	if len(f.Plans) > 0 && !containsFold(f.Plans, runtime.ServicePlanName) {
+		return false
+	}
+
+	if !f.UpdatedSince.IsZero() && lastModified(runtime).Before(f.UpdatedSince) {
+		return false
+	}
+
+	return true
+}
+
+func containsFold(values []string, value string) bool {
+	return slices.ContainsFunc(values, func(v string) bool {
+		return strings.EqualFold(v, value)
+	})
+}
+
+// lastModified returns the latest time at which the runtime or one of its provisioning
+// or deprovisioning operations changed.
+func lastModified(runtime kebruntime.RuntimeDTO) time.Time {
+	modified := runtime.Status.CreatedAt
+
+	candidates := []time.Time{runtime.Status.ModifiedAt}
+	if runtime.Status.DeletedAt != nil {
+		candidates = append(candidates, *runtime.Status.DeletedAt)
+	}
+
+	for _, op := range []*kebruntime.Operation{runtime.Status.Provisioning, runtime.Status.Deprovisioning} {
+		if op != nil {
+			candidates = append(candidates, op.CreatedAt, op.UpdatedAt)
+		}
+	}
+
+	for _, candidate := range candidates {
+		if candidate.After(modified) {
+			modified = candidate
+		}
+	}
+
+	return modified
+}
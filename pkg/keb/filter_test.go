@@ -0,0 +1,89 @@
+package keb
+
+import (
+	"testing"
+	"time"
+
+	kebruntime "github.com/kyma-project/kyma-environment-broker/common/runtime"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterQuery(t *testing.T) {
+	filter := Filter{
+		States:       []string{"succeeded", "error"},
+		Providers:    []string{"aws"},
+		Plans:        []string{"azure", "gcp"},
+		UpdatedSince: time.Date(2025, 1, 1, 12, 0, 0, 0, time.FixedZone("CET", 3600)),
+	}
+
+	require.Equal(t, "plan=azure&plan=gcp&state=succeeded&state=error&updated_since=2025-01-01T11%3A00%3A00Z",
+		filter.query().Encode())
+	require.Empty(t, Filter{}.query().Encode())
+}
+
+func TestFilterMatches(t *testing.T) {
+	since := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	before := since.Add(-time.Hour)
+	after := since.Add(time.Hour)
+
+	testCases := []struct {
+		name     string
+		filter   Filter
+		runtime  kebruntime.RuntimeDTO
+		expected bool
+	}{
+		{
+			name:     "empty filter",
+			runtime:  kebruntime.RuntimeDTO{Provider: "AWS"},
+			expected: true,
+		},
+		{
+			name:     "matching provider ignoring case",
+			filter:   Filter{Providers: []string{"aws"}},
+			runtime:  kebruntime.RuntimeDTO{Provider: "AWS"},
+			expected: true,
+		},
+		{
+			name:    "other provider",
+			filter:  Filter{Providers: []string{"aws"}},
+			runtime: kebruntime.RuntimeDTO{Provider: "Azure"},
+		},
+		{
+			name:    "other plan",
+			filter:  Filter{Plans: []string{"aws"}},
+			runtime: kebruntime.RuntimeDTO{ServicePlanName: "trial"},
+		},
+		{
+			name:    "not modified since",
+			filter:  Filter{UpdatedSince: since},
+			runtime: kebruntime.RuntimeDTO{Status: kebruntime.RuntimeStatus{CreatedAt: before, ModifiedAt: before}},
+		},
+		{
+			name:     "modified since",
+			filter:   Filter{UpdatedSince: since},
+			runtime:  kebruntime.RuntimeDTO{Status: kebruntime.RuntimeStatus{CreatedAt: before, ModifiedAt: after}},
+			expected: true,
+		},
+		{
+			name:   "deprovisioning updated since",
+			filter: Filter{UpdatedSince: since},
+			runtime: kebruntime.RuntimeDTO{Status: kebruntime.RuntimeStatus{
+				CreatedAt:      before,
+				Deprovisioning: &kebruntime.Operation{CreatedAt: before, UpdatedAt: after},
+			}},
+			expected: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, tc.filter.Matches(tc.runtime))
+		})
+	}
+}
+
+func TestIncrementalPollingEnabled(t *testing.T) {
+	require.False(t, (&Config{PollWaitDuration: 10 * time.Minute}).IncrementalPollingEnabled())
+	require.True(t, (&Config{PollWaitDuration: 10 * time.Minute, IncrementalPollInterval: time.Minute}).IncrementalPollingEnabled())
+	require.False(t, (&Config{PollWaitDuration: time.Minute, IncrementalPollInterval: time.Minute}).IncrementalPollingEnabled())
+}
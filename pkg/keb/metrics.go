@@ -2,6 +2,7 @@ package keb
 
 import (
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -15,8 +16,11 @@ const (
 	responseCodeLabel = "status"
 	// requestURLLabel name of the request URL label used by multiple metrics.
 	requestURLLabel = "request_url"
+	// resultLabel name of the label telling if a page was fetched successfully.
+	resultLabel = "success"
 	// metrics names.
-	latencyMetricName = "request_duration_seconds"
+	latencyMetricName     = "request_duration_seconds"
+	pageLatencyMetricName = "page_duration_seconds"
 )
 
 var latencyMetric = promauto.NewHistogramVec(
@@ -30,6 +34,36 @@ var latencyMetric = promauto.NewHistogramVec(
 	[]string{responseCodeLabel, requestURLLabel},
 )
 
+// pageLatencyMetric measures the duration of fetching a single page of runtimes including all retries.
+var pageLatencyMetric = promauto.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Namespace: Namespace,
+		Subsystem: Subsystem,
+		Name:      pageLatencyMetricName,
+		Help:      "Duration of fetching a page of runtimes from KEB including retries in seconds.",
+		Buckets:   []float64{0.5, 1, 2, 3, 4, 5, 7.5, 10, 30, 60},
+	},
+	[]string{resultLabel},
+)
+
+// lastSuccessfulPoll keeps the name it was introduced with by the process package, so existing alerts keep working.
+var lastSuccessfulPoll = promauto.NewGauge(
+	prometheus.GaugeOpts{
+		Namespace: Namespace,
+		Subsystem: "process",
+		Name:      "keb_last_successful_poll_timestamp_seconds",
+		Help:      "Unix timestamp (in seconds) of the last successful poll of the runtimes from KEB.",
+	},
+)
+
+func recordLastSuccessfulPoll(timestamp time.Time) {
+	lastSuccessfulPoll.Set(float64(timestamp.Unix()))
+}
+
+func recordKEBPageLatency(duration time.Duration, success bool) {
+	pageLatencyMetric.WithLabelValues(strconv.FormatBool(success)).Observe(duration.Seconds())
+}
+
 func recordKEBLatency(duration time.Duration, statusCode int, destSvc string) {
 	// the order if the values should be same as defined in the metric declaration.
 	latencyMetric.WithLabelValues(fmt.Sprint(statusCode), destSvc).Observe(duration.Seconds())
@@ -0,0 +1,76 @@
+package keb
+
+import (
+	"context"
+	"time"
+
+	log "github.com/kyma-project/kyma-metrics-collector/pkg/logger"
+	"github.com/kyma-project/kyma-metrics-collector/pkg/runtime"
+)
+
+// RuntimeSource polls the runtimes from KEB.
+// If incremental polling is enabled, only the runtimes updated since the previous poll are fetched in between
+// full resyncs. Runtimes removed from KEB are only removed from the handler on a full resync.
+type RuntimeSource struct {
+	client *Client
+}
+
+var _ runtime.Source = &RuntimeSource{}
+
+func NewRuntimeSource(client *Client) *RuntimeSource {
+	return &RuntimeSource{client: client}
+}
+
+func (s *RuntimeSource) Start(ctx context.Context, handler runtime.SourceHandler) error {
+	kebReq, err := s.client.NewRequest()
+	if err != nil {
+		return err
+	}
+
+	config := s.client.Config
+	waitDuration := config.PollWaitDuration
+
+	if config.IncrementalPollingEnabled() {
+		waitDuration = config.IncrementalPollInterval
+	}
+
+	var lastPoll, lastFullResync time.Time
+
+	for {
+		pollStart := time.Now()
+		fullResync := !config.IncrementalPollingEnabled() || lastFullResync.IsZero() ||
+			pollStart.Sub(lastFullResync) >= config.PollWaitDuration
+
+		filter := config.Filter()
+		if !fullResync {
+			// overlap with the previous poll to not miss runtimes updated while it was running
+			filter.UpdatedSince = lastPoll.Add(-config.IncrementalPollInterval)
+		}
+
+		runtimesPage, err := s.client.GetRuntimes(kebReq, filter)
+		if err != nil {
+			s.client.namedLogger().With(log.KeyResult, log.ValueFail).With(log.KeyError, err.Error()).
+				Error("get runtimes from KEB")
+		} else {
+			s.client.namedLogger().Debugf("num of runtimes are: %d, full resync: %t", runtimesPage.Count, fullResync)
+
+			if fullResync {
+				handler.Resync(runtime.FromKEB(runtimesPage.Data))
+				lastFullResync = pollStart
+			} else {
+				handler.Update(runtime.FromKEB(runtimesPage.Data))
+			}
+
+			lastPoll = pollStart
+			recordLastSuccessfulPoll(pollStart)
+		}
+
+		s.client.namedLogger().Infof("waiting to poll KEB again after %v....", waitDuration)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(waitDuration):
+		}
+	}
+}
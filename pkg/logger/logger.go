@@ -50,6 +50,22 @@ const (
 	// KeyShoot is used as a named key for a log message with shoot.
 	KeyShoot = "shoot"
 
+	// KeyTrackable is used as a named key for a log message with the trackability of a runtime.
+	KeyTrackable = "trackable"
+
+	// KeyTrackingReason is used as a named key for a log message with the reason of a tracking decision.
+	KeyTrackingReason = "trackingReason"
+
+	// KeyLastOperation is used as a named key for a log message with the last operation of a runtime.
+	KeyLastOperation = "lastOperation"
+
+	// KeyFailureReason is used as a named key for a log message with the reason of a failure.
+	KeyFailureReason = "failureReason"
+
+	// KeyKubeconfigMissingScrapes is used as a named key for a log message with the number of consecutive scrapes
+	// without a kubeconfig.
+	KeyKubeconfigMissingScrapes = "kubeconfigMissingScrapes"
+
 	// ValueFail is used as a value for a log message with failure.
 	ValueFail = "fail"
 
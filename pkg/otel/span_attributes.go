@@ -14,6 +14,8 @@ const (
 	globalAccountIDAttr = "global_account_id"
 	shootNameAttr       = "shoot_name"
 	providerAttr        = "provider"
+	regionAttr          = "region"
+	planAttr            = "plan"
 )
 
 func SpanAttributes(runtime *runtime.Info) trace.SpanStartEventOption {
@@ -24,5 +26,7 @@ func SpanAttributes(runtime *runtime.Info) trace.SpanStartEventOption {
 		attribute.String(globalAccountIDAttr, runtime.GlobalAccountID),
 		attribute.String(shootNameAttr, runtime.ShootName),
 		attribute.String(providerAttr, runtime.ProviderType),
+		attribute.String(regionAttr, runtime.Region),
+		attribute.String(planAttr, runtime.PlanName),
 	)
 }
@@ -19,53 +19,104 @@ const (
 	unsuspension
 )
 
+// String returns the name of the operation type used in tracking decisions.
+func (s runtimeState) String() string {
+	switch s {
+	case provisioning:
+		return "provisioning"
+	case deprovisioning:
+		return "deprovisioning"
+	case upgradingkyma:
+		return "upgrading_kyma"
+	case upgradingcluster:
+		return "upgrading_cluster"
+	case update:
+		return "update"
+	case suspension:
+		return "suspension"
+	case unsuspension:
+		return "unsuspension"
+	default:
+		return "unknown"
+	}
+}
+
+// Reasons of a tracking decision.
+const (
+	// ReasonSourceBillable means the runtime source flagged the runtime as billable.
+	ReasonSourceBillable = "source_billable"
+	// ReasonSourceNotBillable means the runtime source flagged the runtime as not billable.
+	ReasonSourceNotBillable = "source_not_billable"
+	// ReasonNoOperations means the runtime has no operations, so it was never provisioned.
+	ReasonNoOperations = "no_operations"
+	// ReasonProvisioned means the last operation is a succeeded provisioning.
+	ReasonProvisioned = "provisioned"
+	// ReasonProvisioningInProgress means the last operation is a provisioning which did not finish yet.
+	ReasonProvisioningInProgress = "provisioning_in_progress"
+	// ReasonProvisioningFailed means the last operation is a failed provisioning.
+	ReasonProvisioningFailed = "provisioning_failed"
+	// ReasonUnsuspended means the last operation is a succeeded unsuspension.
+	ReasonUnsuspended = "unsuspended"
+	// ReasonUnsuspensionInProgress means the last operation is an unsuspension which did not finish yet.
+	ReasonUnsuspensionInProgress = "unsuspension_in_progress"
+	// ReasonUnsuspensionFailed means the last operation is a failed unsuspension.
+	ReasonUnsuspensionFailed = "unsuspension_failed"
+	// ReasonSuspended means the last operation is a suspension.
+	ReasonSuspended = "suspended"
+	// ReasonDeprovisioned means the last operation is a deprovisioning.
+	ReasonDeprovisioned = "deprovisioned"
+	// ReasonActive means the last operation is an upgrade or update of a provisioned runtime.
+	ReasonActive = "active"
+	// ReasonNoMatchingRule means no rule of the tracking policy matches the runtime.
+	ReasonNoMatchingRule = "no_matching_rule"
+)
+
 type simpleOperation struct {
-	state     runtimeState
-	time      time.Time
-	succeeded bool
+	state    runtimeState
+	time     time.Time
+	rawState string
 }
 
-// isRuntimeTrackable determines if a runtime is trackable based on its operations.
-// A runtime is considered trackable if:
-// - It has a successful provisioning or unsuspension operation.
-// - It does not have a suspension or deprovisioning operation as the last operation.
-// - It has any other operation assuming the cluster was successfully provisioned and is billable.
-func isRuntimeTrackable(runtime kebruntime.RuntimeDTO) bool {
-	// Sort operations by time
-	operations := sortOperations(runtime)
-
-	// If a cluster does not have any operations, it is not trackable
-	if len(operations) == 0 {
-		return false
-	}
+// TrackingOperation is an operation of a runtime considered for a tracking decision.
+type TrackingOperation struct {
+	Type      string    `json:"type"`
+	State     string    `json:"state"`
+	CreatedAt time.Time `json:"createdAt"`
+}
 
-	// Get the last operation
-	lastOperation := operations[len(operations)-1]
+// TrackingDecision records why a runtime is tracked or not.
+type TrackingDecision struct {
+	SubAccountID    string    `json:"subAccountID"`
+	GlobalAccountID string    `json:"globalAccountID"`
+	RuntimeID       string    `json:"runtimeID"`
+	ShootName       string    `json:"shootName"`
+	Trackable       bool      `json:"trackable"`
+	Reason          string    `json:"reason"`
+	DecidedAt       time.Time `json:"decidedAt"`
+	// Operations are the operations considered for the decision, sorted by time.
+	// They are empty if the runtime source flagged the runtime.
+	Operations    []TrackingOperation `json:"operations,omitempty"`
+	LastOperation *TrackingOperation  `json:"lastOperation,omitempty"`
+}
 
-	// Determine trackability based on the last operation state
-	//nolint:exhaustive // we only care about the four states, not the others
-	switch lastOperation.state {
-	case provisioning, unsuspension:
-		return lastOperation.succeeded
-	case suspension, deprovisioning:
-		return false
-	default:
-		return true
+func newTrackingDecision(runtime kebruntime.RuntimeDTO) TrackingDecision {
+	return TrackingDecision{
+		SubAccountID:    runtime.SubAccountID,
+		GlobalAccountID: runtime.GlobalAccountID,
+		RuntimeID:       runtime.RuntimeID,
+		ShootName:       runtime.ShootName,
+		DecidedAt:       time.Now(),
 	}
 }
 
 func newSimpleOperation(state runtimeState, operation kebruntime.Operation) simpleOperation {
 	return simpleOperation{
-		state:     state,
-		time:      operation.CreatedAt,
-		succeeded: operationSucceeded(operation),
+		state:    state,
+		time:     operation.CreatedAt,
+		rawState: operation.State,
 	}
 }
 
-func operationSucceeded(operation kebruntime.Operation) bool {
-	return operation.State == string(kebruntime.StateSucceeded)
-}
-
 func sortOperations(runtime kebruntime.RuntimeDTO) []simpleOperation {
 	var operations []simpleOperation
 	if runtime.Status.Provisioning != nil {
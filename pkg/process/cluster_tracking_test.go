@@ -6,6 +6,8 @@ import (
 
 	kebruntime "github.com/kyma-project/kyma-environment-broker/common/runtime"
 	"github.com/onsi/gomega"
+
+	kmcruntime "github.com/kyma-project/kyma-metrics-collector/pkg/runtime"
 )
 
 // TestSortOperations tests the sortOperations function.
@@ -195,8 +197,140 @@ func TestIsRuntimeTrackable(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			result := isRuntimeTrackable(tc.runtime)
+			result := DefaultTrackingPolicy().decide(kmcruntime.Runtime{RuntimeDTO: tc.runtime}).Trackable
 			g.Expect(result).To(gomega.Equal(tc.expected))
 		})
 	}
 }
+
+func TestDecideTracking(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	billable := true
+	notBillable := false
+	created := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	operation := func(state kebruntime.State, offset time.Duration) kebruntime.Operation {
+		return kebruntime.Operation{State: string(state), CreatedAt: created.Add(offset)}
+	}
+
+	operations := func(ops ...kebruntime.Operation) *kebruntime.OperationsData {
+		return &kebruntime.OperationsData{Data: ops}
+	}
+
+	testCases := []struct {
+		name                  string
+		runtime               kmcruntime.Runtime
+		expectedTrackable     bool
+		expectedReason        string
+		expectedLastOperation string
+	}{
+		{
+			name:           "no operations",
+			expectedReason: ReasonNoOperations,
+		},
+		{
+			name: "source flagged billable",
+			runtime: kmcruntime.Runtime{
+				RuntimeDTO: kebruntime.RuntimeDTO{Status: kebruntime.RuntimeStatus{Suspension: operations(operation(kebruntime.StateSucceeded, 0))}},
+				Billable:   &billable,
+			},
+			expectedTrackable: true,
+			expectedReason:    ReasonSourceBillable,
+		},
+		{
+			name:           "source flagged not billable",
+			runtime:        kmcruntime.Runtime{Billable: &notBillable},
+			expectedReason: ReasonSourceNotBillable,
+		},
+		{
+			name: "provisioned",
+			runtime: kmcruntime.Runtime{RuntimeDTO: kebruntime.RuntimeDTO{Status: kebruntime.RuntimeStatus{
+				Provisioning: &kebruntime.Operation{State: string(kebruntime.StateSucceeded), CreatedAt: created},
+			}}},
+			expectedTrackable:     true,
+			expectedReason:        ReasonProvisioned,
+			expectedLastOperation: "provisioning",
+		},
+		{
+			name: "provisioning failed",
+			runtime: kmcruntime.Runtime{RuntimeDTO: kebruntime.RuntimeDTO{Status: kebruntime.RuntimeStatus{
+				Provisioning: &kebruntime.Operation{State: string(kebruntime.StateFailed), CreatedAt: created},
+			}}},
+			expectedReason:        ReasonProvisioningFailed,
+			expectedLastOperation: "provisioning",
+		},
+		{
+			name: "provisioning in progress",
+			runtime: kmcruntime.Runtime{RuntimeDTO: kebruntime.RuntimeDTO{Status: kebruntime.RuntimeStatus{
+				Provisioning: &kebruntime.Operation{State: "in progress", CreatedAt: created},
+			}}},
+			expectedReason:        ReasonProvisioningInProgress,
+			expectedLastOperation: "provisioning",
+		},
+		{
+			name: "suspended",
+			runtime: kmcruntime.Runtime{RuntimeDTO: kebruntime.RuntimeDTO{Status: kebruntime.RuntimeStatus{
+				Provisioning: &kebruntime.Operation{State: string(kebruntime.StateSucceeded), CreatedAt: created},
+				Suspension:   operations(operation(kebruntime.StateSucceeded, time.Hour)),
+			}}},
+			expectedReason:        ReasonSuspended,
+			expectedLastOperation: "suspension",
+		},
+		{
+			name: "unsuspended",
+			runtime: kmcruntime.Runtime{RuntimeDTO: kebruntime.RuntimeDTO{Status: kebruntime.RuntimeStatus{
+				Suspension:   operations(operation(kebruntime.StateSucceeded, time.Hour)),
+				Unsuspension: operations(operation(kebruntime.StateSucceeded, 2*time.Hour)),
+			}}},
+			expectedTrackable:     true,
+			expectedReason:        ReasonUnsuspended,
+			expectedLastOperation: "unsuspension",
+		},
+		{
+			name: "unsuspension failed",
+			runtime: kmcruntime.Runtime{RuntimeDTO: kebruntime.RuntimeDTO{Status: kebruntime.RuntimeStatus{
+				Suspension:   operations(operation(kebruntime.StateSucceeded, time.Hour)),
+				Unsuspension: operations(operation(kebruntime.StateFailed, 2*time.Hour)),
+			}}},
+			expectedReason:        ReasonUnsuspensionFailed,
+			expectedLastOperation: "unsuspension",
+		},
+		{
+			name: "deprovisioned",
+			runtime: kmcruntime.Runtime{RuntimeDTO: kebruntime.RuntimeDTO{Status: kebruntime.RuntimeStatus{
+				Provisioning:   &kebruntime.Operation{State: string(kebruntime.StateSucceeded), CreatedAt: created},
+				Deprovisioning: &kebruntime.Operation{State: "in progress", CreatedAt: created.Add(time.Hour)},
+			}}},
+			expectedReason:        ReasonDeprovisioned,
+			expectedLastOperation: "deprovisioning",
+		},
+		{
+			name: "active after upgrade",
+			runtime: kmcruntime.Runtime{RuntimeDTO: kebruntime.RuntimeDTO{Status: kebruntime.RuntimeStatus{
+				Provisioning:     &kebruntime.Operation{State: string(kebruntime.StateSucceeded), CreatedAt: created},
+				UpgradingCluster: operations(operation(kebruntime.StateFailed, time.Hour)),
+			}}},
+			expectedTrackable:     true,
+			expectedReason:        ReasonActive,
+			expectedLastOperation: "upgrading_cluster",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			decision := DefaultTrackingPolicy().decide(tc.runtime)
+			g.Expect(decision.Trackable).To(gomega.Equal(tc.expectedTrackable))
+			g.Expect(decision.Reason).To(gomega.Equal(tc.expectedReason))
+
+			if tc.expectedLastOperation == "" {
+				g.Expect(decision.LastOperation).To(gomega.BeNil())
+				return
+			}
+
+			g.Expect(decision.LastOperation).NotTo(gomega.BeNil())
+			g.Expect(decision.LastOperation.Type).To(gomega.Equal(tc.expectedLastOperation))
+			g.Expect(decision.Operations[len(decision.Operations)-1]).To(gomega.Equal(*decision.LastOperation))
+		})
+	}
+}
@@ -1,16 +1,72 @@
 package process
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"os"
+	"path"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
+	kebruntime "github.com/kyma-project/kyma-environment-broker/common/runtime"
 	"gopkg.in/yaml.v3"
+
+	log "github.com/kyma-project/kyma-metrics-collector/pkg/logger"
+	kmccache "github.com/kyma-project/kyma-metrics-collector/pkg/runtime/kubeconfigprovider"
+)
+
+// filterReloadInterval is the interval to check the filter file for changes.
+const filterReloadInterval = 30 * time.Second
+
+// Rules of the runtime filter recorded in the rule label of the filtered runtimes metric.
+const (
+	filterRuleNotAllowed = "not_allowed"
+	denyRulePrefix       = "deny_"
+
+	ruleGlobalAccount = "global_account"
+	ruleSubAccount    = "subaccount"
+	ruleRuntimeID     = "runtime_id"
+	ruleShootName     = "shoot_name"
+	ruleProvider      = "provider"
+	ruleRegion        = "region"
+	rulePlan          = "plan"
 )
 
+// accounts is the content of the filter file. The top-level globalAccounts are denied for backwards compatibility.
 type accounts struct {
-	SkippedGlobalAccounts []string `yaml:"globalAccounts"`
+	SkippedGlobalAccounts []string        `yaml:"globalAccounts"`
+	Deny                  filterRulesSpec `yaml:"deny"`
+	Allow                 filterRulesSpec `yaml:"allow"`
+}
+
+type filterRulesSpec struct {
+	GlobalAccounts []string `yaml:"globalAccounts"`
+	SubAccounts    []string `yaml:"subAccounts"`
+	RuntimeIDs     []string `yaml:"runtimeIDs"`
+	// ShootNames are patterns as supported by path.Match, e.g. c-12*.
+	ShootNames []string `yaml:"shootNames"`
+	Providers  []string `yaml:"providers"`
+	Regions    []string `yaml:"regions"`
+	Plans      []string `yaml:"plans"`
+}
+
+// runtimeFilter decides which runtimes are skipped. A runtime is skipped if it matches any deny rule,
+// or if allow rules are defined and it matches none of them. All values are compared case-insensitively.
+type runtimeFilter struct {
+	deny  filterRules
+	allow filterRules
+}
+
+type filterRules struct {
+	globalAccounts map[string]struct{}
+	subAccounts    map[string]struct{}
+	runtimeIDs     map[string]struct{}
+	shootNames     []string
+	providers      map[string]struct{}
+	regions        map[string]struct{}
+	plans          map[string]struct{}
 }
 
 func readFilterFile(file string) ([]byte, error) {
@@ -22,28 +78,227 @@ func readFilterFile(file string) ([]byte, error) {
 	return data, nil
 }
 
-func parseRuntimesToBeFiltered(data []byte) (map[string]struct{}, error) {
+func parseRuntimesToBeFiltered(data []byte) (*runtimeFilter, error) {
 	var filter accounts
 
-	skippedAccounts := make(map[string]struct{})
-	invalidGlobalAccount := []string{}
-
 	err := yaml.Unmarshal(data, &filter)
 	if err != nil {
 		return nil, err
 	}
 
-	for _, account := range filter.SkippedGlobalAccounts {
-		if err = uuid.Validate(account); err != nil {
-			invalidGlobalAccount = append(invalidGlobalAccount, account)
+	filter.Deny.GlobalAccounts = append(filter.SkippedGlobalAccounts, filter.Deny.GlobalAccounts...)
+
+	deny, err := filter.Deny.parse()
+	if err != nil {
+		return nil, err
+	}
+
+	allow, err := filter.Allow.parse()
+	if err != nil {
+		return nil, fmt.Errorf("allow list: %w", err)
+	}
+
+	return &runtimeFilter{deny: deny, allow: allow}, nil
+}
+
+func (s filterRulesSpec) parse() (filterRules, error) {
+	var rules filterRules
+
+	var err error
+	if rules.globalAccounts, err = parseUUIDs("global account", s.GlobalAccounts); err != nil {
+		return rules, err
+	}
+
+	if rules.subAccounts, err = parseUUIDs("subaccount", s.SubAccounts); err != nil {
+		return rules, err
+	}
+
+	if rules.runtimeIDs, err = parseUUIDs("runtime", s.RuntimeIDs); err != nil {
+		return rules, err
+	}
+
+	for _, pattern := range s.ShootNames {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return rules, fmt.Errorf("invalid shoot name pattern %q: %w", pattern, err)
+		}
+
+		rules.shootNames = append(rules.shootNames, strings.ToLower(pattern))
+	}
+
+	rules.providers = toSet(s.Providers)
+	rules.regions = toSet(s.Regions)
+	rules.plans = toSet(s.Plans)
+
+	return rules, nil
+}
+
+// parseUUIDs validates the IDs and returns them as set of lowercase IDs.
+func parseUUIDs(kind string, ids []string) (map[string]struct{}, error) {
+	var invalidIDs []string
+
+	for _, id := range ids {
+		if err := uuid.Validate(id); err != nil {
+			invalidIDs = append(invalidIDs, id)
+		}
+	}
+
+	if len(invalidIDs) > 0 {
+		return nil, fmt.Errorf("invalid %s IDs: %s", kind, strings.Join(invalidIDs, ", "))
+	}
+
+	return toSet(ids), nil
+}
+
+func toSet(values []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(values))
+	for _, value := range values {
+		set[strings.ToLower(value)] = struct{}{}
+	}
+
+	return set
+}
+
+func (r filterRules) empty() bool {
+	return len(r.globalAccounts) == 0 && len(r.subAccounts) == 0 && len(r.runtimeIDs) == 0 &&
+		len(r.shootNames) == 0 && len(r.providers) == 0 && len(r.regions) == 0 && len(r.plans) == 0
+}
+
+// match returns the first rule matching the runtime, or an empty string if none matches.
+func (r filterRules) match(runtime kebruntime.RuntimeDTO) string {
+	contains := func(set map[string]struct{}, value string) bool {
+		_, ok := set[strings.ToLower(value)]
+		return ok
+	}
+
+	switch {
+	case contains(r.globalAccounts, runtime.GlobalAccountID):
+		return ruleGlobalAccount
+	case contains(r.subAccounts, runtime.SubAccountID):
+		return ruleSubAccount
+	case contains(r.runtimeIDs, runtime.RuntimeID):
+		return ruleRuntimeID
+	case contains(r.providers, runtime.Provider):
+		return ruleProvider
+	case contains(r.regions, runtime.ProviderRegion):
+		return ruleRegion
+	case contains(r.plans, runtime.ServicePlanName):
+		return rulePlan
+	}
+
+	for _, pattern := range r.shootNames {
+		if matched, _ := path.Match(pattern, strings.ToLower(runtime.ShootName)); matched {
+			return ruleShootName
+		}
+	}
+
+	return ""
+}
+
+// skip returns true and the rule if the runtime is filtered.
+func (f *runtimeFilter) skip(runtime kebruntime.RuntimeDTO) (bool, string) {
+	if f == nil {
+		return false, ""
+	}
+
+	if rule := f.deny.match(runtime); rule != "" {
+		return true, denyRulePrefix + rule
+	}
+
+	if !f.allow.empty() && f.allow.match(runtime) == "" {
+		return true, filterRuleNotAllowed
+	}
+
+	return false, ""
+}
+
+// watchFilterFile reloads the filter file whenever it changes until the context is done.
+// If the changed file cannot be read or is invalid, the previous filter is kept.
+func (p *Process) watchFilterFile(ctx context.Context, data []byte) {
+	ticker := time.NewTicker(filterReloadInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		changed, err := p.reloadFilterFile(data)
+		if err != nil {
+			recordFilterReload(false)
+			p.namedLogger().With(log.KeyResult, log.ValueFail).With(log.KeyError, err.Error()).
+				Error("reload runtime filter file, keeping the previous filter")
+
 			continue
 		}
 
-		skippedAccounts[account] = struct{}{}
+		data = changed
+	}
+}
+
+// reloadFilterFile applies the filter file if its content differs from the given previous content,
+// and returns the applied content.
+func (p *Process) reloadFilterFile(previous []byte) ([]byte, error) {
+	data, err := readFilterFile(p.filterFile)
+	if err != nil {
+		return previous, err
+	}
+
+	if bytes.Equal(data, previous) {
+		return previous, nil
+	}
+
+	filter, err := parseRuntimesToBeFiltered(data)
+	if err != nil {
+		return previous, err
 	}
 
-	if len(invalidGlobalAccount) > 0 {
-		return nil, fmt.Errorf("invalid global account IDs: %s", strings.Join(invalidGlobalAccount, ", "))
+	p.runtimeFilter.Store(filter)
+	recordFilterReload(true)
+	evicted := p.evictFilteredRuntimes(filter)
+	p.namedLogger().Infof("reloaded runtime filter file, evicted %d newly filtered runtimes", evicted)
+
+	return data, nil
+}
+
+// evictFilteredRuntimes deletes the cached runtimes skipped by the filter. Their subaccounts are dropped from the
+// queue once a worker picks them up, as they are not found in the cache anymore. Runtimes which are not filtered
+// anymore are added by the next poll of the runtime source.
+func (p *Process) evictFilteredRuntimes(filter *runtimeFilter) int {
+	evicted := 0
+
+	for subAccountID, item := range p.Cache.Items() {
+		record, ok := item.Object.(kmccache.Record)
+		if !ok {
+			continue
+		}
+
+		runtime := kebruntime.RuntimeDTO{
+			SubAccountID:    subAccountID,
+			GlobalAccountID: record.GlobalAccountID,
+			RuntimeID:       record.RuntimeID,
+			InstanceID:      record.InstanceID,
+			ShootName:       record.ShootName,
+			Provider:        record.ProviderType,
+			ProviderRegion:  record.Region,
+			ServicePlanName: record.PlanName,
+		}
+
+		skip, rule := filter.skip(runtime)
+		if !skip {
+			continue
+		}
+
+		recordFilteredRuntime(rule)
+		p.namedLoggerWithRecord(&record).Infof("evicting runtime skipped by the reloaded filter rule %s", rule)
+		p.deleteTrackingDecision(subAccountID)
+		p.deleteFromCache(runtime)
+
+		evicted++
 	}
-	return skippedAccounts, nil
+
+	recordItemsInCache(float64(p.Cache.ItemCount()))
+
+	return evicted
 }
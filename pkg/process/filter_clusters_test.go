@@ -2,9 +2,21 @@ package process
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 
+	kebruntime "github.com/kyma-project/kyma-environment-broker/common/runtime"
+	gocache "github.com/patrickmn/go-cache"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+	"k8s.io/client-go/util/workqueue"
+
+	"github.com/kyma-project/kyma-metrics-collector/pkg/logger"
+	kmcruntime "github.com/kyma-project/kyma-metrics-collector/pkg/runtime"
+	kmccache "github.com/kyma-project/kyma-metrics-collector/pkg/runtime/kubeconfigprovider"
+	kmctesting "github.com/kyma-project/kyma-metrics-collector/pkg/testing"
 )
 
 func TestParseClusterToBeFiltered(t *testing.T) {
@@ -69,8 +81,165 @@ func TestParseClusterToBeFiltered(t *testing.T) {
 				require.Equal(t, tc.expectedErr.Error(), err.Error())
 			} else {
 				require.NoError(t, err)
-				require.Equal(t, tc.expected, actual)
+				require.Equal(t, toSet(keys(tc.expected)), actual.deny.globalAccounts)
 			}
 		})
 	}
 }
+
+func keys(set map[string]struct{}) []string {
+	var values []string
+	for value := range set {
+		values = append(values, value)
+	}
+
+	return values
+}
+
+func TestRuntimeFilter(t *testing.T) {
+	const (
+		globalAccountID = "8946d5de-e59a-4f4d-b65d-4595758d1fb1"
+		subAccountID    = "e653f9b0-97f1-4bf4-aaf2-268c5217cf49"
+		runtimeID       = "064894bb-8443-4728-ba66-b11cb1da2400"
+	)
+
+	runtime := kebruntime.RuntimeDTO{
+		GlobalAccountID: globalAccountID,
+		SubAccountID:    subAccountID,
+		RuntimeID:       runtimeID,
+		ShootName:       "c-354a9c2",
+		Provider:        "AWS",
+		ProviderRegion:  "eu-central-1",
+		ServicePlanName: "aws",
+	}
+
+	tt := []struct {
+		name         string
+		filter       string
+		expectedSkip bool
+		expectedRule string
+	}{
+		{name: "empty filter", filter: ``},
+		{name: "legacy global account list", filter: `globalAccounts: ["8946D5DE-E59A-4F4D-B65D-4595758D1FB1"]`, expectedSkip: true, expectedRule: "deny_global_account"},
+		{name: "deny subaccount", filter: `deny: {subAccounts: ["` + subAccountID + `"]}`, expectedSkip: true, expectedRule: "deny_subaccount"},
+		{name: "deny runtime ID", filter: `deny: {runtimeIDs: ["` + runtimeID + `"]}`, expectedSkip: true, expectedRule: "deny_runtime_id"},
+		{name: "deny shoot name pattern", filter: `deny: {shootNames: ["c-354*"]}`, expectedSkip: true, expectedRule: "deny_shoot_name"},
+		{name: "shoot name pattern not matching", filter: `deny: {shootNames: ["c-12*"]}`},
+		{name: "deny provider", filter: `deny: {providers: [aws]}`, expectedSkip: true, expectedRule: "deny_provider"},
+		{name: "deny region", filter: `deny: {regions: [eu-central-1]}`, expectedSkip: true, expectedRule: "deny_region"},
+		{name: "deny plan", filter: `deny: {plans: [aws, trial]}`, expectedSkip: true, expectedRule: "deny_plan"},
+		{name: "allowed by subaccount", filter: `allow: {subAccounts: ["` + subAccountID + `"]}`},
+		{name: "allowed by region", filter: `allow: {regions: [us-east-1, eu-central-1]}`},
+		{name: "not allowed", filter: `allow: {providers: [gcp]}`, expectedSkip: true, expectedRule: "not_allowed"},
+		{
+			name:         "deny takes precedence over allow",
+			filter:       "allow: {providers: [aws]}\ndeny: {plans: [aws]}",
+			expectedSkip: true,
+			expectedRule: "deny_plan",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			filter, err := parseRuntimesToBeFiltered([]byte(tc.filter))
+			require.NoError(t, err)
+
+			skip, rule := filter.skip(runtime)
+			require.Equal(t, tc.expectedSkip, skip)
+			require.Equal(t, tc.expectedRule, rule)
+		})
+	}
+}
+
+func TestParseRuntimeFilterErrors(t *testing.T) {
+	tt := []struct {
+		name        string
+		filter      string
+		expectedErr string
+	}{
+		{name: "invalid subaccount", filter: `deny: {subAccounts: [foo]}`, expectedErr: "invalid subaccount IDs: foo"},
+		{name: "invalid runtime ID", filter: `allow: {runtimeIDs: [foo]}`, expectedErr: "allow list: invalid runtime IDs: foo"},
+		{name: "invalid shoot name pattern", filter: `deny: {shootNames: ["c-["]}`, expectedErr: `invalid shoot name pattern "c-["`},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := parseRuntimesToBeFiltered([]byte(tc.filter))
+			require.ErrorContains(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestReloadFilterFile(t *testing.T) {
+	const (
+		subAccountID     = "e653f9b0-97f1-4bf4-aaf2-268c5217cf49"
+		keptSubAccountID = "8946d5de-e59a-4f4d-b65d-4595758d1fb1"
+	)
+
+	runtime := kebruntime.RuntimeDTO{SubAccountID: subAccountID}
+	file := filepath.Join(t.TempDir(), "filter.yaml")
+	require.NoError(t, os.WriteFile(file, []byte(``), 0o600))
+
+	p := Process{
+		Logger:     logger.NewLogger(zapcore.InfoLevel),
+		Cache:      gocache.New(gocache.NoExpiration, gocache.NoExpiration),
+		filterFile: file,
+	}
+	require.NoError(t, p.Cache.Add(subAccountID, kmccache.Record{SubAccountID: subAccountID, ShootName: "c-1", PlanName: "aws"}, gocache.NoExpiration))
+	require.NoError(t, p.Cache.Add(keptSubAccountID, kmccache.Record{SubAccountID: keptSubAccountID, ShootName: "c-2", PlanName: "aws"}, gocache.NoExpiration))
+
+	data, err := p.reloadFilterFile(nil)
+	require.NoError(t, err)
+
+	skip, _ := p.runtimeFilter.Load().skip(runtime)
+	require.False(t, skip)
+	require.Equal(t, 2, p.Cache.ItemCount())
+
+	// a changed file is applied and the newly filtered runtimes are evicted
+	filteredBefore := testutil.ToFloat64(filteredRuntimes.WithLabelValues("deny_subaccount"))
+
+	require.NoError(t, os.WriteFile(file, []byte(`deny: {subAccounts: ["`+subAccountID+`"]}`), 0o600))
+	data, err = p.reloadFilterFile(data)
+	require.NoError(t, err)
+
+	skip, _ = p.runtimeFilter.Load().skip(runtime)
+	require.True(t, skip)
+
+	_, found := p.Cache.Get(subAccountID)
+	require.False(t, found)
+	_, found = p.Cache.Get(keptSubAccountID)
+	require.True(t, found)
+	require.Equal(t, filteredBefore+1, testutil.ToFloat64(filteredRuntimes.WithLabelValues("deny_subaccount")))
+
+	// an invalid file keeps the previous filter
+	require.NoError(t, os.WriteFile(file, []byte(`deny: {subAccounts: [foo]}`), 0o600))
+	unchanged, err := p.reloadFilterFile(data)
+	require.Error(t, err)
+	require.Equal(t, data, unchanged)
+
+	skip, _ = p.runtimeFilter.Load().skip(runtime)
+	require.True(t, skip)
+	require.Equal(t, 1, p.Cache.ItemCount())
+}
+
+func TestProcessRuntimeEvictsFilteredRuntime(t *testing.T) {
+	const subAccountID = "e653f9b0-97f1-4bf4-aaf2-268c5217cf49"
+
+	p := Process{
+		Logger: logger.NewLogger(zapcore.InfoLevel),
+		Cache:  gocache.New(gocache.NoExpiration, gocache.NoExpiration),
+		Queue:  workqueue.NewTypedDelayingQueue[string](),
+	}
+
+	runtime := kmctesting.NewRuntimesDTO(subAccountID, "c-1", kmctesting.WithProvisioningSucceededStatus(kebruntime.StateSucceeded))
+	p.updateCacheAndQueue(kmcruntime.FromKEB([]kebruntime.RuntimeDTO{runtime}))
+	require.Equal(t, 1, p.Cache.ItemCount())
+
+	filter, err := parseRuntimesToBeFiltered([]byte(`deny: {subAccounts: ["` + subAccountID + `"]}`))
+	require.NoError(t, err)
+	p.runtimeFilter.Store(filter)
+
+	p.updateCacheAndQueue(kmcruntime.FromKEB([]kebruntime.RuntimeDTO{runtime}))
+	require.Equal(t, 0, p.Cache.ItemCount())
+	require.Empty(t, p.TrackingDecisions())
+}
@@ -2,162 +2,86 @@ package process
 
 import (
 	"strings"
-	"time"
 
 	kebruntime "github.com/kyma-project/kyma-environment-broker/common/runtime"
 	"github.com/patrickmn/go-cache"
 	"go.uber.org/zap"
 
 	log "github.com/kyma-project/kyma-metrics-collector/pkg/logger"
+	kmcruntime "github.com/kyma-project/kyma-metrics-collector/pkg/runtime"
 	kmccache "github.com/kyma-project/kyma-metrics-collector/pkg/runtime/kubeconfigprovider"
 )
 
-// pollKEBForRuntimes polls KEB for runtimes information.
-func (p *Process) pollKEBForRuntimes() {
-	kebReq, err := p.KEBClient.NewRequest()
-	if err != nil {
-		p.namedLogger().With(log.KeyResult, log.ValueFail).With(log.KeyError, err.Error()).
-			Fatal("create a new request for KEB")
-	}
-
-	for {
-		runtimesPage, err := p.KEBClient.GetAllRuntimes(kebReq)
-		if err != nil {
-			p.namedLogger().With(log.KeyResult, log.ValueFail).With(log.KeyError, err.Error()).
-				Error("get runtimes from KEB")
-			p.namedLogger().Infof("waiting to poll KEB again after %v....", p.KEBClient.Config.PollWaitDuration)
-			time.Sleep(p.KEBClient.Config.PollWaitDuration)
+// Resync replaces the tracked runtimes with the complete list of runtimes from the runtime source.
+func (p *Process) Resync(runtimes []kmcruntime.Runtime) {
+	p.populateCacheAndQueue(runtimes)
+	p.namedLogger().Debugf("length of the kubeconfigprovider after resync: %d", p.Cache.ItemCount())
+	recordItemsInCache(float64(p.Cache.ItemCount()))
+}
 
-			continue
-		}
+// Update adds or updates the runtimes from the runtime source.
+func (p *Process) Update(runtimes []kmcruntime.Runtime) {
+	p.updateCacheAndQueue(runtimes)
+	recordItemsInCache(float64(p.Cache.ItemCount()))
+}
 
-		p.namedLogger().Debugf("num of runtimes are: %d", runtimesPage.Count)
-		p.populateCacheAndQueue(runtimesPage)
-		p.namedLogger().Debugf("length of the kubeconfigprovider after KEB is done populating: %d", p.Cache.ItemCount())
-		p.namedLogger().Infof("waiting to poll KEB again after %v....", p.KEBClient.Config.PollWaitDuration)
-		recordItemsInCache(float64(p.Cache.ItemCount()))
-		time.Sleep(p.KEBClient.Config.PollWaitDuration)
-	}
+// Delete stops tracking a runtime deleted in the runtime source.
+func (p *Process) Delete(runtime kmcruntime.Runtime) {
+	p.deleteTrackingDecision(runtime.SubAccountID)
+	p.deleteFromCache(runtime.RuntimeDTO)
+	recordItemsInCache(float64(p.Cache.ItemCount()))
 }
 
 // populateCacheAndQueue populates Cache and Queue with new runtimes and deletes the runtimes which should not be tracked.
-func (p *Process) populateCacheAndQueue(runtimes *kebruntime.RuntimesPage) {
+// The runtimes must be the complete list of runtimes, as all subaccounts missing in it are deleted from the Cache.
+func (p *Process) populateCacheAndQueue(runtimes []kmcruntime.Runtime) {
 	// clear the gauge to fill it with the new data
 	kebFetchedClusters.Reset()
 
 	validSubAccounts := make(map[string]bool)
 
-	for _, runtime := range runtimes.Data {
+	for _, runtime := range runtimes {
 		if runtime.SubAccountID == "" {
 			continue
 		}
 
 		validSubAccounts[runtime.SubAccountID] = true
-		recordObj, isFoundInCache := p.Cache.Get(runtime.SubAccountID)
-
-		// Get provisioning and deprovisioning states if available otherwise return empty string for logging.
-		provisioning := getOrDefault(runtime.Status.Provisioning, "")
-		deprovisioning := getOrDefault(runtime.Status.Deprovisioning, "")
-		p.namedLoggerWithRuntime(runtime).
-			With(log.KeyRuntimeState, runtime.Status.State).
-			With(log.KeyProvisioningStatus, provisioning).
-			With(log.KeyDeprovisioningStatus, deprovisioning).
-			Debug("Runtime state")
-
-		if p.skipRuntime(runtime) {
-			p.namedLogger().Infof("skipping runtime with globalAccountID: %s subAccountID: %s, "+
-				"runtimeID: %s, shootName: %s, instanceID: %s",
-				runtime.GlobalAccountID, runtime.SubAccountID, runtime.RuntimeID, runtime.ShootName, runtime.InstanceID)
-			continue
-		}
-
-		if isRuntimeTrackable(runtime) {
-			newRecord := kmccache.Record{
-				SubAccountID:    runtime.SubAccountID,
-				RuntimeID:       runtime.RuntimeID,
-				InstanceID:      runtime.InstanceID,
-				GlobalAccountID: runtime.GlobalAccountID,
-				ShootName:       runtime.ShootName,
-				ProviderType:    strings.ToLower(runtime.Provider),
-				ScanMap:         nil,
-			}
 
-			// record kebFetchedClusters metric for trackable cluster
+		// the metric is only recorded on resyncs, so it counts every runtime once per resync
+		if decision, decided := p.processRuntime(runtime); decided {
 			recordKEBFetchedClusters(
-				trackableTrue,
+				decision,
 				runtime.ShootName,
 				runtime.InstanceID,
 				runtime.RuntimeID,
 				runtime.SubAccountID,
 				runtime.GlobalAccountID)
-
-			// Cluster is trackable but does not exist in the kubeconfigprovider
-			if !isFoundInCache {
-				err := p.Cache.Add(runtime.SubAccountID, newRecord, cache.NoExpiration)
-				if err != nil {
-					p.namedLoggerWithRecord(&newRecord).With(log.KeyResult, log.ValueFail).With(log.KeyError, err.Error()).Error("Failed to add subAccountID to kubeconfigprovider. Skipping queueing it")
-					continue
-				}
-
-				p.Queue.Add(runtime.SubAccountID)
-				p.namedLoggerWithRecord(&newRecord).With(log.KeyResult, log.ValueSuccess).Debug("Queued and added to kubeconfigprovider")
-
-				continue
-			}
-
-			// Cluster is trackable and exists in the kubeconfigprovider
-			if record, ok := recordObj.(kmccache.Record); ok {
-				if record.ShootName == runtime.ShootName {
-					continue
-				}
-				// The shootname has changed hence the record in the kubeconfigprovider is not valid anymore
-				// No need to queue as the subAccountID already exists in queue
-				p.Cache.Set(runtime.SubAccountID, newRecord, cache.NoExpiration)
-				p.namedLoggerWithRecord(&record).Debug("Resetted the values in kubeconfigprovider for subAccount")
-
-				// delete metrics for old shoot name.
-				if success := deleteMetrics(record); !success {
-					p.namedLoggerWithRecord(&record).Warn("prometheus metrics were not successfully removed for subAccount")
-				}
-			}
-
-			continue
 		}
+	}
 
-		// record kebFetchedClusters metric for not trackable clusters
-		recordKEBFetchedClusters(
-			trackableFalse,
-			runtime.ShootName,
-			runtime.InstanceID,
-			runtime.RuntimeID,
-			runtime.SubAccountID,
-			runtime.GlobalAccountID)
-
-		if isFoundInCache {
-			// Cluster is not trackable but is found in kubeconfigprovider should be deleted
-			p.Cache.Delete(runtime.SubAccountID)
-			p.namedLogger().With(log.KeySubAccountID, runtime.SubAccountID).
-				With(log.KeyRuntimeID, runtime.RuntimeID).Debug("Deleted subAccount from kubeconfigprovider")
-			// delete metrics for old shoot name.
-			if record, ok := recordObj.(kmccache.Record); ok {
-				if success := deleteMetrics(record); !success {
-					p.namedLoggerWithRecord(&record).Info("prometheus metrics were not successfully removed for subAccount")
-				}
-			}
+	cachedItems := p.Cache.Items()
+	staleSubAccounts := 0
 
-			continue
+	for sAccID := range cachedItems {
+		if _, ok := validSubAccounts[sAccID]; !ok {
+			staleSubAccounts++
 		}
+	}
+
+	p.pruneTrackingDecisions(validSubAccounts)
 
-		p.namedLogger().With(log.KeySubAccountID, runtime.SubAccountID).
-			With(log.KeyRuntimeID, runtime.RuntimeID).Debug("Ignoring SubAccount as it is not trackable")
+	if !p.allowEviction(staleSubAccounts, len(cachedItems)) {
+		return
 	}
 
 	// Cleaning up subAccounts from the kubeconfigprovider which are not returned by KEB anymore
-	for sAccID, recordObj := range p.Cache.Items() {
+	for sAccID, recordObj := range cachedItems {
 		if _, ok := validSubAccounts[sAccID]; !ok {
 			record, ok := recordObj.Object.(kmccache.Record)
 
+			p.recordsLock.Lock()
 			p.Cache.Delete(sAccID)
+			p.recordsLock.Unlock()
 
 			if !ok {
 				p.namedLoggerWithRecord(&record).
@@ -165,6 +89,7 @@ func (p *Process) populateCacheAndQueue(runtimes *kebruntime.RuntimesPage) {
 			} else {
 				p.namedLoggerWithRecord(&record).
 					Info("SubAccount is not trackable anymore, deleting it from kubeconfigprovider")
+				p.evictClients(record.RuntimeID)
 			}
 			// delete metrics for old shoot name.
 			if success := deleteMetrics(record); !success {
@@ -175,6 +100,212 @@ func (p *Process) populateCacheAndQueue(runtimes *kebruntime.RuntimesPage) {
 	}
 }
 
+// allowEviction is the guard against a KEB response missing a large part of the runtimes, e.g. because of a KEB bug.
+// If more than MaxEvictionPercentage of the tracked subaccounts would be evicted, the eviction is only allowed
+// once the configured number of consecutive resyncs tripped the guard. Until then, the cached records are kept.
+func (p *Process) allowEviction(staleSubAccounts, trackedSubAccounts int) bool {
+	if p.EvictionGuard.MaxEvictionPercentage <= 0 || staleSubAccounts == 0 ||
+		float64(staleSubAccounts)*100 <= p.EvictionGuard.MaxEvictionPercentage*float64(trackedSubAccounts) {
+		p.evictionGuardTrips = 0
+		return true
+	}
+
+	p.evictionGuardTrips++
+	recordEvictionGuardTrip()
+
+	logger := p.namedLogger().
+		With("stale_sub_accounts", staleSubAccounts).
+		With("tracked_sub_accounts", trackedSubAccounts).
+		With("consecutive_trips", p.evictionGuardTrips)
+
+	if p.evictionGuardTrips >= p.EvictionGuard.Confirmations {
+		logger.Warnf("eviction of more than %v%% of the tracked subaccounts is confirmed by consecutive polls, evicting them", p.EvictionGuard.MaxEvictionPercentage)
+		p.evictionGuardTrips = 0
+
+		return true
+	}
+
+	logger.With(log.KeyResult, log.ValueFail).
+		Errorf("eviction guard tripped: KEB did not return more than %v%% of the tracked subaccounts, keeping them until confirmed by %d consecutive polls",
+			p.EvictionGuard.MaxEvictionPercentage, p.EvictionGuard.Confirmations)
+
+	return false
+}
+
+// updateCacheAndQueue adds, updates and deletes the given runtimes in Cache and Queue.
+// In contrast to populateCacheAndQueue, subaccounts missing in the runtimes are kept, so it can be used for sources
+// which only pass the changed runtimes.
+func (p *Process) updateCacheAndQueue(runtimes []kmcruntime.Runtime) {
+	for _, runtime := range runtimes {
+		if runtime.SubAccountID == "" {
+			continue
+		}
+
+		p.processRuntime(runtime)
+	}
+}
+
+// processRuntime adds a trackable runtime to Cache and Queue, updates it if it changed, or deletes it if it is not trackable anymore.
+// It returns the tracking decision, or false if the runtime is skipped by the runtime filter.
+func (p *Process) processRuntime(sourceRuntime kmcruntime.Runtime) (TrackingDecision, bool) {
+	runtime := sourceRuntime.RuntimeDTO
+
+	p.recordsLock.Lock()
+	defer p.recordsLock.Unlock()
+
+	recordObj, isFoundInCache := p.Cache.Get(runtime.SubAccountID)
+
+	// Get provisioning and deprovisioning states if available otherwise return empty string for logging.
+	provisioning := getOrDefault(runtime.Status.Provisioning, "")
+	deprovisioning := getOrDefault(runtime.Status.Deprovisioning, "")
+	p.namedLoggerWithRuntime(runtime).
+		With(log.KeyRuntimeState, runtime.Status.State).
+		With(log.KeyProvisioningStatus, provisioning).
+		With(log.KeyDeprovisioningStatus, deprovisioning).
+		Debug("Runtime state")
+
+	if skip, rule := p.runtimeFilter.Load().skip(runtime); skip {
+		recordFilteredRuntime(rule)
+		p.namedLogger().Infof("skipping runtime with globalAccountID: %s subAccountID: %s, "+
+			"runtimeID: %s, shootName: %s, instanceID: %s, filter rule: %s",
+			runtime.GlobalAccountID, runtime.SubAccountID, runtime.RuntimeID, runtime.ShootName, runtime.InstanceID, rule)
+
+		// the runtime may have been tracked before it was filtered
+		p.deleteTrackingDecision(runtime.SubAccountID)
+		p.deleteFromCacheLocked(runtime)
+
+		return TrackingDecision{}, false
+	}
+
+	decision := p.trackingPolicy().decide(sourceRuntime)
+	p.recordTrackingDecision(decision)
+
+	if decision.Trackable {
+		newRecord := kmccache.Record{
+			SubAccountID:    runtime.SubAccountID,
+			RuntimeID:       runtime.RuntimeID,
+			InstanceID:      runtime.InstanceID,
+			GlobalAccountID: runtime.GlobalAccountID,
+			ShootName:       runtime.ShootName,
+			ProviderType:    strings.ToLower(runtime.Provider),
+			Region:          strings.ToLower(runtime.ProviderRegion),
+			PlanName:        strings.ToLower(runtime.ServicePlanName),
+			ScanMap:         nil,
+		}
+
+		// Cluster is trackable but does not exist in the kubeconfigprovider
+		if !isFoundInCache {
+			err := p.Cache.Add(runtime.SubAccountID, newRecord, cache.NoExpiration)
+			if err != nil {
+				p.namedLoggerWithRecord(&newRecord).With(log.KeyResult, log.ValueFail).With(log.KeyError, err.Error()).Error("Failed to add subAccountID to kubeconfigprovider. Skipping queueing it")
+				return decision, true
+			}
+
+			p.Queue.Add(runtime.SubAccountID)
+			p.namedLoggerWithRecord(&newRecord).With(log.KeyResult, log.ValueSuccess).Debug("Queued and added to kubeconfigprovider")
+
+			return decision, true
+		}
+
+		// Cluster is trackable and exists in the kubeconfigprovider
+		if record, ok := recordObj.(kmccache.Record); ok {
+			if record.ShootName == runtime.ShootName {
+				// The region or plan can change without a new shoot, e.g. on a plan upgrade.
+				// The previous scans stay valid, so only the pricing relevant fields are updated.
+				if record.Region != newRecord.Region || record.PlanName != newRecord.PlanName {
+					record.Region = newRecord.Region
+					record.PlanName = newRecord.PlanName
+					p.Cache.Set(runtime.SubAccountID, record, cache.NoExpiration)
+					p.namedLoggerWithRecord(&record).Debug("Updated region and plan in kubeconfigprovider for subAccount")
+				}
+
+				return decision, true
+			}
+			// The shootname has changed hence the record in the kubeconfigprovider is not valid anymore
+			// No need to queue as the subAccountID already exists in queue
+			p.Cache.Set(runtime.SubAccountID, newRecord, cache.NoExpiration)
+			p.evictClients(record.RuntimeID)
+			p.namedLoggerWithRecord(&record).Debug("Resetted the values in kubeconfigprovider for subAccount")
+
+			// delete metrics for old shoot name.
+			if success := deleteMetrics(record); !success {
+				p.namedLoggerWithRecord(&record).Warn("prometheus metrics were not successfully removed for subAccount")
+			}
+		}
+
+		return decision, true
+	}
+
+	if isFoundInCache {
+		// Cluster is not trackable but is found in kubeconfigprovider should be deleted
+		p.deleteFromCacheLocked(runtime)
+		return decision, true
+	}
+
+	p.namedLogger().With(log.KeySubAccountID, runtime.SubAccountID).
+		With(log.KeyRuntimeID, runtime.RuntimeID).With(log.KeyTrackingReason, decision.Reason).
+		Debug("Ignoring SubAccount as it is not trackable")
+
+	return decision, true
+}
+
+// deleteFromCache deletes the subaccount of the runtime from the Cache together with its metrics.
+func (p *Process) deleteFromCache(runtime kebruntime.RuntimeDTO) {
+	p.recordsLock.Lock()
+	defer p.recordsLock.Unlock()
+
+	p.deleteFromCacheLocked(runtime)
+}
+
+func (p *Process) deleteFromCacheLocked(runtime kebruntime.RuntimeDTO) {
+	recordObj, isFoundInCache := p.Cache.Get(runtime.SubAccountID)
+	if !isFoundInCache {
+		return
+	}
+
+	p.Cache.Delete(runtime.SubAccountID)
+	p.namedLogger().With(log.KeySubAccountID, runtime.SubAccountID).
+		With(log.KeyRuntimeID, runtime.RuntimeID).Debug("Deleted subAccount from kubeconfigprovider")
+	// delete metrics for old shoot name.
+	if record, ok := recordObj.(kmccache.Record); ok {
+		p.evictClients(record.RuntimeID)
+
+		if success := deleteMetrics(record); !success {
+			p.namedLoggerWithRecord(&record).Info("prometheus metrics were not successfully removed for subAccount")
+		}
+	}
+}
+
+// updateRecord applies update to the record of the subaccount in Cache and stores it, so the fields changed by the
+// runtime source since the record was read are kept. It returns false if the runtime source deleted the record or
+// replaced it with the record of another runtime or shoot in the meantime, and then leaves Cache unchanged.
+func (p *Process) updateRecord(read kmccache.Record, update func(record *kmccache.Record)) bool {
+	p.recordsLock.Lock()
+	defer p.recordsLock.Unlock()
+
+	recordObj, found := p.Cache.Get(read.SubAccountID)
+	if !found {
+		return false
+	}
+
+	record, ok := recordObj.(kmccache.Record)
+	if !ok || record.RuntimeID != read.RuntimeID || record.ShootName != read.ShootName {
+		return false
+	}
+
+	update(&record)
+	p.Cache.Set(record.SubAccountID, record, cache.NoExpiration)
+
+	return true
+}
+
+// evictClients closes the pooled clients of a runtime which is not tracked anymore.
+func (p *Process) evictClients(runtimeID string) {
+	if p.ClientPool != nil {
+		p.ClientPool.Evict(runtimeID)
+	}
+}
+
 // getOrDefault returns the runtime state or a default value if runtimeStatus is nil.
 func getOrDefault(runtimeStatus *kebruntime.Operation, defaultValue string) string {
 	if runtimeStatus != nil {
@@ -201,8 +332,3 @@ func (p *Process) namedLoggerWithRecord(record *kmccache.Record) *zap.SugaredLog
 func (p *Process) namedLoggerWithRuntime(runtime kebruntime.RuntimeDTO) *zap.SugaredLogger {
 	return p.Logger.With("component", "kmc").With(log.KeyRuntimeID, runtime.RuntimeID).With(log.KeyShoot, runtime.ShootName).With(log.KeySubAccountID, runtime.SubAccountID).With(log.KeyGlobalAccountID, runtime.GlobalAccountID)
 }
-
-func (p *Process) skipRuntime(runtime kebruntime.RuntimeDTO) bool {
-	_, ok := p.globalAccToBeFiltered[runtime.GlobalAccountID]
-	return ok
-}
@@ -7,6 +7,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promauto"
 
 	"github.com/kyma-project/kyma-metrics-collector/pkg/collector"
+	"github.com/kyma-project/kyma-metrics-collector/pkg/resource/node"
 	kmccache "github.com/kyma-project/kyma-metrics-collector/pkg/runtime/kubeconfigprovider"
 )
 
@@ -20,6 +21,8 @@ const (
 	globalAccountLabel = "global_account_id"
 	successLabel       = "success"
 	trackableLabel     = "trackable"
+	reasonLabel        = "reason"
+	ruleLabel          = "rule"
 )
 
 var (
@@ -56,10 +59,60 @@ var (
 			Name:      "fetched_clusters_total",
 			Help:      "All clusters fetched from KEB, including trackable and not trackable.",
 		},
-		[]string{trackableLabel, shootNameLabel, instanceIdLabel, runtimeIdLabel, subAccountLabel, globalAccountLabel},
+		[]string{trackableLabel, reasonLabel, shootNameLabel, instanceIdLabel, runtimeIdLabel, subAccountLabel, globalAccountLabel},
 	)
+	filteredRuntimes = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "filtered_runtimes_total",
+			Help:      "Number of runtimes skipped by the runtime filter per rule.",
+		},
+		[]string{ruleLabel},
+	)
+	filterReloads = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "filter_reloads_total",
+			Help:      "Number of reloads of the changed runtime filter file, including successful and failed.",
+		},
+		[]string{successLabel},
+	)
+	unauthorizedRetries = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "unauthorized_retries_total",
+			Help:      "Number of retries with a refreshed kubeconfig after a runtime rejected the credentials, including successful and failed.",
+		},
+		[]string{successLabel},
+	)
+	evictionGuardTrips = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "eviction_guard_trips_total",
+			Help:      "Number of full resyncs with KEB which would have evicted more than the allowed percentage of the tracked subaccounts.",
+		}, nil)
 )
 
+func recordEvictionGuardTrip() {
+	evictionGuardTrips.WithLabelValues().Inc()
+}
+
+func recordFilteredRuntime(rule string) {
+	filteredRuntimes.WithLabelValues(rule).Inc()
+}
+
+func recordUnauthorizedRetry(success bool) {
+	unauthorizedRetries.WithLabelValues(strconv.FormatBool(success)).Inc()
+}
+
+func recordFilterReload(success bool) {
+	filterReloads.WithLabelValues(strconv.FormatBool(success)).Inc()
+}
+
 func recordItemsInCache(count float64) {
 	itemsInCache.WithLabelValues().Set(count)
 }
@@ -87,10 +140,11 @@ func recordSubAccountProcessedTimeStamp(shootInfo kmccache.Record) {
 	).SetToCurrentTime()
 }
 
-func recordKEBFetchedClusters(trackable bool, shootName, instanceID, runtimeID, subAccountID, globalAccountID string) {
+func recordKEBFetchedClusters(decision TrackingDecision, shootName, instanceID, runtimeID, subAccountID, globalAccountID string) {
 	// the order of the values should be same as defined in the metric declaration.
 	kebFetchedClusters.WithLabelValues(
-		strconv.FormatBool(trackable),
+		strconv.FormatBool(decision.Trackable),
+		decision.Reason,
 		shootName,
 		instanceID,
 		runtimeID,
@@ -115,6 +169,7 @@ func deleteMetrics(shootInfo kmccache.Record) bool {
 	count += subAccountProcessedTimeStamp.DeletePartialMatch(matchLabels)
 	count += collector.TotalScans.DeletePartialMatch(matchLabels)
 	count += collector.TotalScansConverted.DeletePartialMatch(matchLabels)
+	count += node.ExcludedNodes.DeletePartialMatch(matchLabels)
 
 	return count > 0
 }
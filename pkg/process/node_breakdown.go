@@ -0,0 +1,98 @@
+package process
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+
+	log "github.com/kyma-project/kyma-metrics-collector/pkg/logger"
+	"github.com/kyma-project/kyma-metrics-collector/pkg/resource"
+	kmccache "github.com/kyma-project/kyma-metrics-collector/pkg/runtime/kubeconfigprovider"
+)
+
+// NodeBreakdownPath is the path of the admin endpoint listing the nodes of the runtimes per worker pool and zone.
+const NodeBreakdownPath = "/admin/node-breakdown"
+
+// RuntimeNodeBreakdown is the breakdown of the nodes of a runtime in its last successful scrape.
+type RuntimeNodeBreakdown struct {
+	SubAccountID    string                 `json:"subAccountID"`
+	GlobalAccountID string                 `json:"globalAccountID"`
+	RuntimeID       string                 `json:"runtimeID"`
+	ShootName       string                 `json:"shootName"`
+	Nodes           resource.NodeBreakdown `json:"nodes"`
+}
+
+// NodeBreakdowns returns the node breakdown of every runtime scraped successfully, sorted by subaccount.
+func (p *Process) NodeBreakdowns() []RuntimeNodeBreakdown {
+	breakdowns := []RuntimeNodeBreakdown{}
+
+	for _, item := range p.Cache.Items() {
+		record, ok := item.Object.(kmccache.Record)
+		if !ok {
+			continue
+		}
+
+		if breakdown, found := nodeBreakdownOf(record); found {
+			breakdowns = append(breakdowns, breakdown)
+		}
+	}
+
+	sort.Slice(breakdowns, func(i, j int) bool {
+		return breakdowns[i].SubAccountID < breakdowns[j].SubAccountID
+	})
+
+	return breakdowns
+}
+
+// nodeBreakdownOf returns the node breakdown of the scans of the record, or false if none of its scans breaks down
+// the nodes, e.g. because the runtime was not scraped yet.
+func nodeBreakdownOf(record kmccache.Record) (RuntimeNodeBreakdown, bool) {
+	for _, scan := range record.ScanMap {
+		breakdowner, ok := scan.(resource.NodeBreakdowner)
+		if !ok {
+			continue
+		}
+
+		// the scans in the record were converted to EDP measurements successfully, so their nodes are known
+		nodes, _ := breakdowner.NodeBreakdown()
+
+		return RuntimeNodeBreakdown{
+			SubAccountID:    record.SubAccountID,
+			GlobalAccountID: record.GlobalAccountID,
+			RuntimeID:       record.RuntimeID,
+			ShootName:       record.ShootName,
+			Nodes:           nodes,
+		}, true
+	}
+
+	return RuntimeNodeBreakdown{}, false
+}
+
+// ServeNodeBreakdowns serves the node breakdowns as JSON. The optional subaccount query parameter returns only the
+// breakdown of that subaccount, or 404 if there is none.
+func (p *Process) ServeNodeBreakdowns(writer http.ResponseWriter, request *http.Request) {
+	var response any
+
+	if subAccountID := request.URL.Query().Get(subAccountQueryParam); subAccountID == "" {
+		response = p.NodeBreakdowns()
+	} else {
+		// a missing or unexpected cache item has no scans, so no breakdown is found
+		item, _ := p.Cache.Get(subAccountID)
+		record, _ := item.(kmccache.Record)
+
+		breakdown, found := nodeBreakdownOf(record)
+		if !found {
+			http.Error(writer, "no node breakdown for subaccount "+subAccountID, http.StatusNotFound)
+			return
+		}
+
+		response = breakdown
+	}
+
+	writer.Header().Set("Content-Type", "application/json")
+
+	if err := json.NewEncoder(writer).Encode(response); err != nil {
+		p.namedLogger().With(log.KeyResult, log.ValueFail).With(log.KeyError, err.Error()).
+			Error("write node breakdown response")
+	}
+}
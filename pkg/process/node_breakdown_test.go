@@ -0,0 +1,94 @@
+package process
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	gocache "github.com/patrickmn/go-cache"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/kyma-project/kyma-metrics-collector/pkg/collector"
+	"github.com/kyma-project/kyma-metrics-collector/pkg/logger"
+	"github.com/kyma-project/kyma-metrics-collector/pkg/process/stubs"
+	"github.com/kyma-project/kyma-metrics-collector/pkg/resource"
+	kmccache "github.com/kyma-project/kyma-metrics-collector/pkg/runtime/kubeconfigprovider"
+)
+
+// nodeBreakdownScan is a scan breaking down the nodes into the given breakdown.
+type nodeBreakdownScan struct {
+	stubs.Scan
+
+	breakdown resource.NodeBreakdown
+}
+
+func (s nodeBreakdownScan) NodeBreakdown() (resource.NodeBreakdown, error) {
+	return s.breakdown, nil
+}
+
+func TestServeNodeBreakdowns(t *testing.T) {
+	breakdown := resource.NodeBreakdown{
+		Pools: []resource.NodeGroup{{Name: "cpu-worker-0", Architectures: []string{"amd64"}, Count: 2}},
+		Zones: []resource.NodeGroup{{Name: "eu-central-1a", Architectures: []string{"amd64"}, Count: 2}},
+	}
+
+	p := Process{
+		Cache:  gocache.New(gocache.NoExpiration, gocache.NoExpiration),
+		Logger: logger.NewLogger(zapcore.InfoLevel),
+	}
+
+	p.Cache.Set("subaccount-b", kmccache.Record{
+		SubAccountID: "subaccount-b",
+		RuntimeID:    "runtime-b",
+		ShootName:    "shoot-b",
+		ScanMap: collector.ScanMap{
+			"node": nodeBreakdownScan{breakdown: breakdown},
+			"pvc":  stubs.NewScan(nil),
+		},
+	}, gocache.NoExpiration)
+	p.Cache.Set("subaccount-a", kmccache.Record{
+		SubAccountID: "subaccount-a",
+		RuntimeID:    "runtime-a",
+		ScanMap:      collector.ScanMap{"node": nodeBreakdownScan{}},
+	}, gocache.NoExpiration)
+	p.Cache.Set("subaccount-c", kmccache.Record{SubAccountID: "subaccount-c", RuntimeID: "runtime-c"}, gocache.NoExpiration)
+
+	t.Run("lists the breakdowns of all scraped runtimes", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		p.ServeNodeBreakdowns(recorder, httptest.NewRequest(http.MethodGet, NodeBreakdownPath, nil))
+		require.Equal(t, http.StatusOK, recorder.Code)
+
+		var breakdowns []RuntimeNodeBreakdown
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &breakdowns))
+		require.Len(t, breakdowns, 2)
+		require.Equal(t, "subaccount-a", breakdowns[0].SubAccountID)
+		require.Equal(t, "subaccount-b", breakdowns[1].SubAccountID)
+		require.Equal(t, breakdown, breakdowns[1].Nodes)
+	})
+
+	t.Run("returns the breakdown of a subaccount", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		p.ServeNodeBreakdowns(recorder, httptest.NewRequest(http.MethodGet, NodeBreakdownPath+"?subaccount=subaccount-b", nil))
+		require.Equal(t, http.StatusOK, recorder.Code)
+
+		var runtimeBreakdown RuntimeNodeBreakdown
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &runtimeBreakdown))
+		require.Equal(t, "runtime-b", runtimeBreakdown.RuntimeID)
+		require.Equal(t, "shoot-b", runtimeBreakdown.ShootName)
+		require.Equal(t, breakdown, runtimeBreakdown.Nodes)
+	})
+
+	t.Run("returns not found for a runtime which was not scraped yet", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		p.ServeNodeBreakdowns(recorder, httptest.NewRequest(http.MethodGet, NodeBreakdownPath+"?subaccount=subaccount-c", nil))
+		require.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+
+	t.Run("returns not found for an unknown subaccount", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		p.ServeNodeBreakdowns(recorder, httptest.NewRequest(http.MethodGet, NodeBreakdownPath+"?subaccount=subaccount-d", nil))
+		require.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+}
@@ -1,10 +1,14 @@
 package process
 
 import (
+	"context"
 	"fmt"
+	"os"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/kelseyhightower/envconfig"
 	gocache "github.com/patrickmn/go-cache"
 	"go.uber.org/zap"
 	"k8s.io/client-go/util/workqueue"
@@ -12,34 +16,93 @@ import (
 	"github.com/kyma-project/kyma-metrics-collector/pkg/collector"
 	"github.com/kyma-project/kyma-metrics-collector/pkg/collector/edp"
 	"github.com/kyma-project/kyma-metrics-collector/pkg/config"
-	"github.com/kyma-project/kyma-metrics-collector/pkg/keb"
+	log "github.com/kyma-project/kyma-metrics-collector/pkg/logger"
 	"github.com/kyma-project/kyma-metrics-collector/pkg/queue"
 	"github.com/kyma-project/kyma-metrics-collector/pkg/runtime"
 )
 
 type Process struct {
-	KEBClient             *keb.Client
-	EDPClient             *edp.Client
-	EDPCollector          collector.CollectorSender
-	Queue                 workqueue.TypedDelayingInterface[string]
-	KubeconfigProvider    runtime.ConfigProvider
-	Cache                 *gocache.Cache
-	PublicCloudSpecs      *config.PublicCloudSpecs
-	ScrapeInterval        time.Duration
-	WorkersPoolSize       int
-	Logger                *zap.SugaredLogger
-	ClientFactory         runtime.ClientFactory
-	globalAccToBeFiltered map[string]struct{}
+	RuntimeSource      runtime.Source
+	EDPClient          *edp.Client
+	EDPCollector       collector.CollectorSender
+	Queue              workqueue.TypedDelayingInterface[string]
+	KubeconfigProvider runtime.ConfigProvider
+	Cache              *gocache.Cache
+	PublicCloudSpecs   *config.PublicCloudSpecs
+	ScrapeInterval     time.Duration
+	WorkersPoolSize    int
+	Logger             *zap.SugaredLogger
+	ClientFactory      runtime.ClientFactory
+	// ClientPool reuses the clients of the runtimes across scrapes. If it is nil, new clients are created for every scrape.
+	ClientPool    *runtime.ClientPool
+	EvictionGuard EvictionGuardConfig
+	// TrackingPolicy decides which runtimes are tracked. The default policy is used if it is nil.
+	TrackingPolicy *TrackingPolicy
+	// runtimeFilter decides which runtimes are skipped. It is swapped when the filter file changes.
+	runtimeFilter atomic.Pointer[runtimeFilter]
+	// filterFile is the file of the runtime filter, and filterFileData the content of the applied filter.
+	filterFile     string
+	filterFileData []byte
+	// evictionGuardTrips counts the consecutive full resyncs which tripped the eviction guard.
+	evictionGuardTrips int
+	// trackingDecisions holds the latest tracking decision per subaccount.
+	trackingDecisions     map[string]TrackingDecision
+	trackingDecisionsLock sync.RWMutex
+	// recordsLock serialises the read-modify-write of the records in Cache by the runtime source and the workers.
+	recordsLock sync.Mutex
 }
 
-const (
-	trackableTrue  = true
-	trackableFalse = false
-)
+// EvictionGuardConfig configures the guard against evicting a large part of the tracked runtimes in a single resync.
+// The guard is disabled if MaxEvictionPercentage is not set.
+type EvictionGuardConfig struct {
+	// MaxEvictionPercentage is the maximum percentage of tracked runtimes which a single resync may evict.
+	// Larger evictions are only applied if they are returned by Confirmations consecutive resyncs.
+	MaxEvictionPercentage float64 `default:"25" envconfig:"EVICTION_GUARD_MAX_PERCENTAGE"`
+	Confirmations         int     `default:"3"  envconfig:"EVICTION_GUARD_CONFIRMATIONS"`
+}
+
+// deprecatedEvictionGuardConfig holds the former names of the eviction guard settings, which are only read if the
+// current names are not set.
+type deprecatedEvictionGuardConfig struct {
+	MaxEvictionPercentage *float64 `envconfig:"KEB_MAX_EVICTION_PERCENTAGE"`
+	Confirmations         *int     `envconfig:"KEB_EVICTION_CONFIRMATIONS"`
+}
+
+// LoadEvictionGuardConfig loads the EvictionGuardConfig from the environment. The former KEB_MAX_EVICTION_PERCENTAGE
+// and KEB_EVICTION_CONFIRMATIONS are still accepted if EVICTION_GUARD_MAX_PERCENTAGE and EVICTION_GUARD_CONFIRMATIONS
+// are not set.
+func LoadEvictionGuardConfig() (EvictionGuardConfig, error) {
+	config := EvictionGuardConfig{}
+	if err := envconfig.Process("", &config); err != nil {
+		return EvictionGuardConfig{}, err
+	}
+
+	deprecated := deprecatedEvictionGuardConfig{}
+	if err := envconfig.Process("", &deprecated); err != nil {
+		return EvictionGuardConfig{}, err
+	}
+
+	if _, ok := os.LookupEnv("EVICTION_GUARD_MAX_PERCENTAGE"); !ok && deprecated.MaxEvictionPercentage != nil {
+		config.MaxEvictionPercentage = *deprecated.MaxEvictionPercentage
+	}
+
+	if _, ok := os.LookupEnv("EVICTION_GUARD_CONFIRMATIONS"); !ok && deprecated.Confirmations != nil {
+		config.Confirmations = *deprecated.Confirmations
+	}
+
+	return config, nil
+}
+
+// clientIdleScrapes is the number of scrape intervals after which unused clients of a runtime are evicted from the pool.
+const clientIdleScrapes = 3
+
+// kubeconfigMissingScrapes is the number of consecutive scrapes without a kubeconfig after which a missing kubeconfig
+// of a runtime is reported as an error.
+const kubeconfigMissingScrapes = 5
 
 // New creates a new Process object.
 func New(
-	kebClient *keb.Client,
+	runtimeSource runtime.Source,
 	edpClient *edp.Client,
 	edpCollector collector.CollectorSender,
 	configProvider runtime.ConfigProvider,
@@ -48,6 +111,8 @@ func New(
 	workerPoolSize int,
 	logger *zap.SugaredLogger,
 	fileName string,
+	evictionGuard EvictionGuardConfig,
+	trackingPolicy *TrackingPolicy,
 ) (*Process, error) {
 	switch {
 	case logger == nil,
@@ -55,49 +120,75 @@ func New(
 		publicCloudSpecs == nil,
 		edpCollector == nil,
 		edpClient == nil,
-		kebClient == nil:
+		runtimeSource == nil:
 		return nil, fmt.Errorf("missing required parameter")
 	}
 
 	// Creating kubeconfigprovider with no expiration and the data will never be cleaned up
 	cache := gocache.New(gocache.NoExpiration, gocache.NoExpiration)
 
-	filterList := make(map[string]struct{})
+	var (
+		filter *runtimeFilter
+		data   []byte
+	)
 
 	if fileName != "" {
-		data, err := readFilterFile(fileName)
+		var err error
+
+		data, err = readFilterFile(fileName)
 		if err != nil {
 			return nil, fmt.Errorf("failed to read clusters to be filtered file: %v", err)
 		}
 
-		filterList, err = parseRuntimesToBeFiltered(data)
+		filter, err = parseRuntimesToBeFiltered(data)
 		if err != nil {
 			return nil, fmt.Errorf("failed to parse cluster list to filter list: %v", err)
 		}
 	}
 
-	return &Process{
-		KEBClient:             kebClient,
-		EDPClient:             edpClient,
-		EDPCollector:          edpCollector,
-		KubeconfigProvider:    configProvider,
-		Logger:                logger,
-		PublicCloudSpecs:      publicCloudSpecs,
-		Cache:                 cache,
-		ScrapeInterval:        scrapeInterval,
-		Queue:                 queue.NewQueue("trackable-skrs"),
-		WorkersPoolSize:       workerPoolSize,
-		ClientFactory:         runtime.NewClientsFactory(),
-		globalAccToBeFiltered: filterList,
-	}, nil
+	clientFactory := runtime.NewClientsFactory()
+
+	p := &Process{
+		RuntimeSource:      runtimeSource,
+		EDPClient:          edpClient,
+		EDPCollector:       edpCollector,
+		KubeconfigProvider: configProvider,
+		Logger:             logger,
+		PublicCloudSpecs:   publicCloudSpecs,
+		Cache:              cache,
+		ScrapeInterval:     scrapeInterval,
+		Queue:              queue.NewQueue("trackable-skrs"),
+		WorkersPoolSize:    workerPoolSize,
+		ClientFactory:      clientFactory,
+		ClientPool:         runtime.NewClientPool(clientFactory, clientIdleScrapes*scrapeInterval),
+		EvictionGuard:      evictionGuard,
+		TrackingPolicy:     trackingPolicy,
+		trackingDecisions:  make(map[string]TrackingDecision),
+		filterFile:         fileName,
+		filterFileData:     data,
+	}
+	p.runtimeFilter.Store(filter)
+
+	return p, nil
 }
 
 // Start runs the complete process of collection and sending metrics.
 func (p *Process) Start() {
 	var wg sync.WaitGroup
 
+	if p.ClientPool != nil {
+		go p.ClientPool.Run(context.Background())
+	}
+
+	if p.filterFile != "" {
+		go p.watchFilterFile(context.Background(), p.filterFileData)
+	}
+
 	go func() {
-		p.pollKEBForRuntimes()
+		if err := p.RuntimeSource.Start(context.Background(), p); err != nil {
+			p.namedLogger().With(log.KeyResult, log.ValueFail).With(log.KeyError, err.Error()).
+				Fatal("start runtime source")
+		}
 	}()
 
 	for i := range p.WorkersPoolSize {
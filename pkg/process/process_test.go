@@ -1,12 +1,15 @@
 package process
 
 import (
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"reflect"
 	"strconv"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -17,14 +20,21 @@ import (
 	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap/zapcore"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+	k8stesting "k8s.io/client-go/testing"
 	"k8s.io/client-go/util/workqueue"
 
 	"github.com/kyma-project/kyma-metrics-collector/pkg/collector"
+	"github.com/kyma-project/kyma-metrics-collector/pkg/collector/edp"
+	edpstubs "github.com/kyma-project/kyma-metrics-collector/pkg/collector/edp/stubs"
 	"github.com/kyma-project/kyma-metrics-collector/pkg/config"
 	kmckeb "github.com/kyma-project/kyma-metrics-collector/pkg/keb"
 	"github.com/kyma-project/kyma-metrics-collector/pkg/logger"
 	"github.com/kyma-project/kyma-metrics-collector/pkg/process/stubs"
+	"github.com/kyma-project/kyma-metrics-collector/pkg/resource"
 	runtime2 "github.com/kyma-project/kyma-metrics-collector/pkg/runtime"
 	"github.com/kyma-project/kyma-metrics-collector/pkg/runtime/kubeconfigprovider"
 	runtimestubs "github.com/kyma-project/kyma-metrics-collector/pkg/runtime/stubs"
@@ -97,7 +107,7 @@ func TestPollKEBForRuntimes(t *testing.T) {
 		queue := workqueue.NewTypedDelayingQueue[string]()
 		cache := gocache.New(gocache.NoExpiration, gocache.NoExpiration)
 		newProcess = &Process{
-			KEBClient:      kebClient,
+			RuntimeSource:  kmckeb.NewRuntimeSource(kebClient),
 			Queue:          queue,
 			Cache:          cache,
 			ScrapeInterval: 0,
@@ -107,8 +117,11 @@ func TestPollKEBForRuntimes(t *testing.T) {
 		// Reset the cluster count necessary for clean slate of next tests
 		kebFetchedClusters.Reset()
 
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
 		go func() {
-			newProcess.pollKEBForRuntimes()
+			_ = newProcess.RuntimeSource.Start(ctx, newProcess)
 		}()
 		g.Eventually(func() int {
 			return timesVisited
@@ -127,6 +140,87 @@ func TestPollKEBForRuntimes(t *testing.T) {
 	})
 }
 
+func TestPollKEBIncrementally(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	kebFetchedClusters.Reset()
+
+	existingRuntime := kmctesting.NewRuntimesDTO(uuid.New().String(), fmt.Sprintf("shoot-%s", kmctesting.GenerateRandomAlphaString(5)),
+		kmctesting.WithProvisioningSucceededStatus(kebruntime.StateSucceeded))
+	newRuntime := kmctesting.NewRuntimesDTO(uuid.New().String(), fmt.Sprintf("shoot-%s", kmctesting.GenerateRandomAlphaString(5)),
+		kmctesting.WithProvisioningSucceededStatus(kebruntime.StateSucceeded))
+	newRuntime.Status.CreatedAt = time.Now()
+
+	var fullResyncs, incrementalPolls atomic.Int32
+
+	getRuntimesHandler := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		page := kebruntime.RuntimesPage{Data: []kebruntime.RuntimeDTO{existingRuntime}}
+
+		if req.URL.Query().Has(kmckeb.UpdatedSinceParam) {
+			// the new runtime is provisioned after the first full resync
+			incrementalPolls.Add(1)
+			page.Data = append(page.Data, newRuntime)
+		} else {
+			fullResyncs.Add(1)
+		}
+
+		page.Count = len(page.Data)
+		page.TotalCount = len(page.Data)
+
+		data, err := json.Marshal(page)
+		g.Expect(err).Should(gomega.BeNil())
+
+		_, err = rw.Write(data)
+		g.Expect(err).Should(gomega.BeNil())
+	})
+
+	srv := kmctesting.StartTestServer(expectedPathPrefix, getRuntimesHandler, g)
+	defer srv.Close()
+
+	kebClient := &kmckeb.Client{
+		HTTPClient: http.DefaultClient,
+		Logger:     logger.NewLogger(zapcore.InfoLevel),
+		Config: &kmckeb.Config{
+			URL:                     srv.URL + expectedPathPrefix,
+			Timeout:                 timeout,
+			RetryCount:              1,
+			PollWaitDuration:        time.Hour,
+			IncrementalPollInterval: 50 * time.Millisecond,
+		},
+	}
+
+	p := &Process{
+		RuntimeSource: kmckeb.NewRuntimeSource(kebClient),
+		Queue:         workqueue.NewTypedDelayingQueue[string](),
+		Cache:         gocache.New(gocache.NoExpiration, gocache.NoExpiration),
+		Logger:        logger.NewLogger(zapcore.InfoLevel),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		_ = p.RuntimeSource.Start(ctx, p)
+	}()
+
+	g.Eventually(func() int {
+		return p.Queue.Len()
+	}, 10*time.Second).Should(gomega.Equal(2))
+
+	cancel()
+	<-done
+
+	_, found := p.Cache.Get(newRuntime.SubAccountID)
+	g.Expect(found).To(gomega.BeTrue())
+	// the new runtime is queued by an incremental poll before the next full resync
+	g.Expect(fullResyncs.Load()).To(gomega.Equal(int32(1)))
+	g.Expect(incrementalPolls.Load()).To(gomega.BeNumerically(">=", 1))
+}
+
 func TestPopulateCacheAndQueue(t *testing.T) {
 	g := gomega.NewGomegaWithT(t)
 
@@ -157,7 +251,7 @@ func TestPopulateCacheAndQueue(t *testing.T) {
 			runtimesPage.Data = append(runtimesPage.Data, runtime)
 		}
 
-		p.populateCacheAndQueue(runtimesPage)
+		p.populateCacheAndQueue(runtime2.FromKEB(runtimesPage.Data))
 		g.Expect(*p.Cache).To(gomega.Equal(*expectedCache))
 		g.Expect(areQueuesEqual(p.Queue, expectedQueue)).To(gomega.BeTrue())
 
@@ -199,7 +293,7 @@ func TestPopulateCacheAndQueue(t *testing.T) {
 			runtimesPage.Data = append(runtimesPage.Data, rntme)
 		}
 
-		p.populateCacheAndQueue(runtimesPage)
+		p.populateCacheAndQueue(runtime2.FromKEB(runtimesPage.Data))
 		g.Expect(*p.Cache).To(gomega.Equal(*expectedCache))
 		g.Expect(areQueuesEqual(p.Queue, expectedQueue)).To(gomega.BeTrue())
 
@@ -240,7 +334,7 @@ func TestPopulateCacheAndQueue(t *testing.T) {
 
 		runtimesPageWithNoRuntimes.Data = []kebruntime.RuntimeDTO{}
 
-		p.populateCacheAndQueue(runtimesPageWithNoRuntimes)
+		p.populateCacheAndQueue(runtime2.FromKEB(runtimesPageWithNoRuntimes.Data))
 		g.Expect(*p.Cache).To(gomega.Equal(*expectedEmptyCache))
 		g.Expect(areQueuesEqual(p.Queue, expectedEmptyQueue)).To(gomega.BeTrue())
 
@@ -284,7 +378,7 @@ func TestPopulateCacheAndQueue(t *testing.T) {
 		runtimesPage.Data = append(runtimesPage.Data, rntme)
 
 		// expected kubeconfigprovider changes after deprovisioning
-		p.populateCacheAndQueue(runtimesPage)
+		p.populateCacheAndQueue(runtime2.FromKEB(runtimesPage.Data))
 		g.Expect(*p.Cache).To(gomega.Equal(*expectedCache))
 		g.Expect(areQueuesEqual(p.Queue, expectedQueue)).To(gomega.BeTrue())
 
@@ -301,7 +395,7 @@ func TestPopulateCacheAndQueue(t *testing.T) {
 
 		runtimesPage.Data = []kebruntime.RuntimeDTO{rntme}
 
-		p.populateCacheAndQueue(skrRuntimesPageWithProvisioning)
+		p.populateCacheAndQueue(runtime2.FromKEB(skrRuntimesPageWithProvisioning.Data))
 		g.Expect(*p.Cache).To(gomega.Equal(*expectedCache))
 		gotSubAccID, _ := p.Queue.Get()
 		g.Expect(gotSubAccID).To(gomega.Equal(subAccID))
@@ -326,6 +420,220 @@ func TestPopulateCacheAndQueue(t *testing.T) {
 			verifyKEBAllClustersCountMetricValue(expectedMetricValue, g, runtimeData)
 		}
 	})
+
+	t.Run("with loaded kubeconfigprovider, then the plan of the runtime changes", func(t *testing.T) {
+		// Reset the cluster count necessary for clean slate of next tests
+		kebFetchedClusters.Reset()
+
+		subAccID := uuid.New().String()
+		shootName := fmt.Sprintf("shoot-%s", kmctesting.GenerateRandomAlphaString(5))
+		cache := gocache.New(gocache.NoExpiration, gocache.NoExpiration)
+
+		p := Process{
+			Queue:  workqueue.NewTypedDelayingQueue[string](),
+			Cache:  cache,
+			Logger: logger.NewLogger(zapcore.InfoLevel),
+		}
+		oldRecord := NewRecord(subAccID, shootName, "foo")
+		oldRecord.Region = "eu-central-1"
+		oldRecord.PlanName = "trial"
+		oldRecord.ScanMap = NewScanMap()
+
+		err := p.Cache.Add(subAccID, oldRecord, gocache.NoExpiration)
+		g.Expect(err).Should(gomega.BeNil())
+
+		rntme := kmctesting.NewRuntimesDTO(subAccID, shootName, kmctesting.WithProvisioningSucceededStatus(kebruntime.StateSucceeded))
+		rntme.ProviderRegion = "eu-central-1"
+		rntme.ServicePlanName = "AWS"
+		runtimesPage := &kebruntime.RuntimesPage{Data: []kebruntime.RuntimeDTO{rntme}}
+
+		p.populateCacheAndQueue(runtime2.FromKEB(runtimesPage.Data))
+
+		expectedRecord := oldRecord
+		expectedRecord.PlanName = "aws"
+
+		gotRecord, found := p.Cache.Get(subAccID)
+		g.Expect(found).To(gomega.BeTrue())
+		g.Expect(gotRecord).To(gomega.Equal(expectedRecord))
+		// the runtime is already queued, so it must not be queued again
+		g.Expect(p.Queue.Len()).To(gomega.Equal(0))
+	})
+}
+
+func TestEvictionGuard(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	kebFetchedClusters.Reset()
+	evictionGuardTrips.Reset()
+
+	p := Process{
+		Queue:  workqueue.NewTypedDelayingQueue[string](),
+		Cache:  gocache.New(gocache.NoExpiration, gocache.NoExpiration),
+		Logger: logger.NewLogger(zapcore.InfoLevel),
+		EvictionGuard: EvictionGuardConfig{
+			MaxEvictionPercentage: 50,
+			Confirmations:         3,
+		},
+	}
+
+	var runtimes []kebruntime.RuntimeDTO
+
+	for range 4 {
+		subAccID := uuid.New().String()
+		shootName := fmt.Sprintf("shoot-%s", kmctesting.GenerateRandomAlphaString(5))
+		runtimes = append(runtimes, kmctesting.NewRuntimesDTO(subAccID, shootName, kmctesting.WithProvisioningSucceededStatus(kebruntime.StateSucceeded)))
+	}
+
+	p.populateCacheAndQueue(runtime2.FromKEB(runtimes))
+	g.Expect(p.Cache.ItemCount()).To(gomega.Equal(4))
+
+	// evicting 2 of 4 runtimes is within the allowed percentage
+	p.populateCacheAndQueue(runtime2.FromKEB(runtimes[:2]))
+	g.Expect(p.Cache.ItemCount()).To(gomega.Equal(2))
+	g.Expect(testutil.ToFloat64(evictionGuardTrips.WithLabelValues())).To(gomega.Equal(0.0))
+
+	// an empty response is kept until it is confirmed by 3 consecutive polls
+	var emptyPage []runtime2.Runtime
+
+	p.populateCacheAndQueue(emptyPage)
+	g.Expect(p.Cache.ItemCount()).To(gomega.Equal(2))
+
+	p.populateCacheAndQueue(emptyPage)
+	g.Expect(p.Cache.ItemCount()).To(gomega.Equal(2))
+	g.Expect(testutil.ToFloat64(evictionGuardTrips.WithLabelValues())).To(gomega.Equal(2.0))
+
+	// a good response in between resets the confirmations
+	p.populateCacheAndQueue(runtime2.FromKEB(runtimes[:2]))
+	p.populateCacheAndQueue(emptyPage)
+	p.populateCacheAndQueue(emptyPage)
+	g.Expect(p.Cache.ItemCount()).To(gomega.Equal(2))
+
+	p.populateCacheAndQueue(emptyPage)
+	g.Expect(p.Cache.ItemCount()).To(gomega.Equal(0))
+	g.Expect(testutil.ToFloat64(evictionGuardTrips.WithLabelValues())).To(gomega.Equal(5.0))
+}
+
+func TestLoadEvictionGuardConfig(t *testing.T) {
+	testCases := []struct {
+		name     string
+		env      map[string]string
+		expected EvictionGuardConfig
+	}{
+		{
+			name:     "defaults",
+			expected: EvictionGuardConfig{MaxEvictionPercentage: 25, Confirmations: 3},
+		},
+		{
+			name: "former names",
+			env: map[string]string{
+				"KEB_MAX_EVICTION_PERCENTAGE": "10",
+				"KEB_EVICTION_CONFIRMATIONS":  "5",
+			},
+			expected: EvictionGuardConfig{MaxEvictionPercentage: 10, Confirmations: 5},
+		},
+		{
+			name: "current names take precedence",
+			env: map[string]string{
+				"EVICTION_GUARD_MAX_PERCENTAGE": "0",
+				"KEB_MAX_EVICTION_PERCENTAGE":   "10",
+				"EVICTION_GUARD_CONFIRMATIONS":  "2",
+				"KEB_EVICTION_CONFIRMATIONS":    "5",
+			},
+			expected: EvictionGuardConfig{MaxEvictionPercentage: 0, Confirmations: 2},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := gomega.NewGomegaWithT(t)
+
+			for key, value := range tc.env {
+				t.Setenv(key, value)
+			}
+
+			config, err := LoadEvictionGuardConfig()
+			g.Expect(err).Should(gomega.BeNil())
+			g.Expect(config).To(gomega.Equal(tc.expected))
+		})
+	}
+}
+
+func TestUpdateCacheAndQueue(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	t.Run("update adds new runtimes and keeps runtimes missing in the response", func(t *testing.T) {
+		kebFetchedClusters.Reset()
+
+		cache := gocache.New(gocache.NoExpiration, gocache.NoExpiration)
+		p := Process{
+			Queue:  workqueue.NewTypedDelayingQueue[string](),
+			Cache:  cache,
+			Logger: logger.NewLogger(zapcore.InfoLevel),
+		}
+
+		existingSubAccID := uuid.New().String()
+		existingRecord := NewRecord(existingSubAccID, fmt.Sprintf("shoot-%s", kmctesting.GenerateRandomAlphaString(5)), "foo")
+		err := p.Cache.Add(existingSubAccID, existingRecord, gocache.NoExpiration)
+		g.Expect(err).Should(gomega.BeNil())
+
+		newSubAccID := uuid.New().String()
+		newRuntime := kmctesting.NewRuntimesDTO(newSubAccID, fmt.Sprintf("shoot-%s", kmctesting.GenerateRandomAlphaString(5)),
+			kmctesting.WithProvisioningSucceededStatus(kebruntime.StateSucceeded))
+
+		p.updateCacheAndQueue(runtime2.FromKEB([]kebruntime.RuntimeDTO{newRuntime}))
+
+		g.Expect(p.Cache.ItemCount()).To(gomega.Equal(2))
+		_, found := p.Cache.Get(existingSubAccID)
+		g.Expect(found).To(gomega.BeTrue())
+		_, found = p.Cache.Get(newSubAccID)
+		g.Expect(found).To(gomega.BeTrue())
+		g.Expect(p.Queue.Len()).To(gomega.Equal(1))
+		// the fetched clusters are only recorded on resyncs
+		g.Expect(testutil.CollectAndCount(kebFetchedClusters, fecthedClustersMetricName)).To(gomega.Equal(0))
+	})
+
+	t.Run("resyncs record every runtime once", func(t *testing.T) {
+		kebFetchedClusters.Reset()
+
+		p := Process{
+			Queue:  workqueue.NewTypedDelayingQueue[string](),
+			Cache:  gocache.New(gocache.NoExpiration, gocache.NoExpiration),
+			Logger: logger.NewLogger(zapcore.InfoLevel),
+		}
+
+		provisioned := kmctesting.NewRuntimesDTO(uuid.New().String(), fmt.Sprintf("shoot-%s", kmctesting.GenerateRandomAlphaString(5)),
+			kmctesting.WithProvisioningSucceededStatus(kebruntime.StateSucceeded))
+
+		p.populateCacheAndQueue(runtime2.FromKEB([]kebruntime.RuntimeDTO{provisioned}))
+		p.updateCacheAndQueue(runtime2.FromKEB([]kebruntime.RuntimeDTO{provisioned}))
+		p.populateCacheAndQueue(runtime2.FromKEB([]kebruntime.RuntimeDTO{provisioned}))
+
+		g.Expect(testutil.CollectAndCount(kebFetchedClusters, fecthedClustersMetricName)).To(gomega.Equal(1))
+		verifyKEBAllClustersCountMetricValue(1, g, provisioned)
+	})
+
+	t.Run("update deletes runtimes which are not trackable anymore", func(t *testing.T) {
+		kebFetchedClusters.Reset()
+
+		cache := gocache.New(gocache.NoExpiration, gocache.NoExpiration)
+		p := Process{
+			Queue:  workqueue.NewTypedDelayingQueue[string](),
+			Cache:  cache,
+			Logger: logger.NewLogger(zapcore.InfoLevel),
+		}
+
+		subAccID := uuid.New().String()
+		shootName := fmt.Sprintf("shoot-%s", kmctesting.GenerateRandomAlphaString(5))
+		err := p.Cache.Add(subAccID, NewRecord(subAccID, shootName, "foo"), gocache.NoExpiration)
+		g.Expect(err).Should(gomega.BeNil())
+
+		deprovisioned := kmctesting.NewRuntimesDTO(subAccID, shootName,
+			kmctesting.WithProvisionedAndDeprovisionedStatus(kebruntime.StateDeprovisioned))
+
+		p.updateCacheAndQueue(runtime2.FromKEB([]kebruntime.RuntimeDTO{deprovisioned}))
+
+		g.Expect(p.Cache.ItemCount()).To(gomega.Equal(0))
+	})
 }
 
 // TestPrometheusMetricsRemovedForDeletedSubAccounts tests that the prometheus metrics
@@ -486,7 +794,7 @@ func TestPrometheusMetricsRemovedForDeletedSubAccounts(t *testing.T) {
 			}
 
 			// when
-			p.populateCacheAndQueue(runtimesPage)
+			p.populateCacheAndQueue(runtime2.FromKEB(runtimesPage.Data))
 
 			// then
 			// check if metrics still exists or not for shoot1 (existing shoot)
@@ -650,7 +958,7 @@ func TestPrometheusMetricsProcessSubAccountID(t *testing.T) {
 
 			secretKCPStored := kmctesting.NewKCPStoredSecret(tc.givenShoot.RuntimeID, tc.KubeConfig)
 			secretCacheClient := fake.NewClientset(secretKCPStored)
-			kubeconfigProvider := kubeconfigprovider.New(secretCacheClient.CoreV1(), logger, 1*time.Minute, "test")
+			kubeconfigProvider := kubeconfigprovider.New(secretCacheClient.CoreV1(), kubeconfigprovider.DefaultSecretConfig(), logger, 1*time.Minute, 0, "test")
 
 			// initiate process instance.
 			givenProcess := &Process{
@@ -661,7 +969,7 @@ func TestPrometheusMetricsProcessSubAccountID(t *testing.T) {
 				Logger:             logger,
 				KubeconfigProvider: kubeconfigProvider,
 				ClientFactory: &runtimestubs.ClientFactory{
-					Clients: runtimestubs.Clients{},
+					Clients: runtimestubs.Clients{KubernetesInterface: fake.NewSimpleClientset()},
 				},
 			}
 
@@ -741,12 +1049,11 @@ func TestSkipGlobalAccountID(t *testing.T) {
 	}
 
 	for _, tc := range tt {
-		p := Process{
-			globalAccToBeFiltered: tc.filter,
-		}
+		p := Process{}
+		p.runtimeFilter.Store(&runtimeFilter{deny: filterRules{globalAccounts: toSet(keys(tc.filter))}})
 
 		t.Run(tc.name, func(t *testing.T) {
-			actual := p.skipRuntime(tc.runtime)
+			actual, _ := p.runtimeFilter.Load().skip(tc.runtime)
 			require.Equal(t, tc.expected, actual)
 		})
 	}
@@ -762,7 +1069,7 @@ func TestExecute(t *testing.T) {
 
 	secretKCPStored := kmctesting.NewKCPStoredSecret(runtimeID, expectedKubeconfig)
 	secretCacheClient := fake.NewSimpleClientset(secretKCPStored)
-	kubeconfigProvider := kubeconfigprovider.New(secretCacheClient.CoreV1(), log, 1*time.Minute, "test")
+	kubeconfigProvider := kubeconfigprovider.New(secretCacheClient.CoreV1(), kubeconfigprovider.DefaultSecretConfig(), log, 1*time.Minute, 0, "test")
 
 	expectedScanMap := NewScanMap()
 	collector := stubs.NewCollector(expectedScanMap, nil)
@@ -789,7 +1096,7 @@ func TestExecute(t *testing.T) {
 		Logger:             log,
 		KubeconfigProvider: kubeconfigProvider,
 		ClientFactory: &runtimestubs.ClientFactory{
-			Clients: runtimestubs.Clients{},
+			Clients: runtimestubs.Clients{KubernetesInterface: fake.NewSimpleClientset()},
 		},
 	}
 
@@ -886,10 +1193,11 @@ func NewScanMap() collector.ScanMap {
 // Helper function to check the value of the `kmc_process_fetched_clusters` metric using `ToFloat64`.
 func verifyKEBAllClustersCountMetricValue(expectedValue int, g *gomega.WithT, runtimeData kebruntime.RuntimeDTO) bool {
 	return g.Eventually(func() int {
-		trackable := isRuntimeTrackable(runtimeData)
+		decision := DefaultTrackingPolicy().decide(runtime2.Runtime{RuntimeDTO: runtimeData})
 
 		counter, err := kebFetchedClusters.GetMetricWithLabelValues(
-			strconv.FormatBool(trackable),
+			strconv.FormatBool(decision.Trackable),
+			decision.Reason,
 			runtimeData.ShootName,
 			runtimeData.InstanceID,
 			runtimeData.RuntimeID,
@@ -926,3 +1234,437 @@ users:
     client-key-data: ` + base64.StdEncoding.EncodeToString([]byte("fake-client-key-data")) + `
 `
 }
+
+func TestDeleteFromCacheEvictsClients(t *testing.T) {
+	subAccID := uuid.New().String()
+	runtimeID := uuid.New().String()
+
+	p := Process{
+		Cache:      gocache.New(gocache.NoExpiration, gocache.NoExpiration),
+		Logger:     logger.NewLogger(zapcore.InfoLevel),
+		ClientPool: runtime2.NewClientPool(&runtimestubs.ClientFactory{}, time.Minute),
+	}
+
+	record := NewRecord(subAccID, "shoot", "foo")
+	record.RuntimeID = runtimeID
+	require.NoError(t, p.Cache.Add(subAccID, record, gocache.NoExpiration))
+
+	_, release, err := p.ClientPool.Get(runtimeID, []byte(generateFakeKubeConfig()))
+	require.NoError(t, err)
+	release()
+	require.Equal(t, 1, p.ClientPool.Len())
+
+	p.deleteFromCache(kebruntime.RuntimeDTO{SubAccountID: subAccID, RuntimeID: runtimeID})
+	require.Equal(t, 0, p.ClientPool.Len())
+}
+
+type kubeconfigProviderFunc func(runtimeID string) ([]byte, error)
+
+func (f kubeconfigProviderFunc) Get(runtimeID string) ([]byte, error) {
+	return f(runtimeID)
+}
+
+func TestProcessSubAccountIDKubeconfigErrors(t *testing.T) {
+	subAccID := uuid.New().String()
+	runtimeID := uuid.New().String()
+
+	var providerErr error
+
+	p := &Process{
+		Queue:          workqueue.NewTypedDelayingQueue[string](),
+		Cache:          gocache.New(gocache.NoExpiration, gocache.NoExpiration),
+		ScrapeInterval: time.Minute,
+		Logger:         logger.NewLogger(zapcore.InfoLevel),
+		KubeconfigProvider: kubeconfigProviderFunc(func(string) ([]byte, error) {
+			return nil, providerErr
+		}),
+	}
+
+	record := NewRecord(subAccID, "shoot", "foo")
+	record.RuntimeID = runtimeID
+	require.NoError(t, p.Cache.Add(subAccID, record, gocache.NoExpiration))
+
+	missingScrapes := func() int {
+		item, found := p.Cache.Get(subAccID)
+		require.True(t, found)
+
+		return item.(kubeconfigprovider.Record).KubeconfigMissingScrapes
+	}
+
+	// consecutive scrapes without a kubeconfig are counted in the record
+	providerErr = fmt.Errorf("%w: %s", kubeconfigprovider.ErrNotFound, runtimeID)
+	for i := 1; i <= kubeconfigMissingScrapes+1; i++ {
+		require.False(t, p.processSubAccountID(subAccID, 1))
+		require.Equal(t, i, missingScrapes())
+	}
+
+	// other failures do not change the count
+	providerErr = fmt.Errorf("%w: timeout", kubeconfigprovider.ErrTransient)
+	require.False(t, p.processSubAccountID(subAccID, 1))
+	require.Equal(t, kubeconfigMissingScrapes+1, missingScrapes())
+
+	// the count is reset once the kubeconfig is found
+	providerErr = nil
+	p.KubeconfigProvider = kubeconfigProviderFunc(func(string) ([]byte, error) {
+		return []byte(generateFakeKubeConfig()), nil
+	})
+	p.ClientFactory = &runtimestubs.ClientFactory{Clients: runtimestubs.Clients{KubernetesInterface: fake.NewSimpleClientset()}}
+	p.EDPCollector = stubs.NewCollector(NewScanMap(), nil)
+
+	require.True(t, p.processSubAccountID(subAccID, 1))
+	require.Equal(t, 0, missingScrapes())
+}
+
+// rotatingKubeconfigProvider returns the next kubeconfig once the current one is invalidated.
+type rotatingKubeconfigProvider struct {
+	kubeconfigs   [][]byte
+	invalidations int
+}
+
+func (r *rotatingKubeconfigProvider) Get(string) ([]byte, error) {
+	return r.kubeconfigs[min(r.invalidations, len(r.kubeconfigs)-1)], nil
+}
+
+func (r *rotatingKubeconfigProvider) Invalidate(string) {
+	r.invalidations++
+}
+
+// sequenceClientFactory returns the clients in order for consecutive calls.
+type sequenceClientFactory struct {
+	clients []runtimestubs.Clients
+	calls   int
+}
+
+func (s *sequenceClientFactory) NewClients(*rest.Config) (runtime2.InterfaceCloser, error) {
+	clients := s.clients[min(s.calls, len(s.clients)-1)]
+	s.calls++
+
+	return clients, nil
+}
+
+// newVersionClients returns clients whose requests for the version of the API server fail with err.
+func newVersionClients(err error) runtimestubs.Clients {
+	clientset := fake.NewSimpleClientset()
+	clientset.PrependReactor("get", "version", func(k8stesting.Action) (bool, k8sruntime.Object, error) {
+		return err != nil, nil, err
+	})
+
+	return runtimestubs.Clients{KubernetesInterface: clientset}
+}
+
+// versionScanner fails if the API server rejects the request for its version.
+type versionScanner struct{}
+
+func (versionScanner) Scan(_ context.Context, _ *runtime2.Info, clients runtime2.Interface) (resource.ScanConverter, error) {
+	if _, err := clients.K8s().Discovery().ServerVersion(); err != nil {
+		return nil, err
+	}
+
+	return edpstubs.Scan{}, nil
+}
+
+func (versionScanner) ID() resource.ScannerID {
+	return "version"
+}
+
+func TestProcessSubAccountIDUnauthorizedRetry(t *testing.T) {
+	unauthorized := k8serr.NewUnauthorized("token expired")
+	kubeconfig := generateFakeKubeConfig()
+	rotatedKubeconfig := kubeconfig + "# rotated\n"
+
+	tests := []struct {
+		name              string
+		kubeconfigs       []string
+		clients           []runtimestubs.Clients
+		wantSuccess       bool
+		wantClients       int
+		wantInvalidations int
+	}{
+		{
+			name:              "retries once with the rotated kubeconfig",
+			kubeconfigs:       []string{kubeconfig, rotatedKubeconfig},
+			clients:           []runtimestubs.Clients{newVersionClients(unauthorized), newVersionClients(nil)},
+			wantSuccess:       true,
+			wantClients:       2,
+			wantInvalidations: 1,
+		},
+		{
+			name:              "does not retry if the kubeconfig was not rotated",
+			kubeconfigs:       []string{kubeconfig},
+			clients:           []runtimestubs.Clients{newVersionClients(unauthorized)},
+			wantClients:       1,
+			wantInvalidations: 1,
+		},
+		{
+			name:              "retries only once",
+			kubeconfigs:       []string{kubeconfig, rotatedKubeconfig},
+			clients:           []runtimestubs.Clients{newVersionClients(unauthorized), newVersionClients(unauthorized)},
+			wantClients:       2,
+			wantInvalidations: 1,
+		},
+		{
+			name:        "does not retry other errors",
+			kubeconfigs: []string{kubeconfig, rotatedKubeconfig},
+			clients:     []runtimestubs.Clients{newVersionClients(fmt.Errorf("timeout"))},
+			wantClients: 1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			subAccID := uuid.New().String()
+			provider := &rotatingKubeconfigProvider{}
+
+			for _, kubeconfig := range tc.kubeconfigs {
+				provider.kubeconfigs = append(provider.kubeconfigs, []byte(kubeconfig))
+			}
+
+			edpClient, usages := startEDPServer(t)
+			clientFactory := &sequenceClientFactory{clients: tc.clients}
+
+			p := &Process{
+				EDPCollector:       edp.NewCollector(edpClient, versionScanner{}),
+				Queue:              workqueue.NewTypedDelayingQueue[string](),
+				Cache:              gocache.New(gocache.NoExpiration, gocache.NoExpiration),
+				ScrapeInterval:     time.Minute,
+				Logger:             logger.NewLogger(zapcore.InfoLevel),
+				KubeconfigProvider: provider,
+				ClientFactory:      clientFactory,
+			}
+
+			record := NewRecord(subAccID, "shoot", "foo")
+			record.RuntimeID = uuid.New().String()
+			record.ScanMap = collector.ScanMap{versionScanner{}.ID(): edpstubs.Scan{}}
+			require.NoError(t, p.Cache.Add(subAccID, record, gocache.NoExpiration))
+
+			require.Equal(t, tc.wantSuccess, p.processSubAccountID(subAccID, 1))
+			require.Equal(t, tc.wantClients, clientFactory.calls)
+			require.Equal(t, tc.wantInvalidations, provider.invalidations)
+
+			// the measurements are sent once, falling back to the previous scan if the credentials are rejected
+			require.Len(t, *usages, 1)
+		})
+	}
+}
+
+// clockScanner returns a scan with one node at the next time for every scan.
+type clockScanner struct {
+	times []time.Time
+	scans int
+}
+
+func (c *clockScanner) Scan(context.Context, *runtime2.Info, runtime2.Interface) (resource.ScanConverter, error) {
+	scan := edpstubs.Scan{
+		At:         c.times[c.scans],
+		UsageRates: []resource.Rate{{Key: "node", PerHour: resource.Usage{NodeHours: 1}}},
+	}
+	c.scans++
+
+	return scan, nil
+}
+
+func (c *clockScanner) ID() resource.ScannerID {
+	return "clock"
+}
+
+// startEDPServer starts an EDP server collecting the usage of the received payloads.
+func startEDPServer(t *testing.T) (*edp.Client, *[]resource.Usage) {
+	t.Helper()
+
+	var usages []resource.Usage
+
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		var payload struct {
+			Usage *resource.Usage `json:"usage"`
+		}
+
+		if err := json.NewDecoder(req.Body).Decode(&payload); err != nil || payload.Usage == nil {
+			rw.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		usages = append(usages, *payload.Usage)
+
+		rw.WriteHeader(http.StatusCreated)
+	}))
+	t.Cleanup(srv.Close)
+
+	edpClient := edp.NewClient(&edp.Config{
+		URL:               srv.URL,
+		Timeout:           time.Second,
+		EventRetry:        1,
+		TimeWeightedUsage: true,
+		UsageMaxPeriod:    24 * time.Hour,
+	}, logger.NewLogger(zapcore.InfoLevel))
+
+	return edpClient, &usages
+}
+
+func TestProcessSubAccountIDUsageWithFailingScanner(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := &clockScanner{times: []time.Time{start, start.Add(time.Hour), start.Add(2 * time.Hour), start.Add(3 * time.Hour)}}
+	failing := edpstubs.NewScanner(nil, fmt.Errorf("failed to scan"), "failing")
+
+	edpClient, usages := startEDPServer(t)
+
+	subAccID := uuid.New().String()
+	p := &Process{
+		EDPCollector:   edp.NewCollector(edpClient, clock, failing),
+		Queue:          workqueue.NewTypedDelayingQueue[string](),
+		Cache:          gocache.New(gocache.NoExpiration, gocache.NoExpiration),
+		ScrapeInterval: time.Minute,
+		Logger:         logger.NewLogger(zapcore.InfoLevel),
+		KubeconfigProvider: kubeconfigProviderFunc(func(string) ([]byte, error) {
+			return []byte(generateFakeKubeConfig()), nil
+		}),
+		ClientFactory: &runtimestubs.ClientFactory{Clients: runtimestubs.Clients{KubernetesInterface: fake.NewSimpleClientset()}},
+	}
+
+	record := NewRecord(subAccID, "shoot", "foo")
+	record.RuntimeID = uuid.New().String()
+	require.NoError(t, p.Cache.Add(subAccID, record, gocache.NoExpiration))
+
+	// the failing scanner fails every scrape, but the payloads are sent with the scans of the other scanner
+	for range clock.times {
+		require.False(t, p.processSubAccountID(subAccID, 1))
+	}
+
+	// every hour between the scans is sent once
+	require.Equal(t, []resource.Usage{{}, {NodeHours: 1}, {NodeHours: 1}, {NodeHours: 1}}, *usages)
+}
+
+// hookScanner calls during while the runtime is scanned, e.g. to change the runtime in the runtime source.
+type hookScanner struct {
+	during func()
+}
+
+func (h hookScanner) Scan(context.Context, *runtime2.Info, runtime2.Interface) (resource.ScanConverter, error) {
+	h.during()
+
+	return edpstubs.Scan{At: time.Now()}, nil
+}
+
+func (hookScanner) ID() resource.ScannerID {
+	return "hook"
+}
+
+func TestProcessSubAccountIDWithRuntimeChangedDuringScrape(t *testing.T) {
+	newProcess := func(t *testing.T, during func(p *Process)) *Process {
+		t.Helper()
+
+		edpClient, _ := startEDPServer(t)
+
+		p := &Process{
+			Queue:          workqueue.NewTypedDelayingQueue[string](),
+			Cache:          gocache.New(gocache.NoExpiration, gocache.NoExpiration),
+			ScrapeInterval: time.Minute,
+			Logger:         logger.NewLogger(zapcore.InfoLevel),
+			KubeconfigProvider: kubeconfigProviderFunc(func(string) ([]byte, error) {
+				return []byte(generateFakeKubeConfig()), nil
+			}),
+			ClientFactory: &runtimestubs.ClientFactory{Clients: runtimestubs.Clients{KubernetesInterface: fake.NewSimpleClientset()}},
+		}
+		p.EDPCollector = edp.NewCollector(edpClient, hookScanner{during: func() { during(p) }})
+
+		return p
+	}
+
+	newRuntime := func(record kubeconfigprovider.Record) kebruntime.RuntimeDTO {
+		runtime := kmctesting.NewRuntimesDTO(record.SubAccountID, record.ShootName,
+			kmctesting.WithProvisioningSucceededStatus(kebruntime.StateSucceeded))
+		runtime.RuntimeID = record.RuntimeID
+		runtime.GlobalAccountID = record.GlobalAccountID
+
+		return runtime
+	}
+
+	t.Run("region and plan updated during the scrape are kept together with the new scans", func(t *testing.T) {
+		subAccID := uuid.New().String()
+		record := NewRecord(subAccID, "shoot", "foo")
+		record.RuntimeID = uuid.New().String()
+		record.Region = "westeurope"
+		record.PlanName = "azure"
+
+		p := newProcess(t, func(p *Process) {
+			runtime := newRuntime(record)
+			runtime.ProviderRegion = "northeurope"
+			runtime.ServicePlanName = "azure_lite"
+			p.Update(runtime2.FromKEB([]kebruntime.RuntimeDTO{runtime}))
+		})
+		require.NoError(t, p.Cache.Add(subAccID, record, gocache.NoExpiration))
+
+		require.True(t, p.processSubAccountID(subAccID, 1))
+
+		item, found := p.Cache.Get(subAccID)
+		require.True(t, found)
+
+		gotRecord := item.(kubeconfigprovider.Record)
+		require.Equal(t, "northeurope", gotRecord.Region)
+		require.Equal(t, "azure_lite", gotRecord.PlanName)
+		require.Contains(t, gotRecord.ScanMap, hookScanner{}.ID())
+	})
+
+	t.Run("records deleted during the scrape are not brought back", func(t *testing.T) {
+		subAccID := uuid.New().String()
+		record := NewRecord(subAccID, "shoot", "foo")
+		record.RuntimeID = uuid.New().String()
+
+		p := newProcess(t, func(p *Process) {
+			p.Delete(runtime2.FromKEB([]kebruntime.RuntimeDTO{newRuntime(record)})[0])
+		})
+		require.NoError(t, p.Cache.Add(subAccID, record, gocache.NoExpiration))
+
+		require.False(t, p.processSubAccountID(subAccID, 1))
+
+		_, found := p.Cache.Get(subAccID)
+		require.False(t, found)
+	})
+
+	t.Run("records replaced with a new shoot during the scrape are kept", func(t *testing.T) {
+		subAccID := uuid.New().String()
+		record := NewRecord(subAccID, "shoot", "foo")
+		record.RuntimeID = uuid.New().String()
+
+		p := newProcess(t, func(p *Process) {
+			runtime := newRuntime(record)
+			runtime.ShootName = "new-shoot"
+			p.Update(runtime2.FromKEB([]kebruntime.RuntimeDTO{runtime}))
+		})
+		require.NoError(t, p.Cache.Add(subAccID, record, gocache.NoExpiration))
+
+		require.False(t, p.processSubAccountID(subAccID, 1))
+
+		item, found := p.Cache.Get(subAccID)
+		require.True(t, found)
+
+		gotRecord := item.(kubeconfigprovider.Record)
+		require.Equal(t, "new-shoot", gotRecord.ShootName)
+		require.Nil(t, gotRecord.ScanMap)
+	})
+}
+
+func TestProcessSubAccountIDWithRecordDeletedDuringKubeconfigLookup(t *testing.T) {
+	subAccID := uuid.New().String()
+	record := NewRecord(subAccID, "shoot", "foo")
+	record.RuntimeID = uuid.New().String()
+
+	p := &Process{
+		Queue:  workqueue.NewTypedDelayingQueue[string](),
+		Cache:  gocache.New(gocache.NoExpiration, gocache.NoExpiration),
+		Logger: logger.NewLogger(zapcore.InfoLevel),
+	}
+	p.KubeconfigProvider = kubeconfigProviderFunc(func(runtimeID string) ([]byte, error) {
+		// the runtime is deprovisioned while its kubeconfig is looked up
+		p.deleteFromCache(kebruntime.RuntimeDTO{SubAccountID: subAccID, RuntimeID: runtimeID})
+
+		return nil, fmt.Errorf("%w: %s", kubeconfigprovider.ErrNotFound, runtimeID)
+	})
+	require.NoError(t, p.Cache.Add(subAccID, record, gocache.NoExpiration))
+
+	require.False(t, p.processSubAccountID(subAccID, 1))
+
+	_, found := p.Cache.Get(subAccID)
+	require.False(t, found)
+	// the scrape interval is 0, so a requeued subaccount would be in the queue already
+	require.Equal(t, 0, p.Queue.Len())
+}
@@ -1,13 +1,16 @@
 package process
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 
-	"github.com/patrickmn/go-cache"
 	"go.uber.org/zap"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/client-go/tools/clientcmd"
 
+	"github.com/kyma-project/kyma-metrics-collector/pkg/collector"
 	log "github.com/kyma-project/kyma-metrics-collector/pkg/logger"
 	"github.com/kyma-project/kyma-metrics-collector/pkg/runtime"
 	kmccache "github.com/kyma-project/kyma-metrics-collector/pkg/runtime/kubeconfigprovider"
@@ -46,15 +49,7 @@ func (p *Process) processSubAccountID(subAccountID string, identifier int) bool
 	// Get kubeConfig from kubeconfigprovider
 	kubeConfig, err := p.KubeconfigProvider.Get(record.RuntimeID)
 	if err != nil {
-		p.handleError(&record, subAccountID, identifier, fmt.Errorf("failed to load kubeconfig from kubeconfigprovider: %w", err))
-
-		return false
-	}
-
-	// Create REST client config from kubeConfig
-	restClientConfig, err := clientcmd.RESTConfigFromKubeConfig(kubeConfig)
-	if err != nil {
-		p.handleError(&record, subAccountID, identifier, fmt.Errorf("failed to create REST config from kubeconfig: %w", err))
+		p.handleKubeconfigError(&record, subAccountID, identifier, err)
 
 		return false
 	}
@@ -68,19 +63,30 @@ func (p *Process) processSubAccountID(subAccountID string, identifier int) bool
 		GlobalAccountID: record.GlobalAccountID,
 		ShootName:       record.ShootName,
 		ProviderType:    record.ProviderType,
+		Region:          record.Region,
+		PlanName:        record.PlanName,
 	}
 
-	clients, err := p.ClientFactory.NewClients(restClientConfig)
+	newScans, err := p.collectAndSend(ctx, &runtimeInfo, record, kubeConfig, identifier)
 	if err != nil {
-		p.handleError(&record, subAccountID, identifier, fmt.Errorf("failed to create clients: %w", err))
+		if newScans != nil && !errors.Is(err, collector.ErrNotSent) {
+			// the measurements were sent, so the sent scans are the baseline of the next scrape. Otherwise, the usage
+			// since the previous scans would be sent again.
+			if !p.storeScans(record, newScans) {
+				p.handleUntrackedRecord(&record, subAccountID, identifier)
+
+				return false
+			}
+		}
+
+		p.handleError(&record, subAccountID, identifier, err)
 
 		return false
 	}
-	defer clients.CloseConnections()
 
-	newScans, err := p.EDPCollector.CollectAndSend(ctx, &runtimeInfo, clients, record.ScanMap)
-	if err != nil {
-		p.handleError(&record, subAccountID, identifier, fmt.Errorf("failed to collect and send measurements to EDP backend: %w", err))
+	// Update kubeconfigprovider
+	if !p.storeScans(record, newScans) {
+		p.handleUntrackedRecord(&record, subAccountID, identifier)
 
 		return false
 	}
@@ -93,14 +99,169 @@ func (p *Process) processSubAccountID(subAccountID string, identifier int) bool
 	recordSubAccountProcessed(true, record)
 	recordSubAccountProcessedTimeStamp(record)
 
-	// Update kubeconfigprovider
-	p.Cache.Set(record.SubAccountID, record, cache.NoExpiration)
 	p.queueProcessingLogger(&record, subAccountID, identifier).
 		Debug("updated kubeconfigprovider with new record")
 
 	return true
 }
 
+// storeScans stores the scans as the baseline of the next scrape in the record of the subaccount. The region and plan
+// may have been updated by the runtime source during the scrape, so only the fields owned by the worker are written.
+// It returns false if the subaccount is not tracked with the runtime and shoot of the record anymore.
+func (p *Process) storeScans(record kmccache.Record, scans collector.ScanMap) bool {
+	return p.updateRecord(record, func(current *kmccache.Record) {
+		current.ScanMap = scans
+		current.KubeconfigMissingScrapes = 0
+	})
+}
+
+// handleUntrackedRecord drops the subaccount from the queue, as the runtime source deleted or replaced its record
+// while it was processed. A replaced record is queued by the runtime source again.
+func (p *Process) handleUntrackedRecord(record *kmccache.Record, subAccountID string, identifier int) {
+	p.queueProcessingLogger(record, subAccountID, identifier).With(log.KeyRequeue, log.ValueFalse).
+		Info("subAccountID is not tracked with the processed record anymore, dropping it")
+
+	recordSubAccountProcessed(false, kmccache.Record{SubAccountID: subAccountID})
+}
+
+// collectAndSend collects the measurements of the runtime with the given kubeconfig and sends them to EDP.
+// It returns the collected scans, or nil if the clients could not be created.
+func (p *Process) collectAndSend(ctx context.Context, runtimeInfo *runtime.Info, record kmccache.Record, kubeConfig []byte, identifier int) (collector.ScanMap, error) {
+	clients, release, err := p.authorizedClients(record, kubeConfig, identifier)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create clients: %w", err)
+	}
+	defer release()
+
+	newScans, err := p.EDPCollector.CollectAndSend(ctx, runtimeInfo, clients, record.ScanMap)
+	if err != nil {
+		return newScans, fmt.Errorf("failed to collect and send measurements to EDP backend: %w", err)
+	}
+
+	return newScans, nil
+}
+
+// authorizedClients returns the clients of the runtime for the kubeconfig. If the runtime rejects the credentials of
+// the kubeconfig, e.g. because they were rotated, it returns the clients of a refreshed kubeconfig instead.
+// The credentials are checked before collecting, because failed scans fall back to the previous scans, which are sent
+// anyway. Retrying after sending would send the measurements twice.
+func (p *Process) authorizedClients(record kmccache.Record, kubeConfig []byte, identifier int) (runtime.Interface, func(), error) {
+	clients, release, err := p.clients(record.RuntimeID, kubeConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = checkCredentials(clients)
+	if !k8serr.IsUnauthorized(err) {
+		return clients, release, nil
+	}
+
+	freshKubeConfig, refreshed := p.refreshKubeconfig(record.RuntimeID, kubeConfig)
+	if !refreshed {
+		// the scans fail and fall back to the previous scans
+		return clients, release, nil
+	}
+
+	p.queueProcessingLogger(&record, record.SubAccountID, identifier).With(log.KeyError, err.Error()).
+		Info("runtime rejected the credentials of the kubeconfig, retrying with a refreshed kubeconfig")
+	release()
+
+	clients, release, err = p.clients(record.RuntimeID, freshKubeConfig)
+	if err != nil {
+		recordUnauthorizedRetry(false)
+
+		return nil, nil, err
+	}
+
+	recordUnauthorizedRetry(!k8serr.IsUnauthorized(checkCredentials(clients)))
+
+	return clients, release, nil
+}
+
+// checkCredentials requests the version of the API server, which every authenticated user may read.
+func checkCredentials(clients runtime.Interface) error {
+	_, err := clients.K8s().Discovery().ServerVersion()
+
+	return err
+}
+
+// refreshKubeconfig drops the kubeconfig of the runtime from the KubeconfigProvider and loads it again.
+// It returns false if the provider does not return a different kubeconfig, so a retry would fail again.
+func (p *Process) refreshKubeconfig(runtimeID string, staleKubeConfig []byte) ([]byte, bool) {
+	if invalidator, ok := p.KubeconfigProvider.(runtime.ConfigInvalidator); ok {
+		invalidator.Invalidate(runtimeID)
+	}
+
+	kubeConfig, err := p.KubeconfigProvider.Get(runtimeID)
+	if err != nil || bytes.Equal(kubeConfig, staleKubeConfig) {
+		return nil, false
+	}
+
+	return kubeConfig, true
+}
+
+// clients returns the clients of the runtime from the ClientPool, which are released to the pool when release is
+// called. Without a pool, new clients are created, and their connections are closed when release is called.
+func (p *Process) clients(runtimeID string, kubeConfig []byte) (runtime.Interface, func(), error) {
+	if p.ClientPool != nil {
+		return p.ClientPool.Get(runtimeID, kubeConfig)
+	}
+
+	// Create REST client config from kubeConfig
+	restClientConfig, err := clientcmd.RESTConfigFromKubeConfig(kubeConfig)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create REST config from kubeconfig: %w", err)
+	}
+
+	clients, err := p.ClientFactory.NewClients(restClientConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return clients, clients.CloseConnections, nil
+}
+
+// handleKubeconfigError requeues the subaccount if its kubeconfig could not be loaded. A missing kubeconfig is expected
+// for a few scrapes while the runtime is provisioned or deprovisioned, so it is only reported as an error once it is
+// missing for kubeconfigMissingScrapes consecutive scrapes. Transient failures are logged as warnings.
+func (p *Process) handleKubeconfigError(record *kmccache.Record, subAccountID string, identifier int, err error) {
+	reason := kmccache.FailureReason(err)
+	logger := p.queueProcessingLogger(record, subAccountID, identifier).With(log.KeyFailureReason, reason).
+		With(log.KeyError, err.Error()).With(log.KeyRequeue, log.ValueTrue)
+
+	switch reason {
+	case kmccache.FailureReasonNotFound:
+		tracked := p.updateRecord(*record, func(current *kmccache.Record) {
+			current.KubeconfigMissingScrapes++
+			record.KubeconfigMissingScrapes = current.KubeconfigMissingScrapes
+		})
+		if !tracked {
+			p.handleUntrackedRecord(record, subAccountID, identifier)
+
+			return
+		}
+
+		logger = logger.With(log.KeyKubeconfigMissingScrapes, record.KubeconfigMissingScrapes)
+
+		switch {
+		case record.KubeconfigMissingScrapes < kubeconfigMissingScrapes:
+			logger.Info("kubeconfig of runtime not found")
+		case record.KubeconfigMissingScrapes == kubeconfigMissingScrapes:
+			logger.With(log.KeyResult, log.ValueFail).Error("kubeconfig of runtime is missing persistently")
+		default:
+			logger.Debug("kubeconfig of runtime is still missing")
+		}
+	case kmccache.FailureReasonTransient:
+		logger.Warn("failed to load kubeconfig of runtime, retrying with the next scrape")
+	default:
+		logger.With(log.KeyResult, log.ValueFail).Error("failed to load kubeconfig of runtime")
+	}
+
+	p.Queue.AddAfter(subAccountID, p.ScrapeInterval)
+
+	recordSubAccountProcessed(false, *record)
+}
+
 func (p *Process) handleError(record *kmccache.Record, subAccountID string, identifier int, err error) {
 	p.queueProcessingLogger(record, subAccountID, identifier).
 		Errorf(err.Error())
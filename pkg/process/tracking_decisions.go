@@ -0,0 +1,120 @@
+package process
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+
+	log "github.com/kyma-project/kyma-metrics-collector/pkg/logger"
+)
+
+// TrackingDecisionsPath is the path of the admin endpoint listing the tracking decisions.
+const TrackingDecisionsPath = "/admin/tracking-decisions"
+
+// subAccountQueryParam filters the tracking decisions returned by the admin endpoint by subaccount.
+const subAccountQueryParam = "subaccount"
+
+// trackingPolicy returns the configured tracking policy or the default one.
+func (p *Process) trackingPolicy() *TrackingPolicy {
+	if p.TrackingPolicy == nil {
+		return DefaultTrackingPolicy()
+	}
+
+	return p.TrackingPolicy
+}
+
+// recordTrackingDecision stores the decision and logs it. Changed decisions are logged on info level,
+// repeated ones only on debug level to not flood the logs on every resync.
+func (p *Process) recordTrackingDecision(decision TrackingDecision) {
+	p.trackingDecisionsLock.Lock()
+
+	if p.trackingDecisions == nil {
+		p.trackingDecisions = make(map[string]TrackingDecision)
+	}
+
+	previous, found := p.trackingDecisions[decision.SubAccountID]
+	p.trackingDecisions[decision.SubAccountID] = decision
+	p.trackingDecisionsLock.Unlock()
+
+	logger := p.namedLogger().
+		With(log.KeySubAccountID, decision.SubAccountID).
+		With(log.KeyRuntimeID, decision.RuntimeID).
+		With(log.KeyTrackable, decision.Trackable).
+		With(log.KeyTrackingReason, decision.Reason)
+
+	if decision.LastOperation != nil {
+		logger = logger.With(log.KeyLastOperation, decision.LastOperation.Type+"/"+decision.LastOperation.State)
+	}
+
+	if found && previous.Trackable == decision.Trackable && previous.Reason == decision.Reason {
+		logger.Debug("Tracking decision for runtime")
+		return
+	}
+
+	logger.Info("Tracking decision for runtime changed")
+}
+
+// deleteTrackingDecision removes the decision of a subaccount deleted in the runtime source.
+func (p *Process) deleteTrackingDecision(subAccountID string) {
+	p.trackingDecisionsLock.Lock()
+	defer p.trackingDecisionsLock.Unlock()
+
+	delete(p.trackingDecisions, subAccountID)
+}
+
+// pruneTrackingDecisions removes the decisions of all subaccounts missing in a full resync.
+func (p *Process) pruneTrackingDecisions(validSubAccounts map[string]bool) {
+	p.trackingDecisionsLock.Lock()
+	defer p.trackingDecisionsLock.Unlock()
+
+	for subAccountID := range p.trackingDecisions {
+		if !validSubAccounts[subAccountID] {
+			delete(p.trackingDecisions, subAccountID)
+		}
+	}
+}
+
+// TrackingDecisions returns the latest tracking decision of every runtime, sorted by subaccount.
+func (p *Process) TrackingDecisions() []TrackingDecision {
+	p.trackingDecisionsLock.RLock()
+	defer p.trackingDecisionsLock.RUnlock()
+
+	decisions := make([]TrackingDecision, 0, len(p.trackingDecisions))
+	for _, decision := range p.trackingDecisions {
+		decisions = append(decisions, decision)
+	}
+
+	sort.Slice(decisions, func(i, j int) bool {
+		return decisions[i].SubAccountID < decisions[j].SubAccountID
+	})
+
+	return decisions
+}
+
+// ServeTrackingDecisions serves the tracking decisions as JSON. The optional subaccount query parameter
+// returns only the decision of that subaccount, or 404 if there is none.
+func (p *Process) ServeTrackingDecisions(writer http.ResponseWriter, request *http.Request) {
+	var response any
+
+	if subAccountID := request.URL.Query().Get(subAccountQueryParam); subAccountID == "" {
+		response = p.TrackingDecisions()
+	} else {
+		p.trackingDecisionsLock.RLock()
+		decision, found := p.trackingDecisions[subAccountID]
+		p.trackingDecisionsLock.RUnlock()
+
+		if !found {
+			http.Error(writer, "no tracking decision for subaccount "+subAccountID, http.StatusNotFound)
+			return
+		}
+
+		response = decision
+	}
+
+	writer.Header().Set("Content-Type", "application/json")
+
+	if err := json.NewEncoder(writer).Encode(response); err != nil {
+		p.namedLogger().With(log.KeyResult, log.ValueFail).With(log.KeyError, err.Error()).
+			Error("write tracking decisions response")
+	}
+}
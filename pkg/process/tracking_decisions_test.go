@@ -0,0 +1,78 @@
+package process
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	kebruntime "github.com/kyma-project/kyma-environment-broker/common/runtime"
+	gocache "github.com/patrickmn/go-cache"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+	"k8s.io/client-go/util/workqueue"
+
+	"github.com/kyma-project/kyma-metrics-collector/pkg/logger"
+	kmcruntime "github.com/kyma-project/kyma-metrics-collector/pkg/runtime"
+	kmctesting "github.com/kyma-project/kyma-metrics-collector/pkg/testing"
+)
+
+func TestServeTrackingDecisions(t *testing.T) {
+	kebFetchedClusters.Reset()
+
+	p := Process{
+		Queue:  workqueue.NewTypedDelayingQueue[string](),
+		Cache:  gocache.New(gocache.NoExpiration, gocache.NoExpiration),
+		Logger: logger.NewLogger(zapcore.InfoLevel),
+	}
+
+	provisioned := kmctesting.NewRuntimesDTO("subaccount-b", "shoot-b",
+		kmctesting.WithProvisioningSucceededStatus(kebruntime.StateSucceeded))
+	failed := kmctesting.NewRuntimesDTO("subaccount-a", "shoot-a",
+		kmctesting.WithProvisioningFailedState)
+	removed := kmctesting.NewRuntimesDTO("subaccount-c", "shoot-c",
+		kmctesting.WithProvisioningSucceededStatus(kebruntime.StateSucceeded))
+
+	p.populateCacheAndQueue(kmcruntime.FromKEB([]kebruntime.RuntimeDTO{provisioned, failed, removed}))
+	p.populateCacheAndQueue(kmcruntime.FromKEB([]kebruntime.RuntimeDTO{provisioned, failed}))
+
+	t.Run("lists the decisions of all runtimes of the last resync", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		p.ServeTrackingDecisions(recorder, httptest.NewRequest(http.MethodGet, TrackingDecisionsPath, nil))
+		require.Equal(t, http.StatusOK, recorder.Code)
+
+		var decisions []TrackingDecision
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &decisions))
+		require.Len(t, decisions, 2)
+		require.Equal(t, "subaccount-a", decisions[0].SubAccountID)
+		require.False(t, decisions[0].Trackable)
+		require.Equal(t, ReasonProvisioningFailed, decisions[0].Reason)
+		require.Equal(t, "subaccount-b", decisions[1].SubAccountID)
+		require.True(t, decisions[1].Trackable)
+		require.Equal(t, ReasonProvisioned, decisions[1].Reason)
+		require.NotNil(t, decisions[1].LastOperation)
+		require.Equal(t, "provisioning", decisions[1].LastOperation.Type)
+	})
+
+	t.Run("returns the decision of a subaccount", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		p.ServeTrackingDecisions(recorder, httptest.NewRequest(http.MethodGet, TrackingDecisionsPath+"?subaccount=subaccount-b", nil))
+		require.Equal(t, http.StatusOK, recorder.Code)
+
+		var decision TrackingDecision
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &decision))
+		require.Equal(t, "shoot-b", decision.ShootName)
+		require.Equal(t, ReasonProvisioned, decision.Reason)
+	})
+
+	t.Run("returns not found for an unknown subaccount", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		p.ServeTrackingDecisions(recorder, httptest.NewRequest(http.MethodGet, TrackingDecisionsPath+"?subaccount=subaccount-c", nil))
+		require.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+
+	t.Run("deleted runtimes are removed", func(t *testing.T) {
+		p.Delete(kmcruntime.FromKEB([]kebruntime.RuntimeDTO{failed})[0])
+		require.Len(t, p.TrackingDecisions(), 1)
+	})
+}
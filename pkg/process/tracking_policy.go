@@ -0,0 +1,195 @@
+package process
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	kebruntime "github.com/kyma-project/kyma-environment-broker/common/runtime"
+	"sigs.k8s.io/yaml"
+
+	kmcruntime "github.com/kyma-project/kyma-metrics-collector/pkg/runtime"
+)
+
+// OperationNone is the operation type a tracking rule uses to match runtimes without any operation.
+const OperationNone = "none"
+
+// TrackingPolicy decides which runtimes are tracked and billed. The rules are evaluated in order against the plan
+// and the last operation of a runtime, and the first matching rule decides. If no rule matches, the runtime is not tracked.
+// Runtimes flagged by the runtime source have no operations, so only the rules which do not match operations or states
+// are evaluated for them, and only to stop billing runtimes the source flagged as billable, e.g. trial runtimes.
+type TrackingPolicy struct {
+	Rules []TrackingRule `json:"rules"`
+}
+
+// TrackingRule matches runtimes by the type and state of their last operation and their plan.
+// Empty lists match any value.
+type TrackingRule struct {
+	// Operations are the types of the last operation, e.g. provisioning or suspension, or none for runtimes without operations.
+	Operations []string `json:"operations,omitempty"`
+	// States are the states of the last operation as reported by KEB, e.g. succeeded, failed or in progress.
+	States []string `json:"states,omitempty"`
+	// Plans are the service plans of the runtime.
+	Plans []string `json:"plans,omitempty"`
+	// Trackable decides if the matched runtimes are tracked.
+	Trackable bool `json:"trackable"`
+	// Reason is recorded in the tracking decision and the reason label of the metrics.
+	Reason string `json:"reason"`
+}
+
+// DefaultTrackingPolicy returns the policy used if none is configured: runtimes are billed after a successful
+// provisioning or unsuspension, stop being billed on a suspension or deprovisioning, and are billed after any other operation.
+func DefaultTrackingPolicy() *TrackingPolicy {
+	succeeded := []string{string(kebruntime.StateSucceeded)}
+	failed := []string{string(kebruntime.StateFailed)}
+
+	return &TrackingPolicy{Rules: []TrackingRule{
+		{Operations: []string{OperationNone}, Reason: ReasonNoOperations},
+		{Operations: []string{provisioning.String()}, States: succeeded, Trackable: true, Reason: ReasonProvisioned},
+		{Operations: []string{provisioning.String()}, States: failed, Reason: ReasonProvisioningFailed},
+		{Operations: []string{provisioning.String()}, Reason: ReasonProvisioningInProgress},
+		{Operations: []string{unsuspension.String()}, States: succeeded, Trackable: true, Reason: ReasonUnsuspended},
+		{Operations: []string{unsuspension.String()}, States: failed, Reason: ReasonUnsuspensionFailed},
+		{Operations: []string{unsuspension.String()}, Reason: ReasonUnsuspensionInProgress},
+		{Operations: []string{suspension.String()}, Reason: ReasonSuspended},
+		{Operations: []string{deprovisioning.String()}, Reason: ReasonDeprovisioned},
+		{Trackable: true, Reason: ReasonActive},
+	}}
+}
+
+// LoadTrackingPolicy loads the tracking policy from a YAML or JSON file. Without a file, the default policy is returned.
+func LoadTrackingPolicy(path string) (*TrackingPolicy, error) {
+	if path == "" {
+		return DefaultTrackingPolicy(), nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tracking policy: %w", err)
+	}
+
+	return ParseTrackingPolicy(data)
+}
+
+// ParseTrackingPolicy parses and validates a tracking policy in YAML or JSON format.
+func ParseTrackingPolicy(data []byte) (*TrackingPolicy, error) {
+	var policy TrackingPolicy
+	if err := yaml.UnmarshalStrict(data, &policy); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal tracking policy: %w", err)
+	}
+
+	if err := policy.validate(); err != nil {
+		return nil, fmt.Errorf("invalid tracking policy: %w", err)
+	}
+
+	return &policy, nil
+}
+
+func (p *TrackingPolicy) validate() error {
+	if len(p.Rules) == 0 {
+		return fmt.Errorf("no rules defined")
+	}
+
+	knownOperations := []string{OperationNone}
+	for state := provisioning; state <= unsuspension; state++ {
+		knownOperations = append(knownOperations, state.String())
+	}
+
+	for i, rule := range p.Rules {
+		if rule.Reason == "" {
+			return fmt.Errorf("rule %d does not define a reason", i)
+		}
+
+		for _, operation := range rule.Operations {
+			if !slices.Contains(knownOperations, operation) {
+				return fmt.Errorf("rule %d matches unknown operation %q, must be one of %s",
+					i, operation, strings.Join(knownOperations, ", "))
+			}
+		}
+	}
+
+	return nil
+}
+
+// decide decides if a runtime is trackable and records the operations considered and the reason.
+func (p *TrackingPolicy) decide(runtime kmcruntime.Runtime) TrackingDecision {
+	decision := newTrackingDecision(runtime.RuntimeDTO)
+
+	if runtime.Billable != nil {
+		return p.decideSourceFlagged(decision, *runtime.Billable, runtime.ServicePlanName)
+	}
+
+	// Sort operations by time
+	for _, operation := range sortOperations(runtime.RuntimeDTO) {
+		decision.Operations = append(decision.Operations, TrackingOperation{
+			Type:      operation.state.String(),
+			State:     operation.rawState,
+			CreatedAt: operation.time,
+		})
+	}
+
+	lastOperation := TrackingOperation{Type: OperationNone}
+	if len(decision.Operations) > 0 {
+		decision.LastOperation = &decision.Operations[len(decision.Operations)-1]
+		lastOperation = *decision.LastOperation
+	}
+
+	decision.Reason = ReasonNoMatchingRule
+
+	for _, rule := range p.Rules {
+		if rule.matches(lastOperation, runtime.ServicePlanName) {
+			decision.Trackable = rule.Trackable
+			decision.Reason = rule.Reason
+
+			break
+		}
+	}
+
+	return decision
+}
+
+// decideSourceFlagged decides if a runtime flagged by the runtime source is trackable. A billable runtime is not tracked
+// if the first rule without operations and states matching its plan does not track it.
+func (p *TrackingPolicy) decideSourceFlagged(decision TrackingDecision, billable bool, plan string) TrackingDecision {
+	if !billable {
+		decision.Reason = ReasonSourceNotBillable
+
+		return decision
+	}
+
+	decision.Trackable = true
+	decision.Reason = ReasonSourceBillable
+
+	for _, rule := range p.Rules {
+		if len(rule.Operations) > 0 || len(rule.States) > 0 || !matchesAny(rule.Plans, plan) {
+			continue
+		}
+
+		if !rule.Trackable {
+			decision.Trackable = false
+			decision.Reason = rule.Reason
+		}
+
+		break
+	}
+
+	return decision
+}
+
+func (r TrackingRule) matches(lastOperation TrackingOperation, plan string) bool {
+	return matchesAny(r.Operations, lastOperation.Type) &&
+		matchesAny(r.States, lastOperation.State) &&
+		matchesAny(r.Plans, plan)
+}
+
+// matchesAny returns true if the values are empty or contain the value, ignoring the case.
+func matchesAny(values []string, value string) bool {
+	if len(values) == 0 {
+		return true
+	}
+
+	return slices.ContainsFunc(values, func(v string) bool {
+		return strings.EqualFold(v, value)
+	})
+}
@@ -0,0 +1,202 @@
+package process
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	kebruntime "github.com/kyma-project/kyma-environment-broker/common/runtime"
+	"github.com/stretchr/testify/require"
+
+	kmcruntime "github.com/kyma-project/kyma-metrics-collector/pkg/runtime"
+)
+
+const customTrackingPolicy = `
+rules:
+# never bill trial runtimes
+- plans: [trial]
+  trackable: false
+  reason: trial
+# keep billing while a deprovisioning is retried
+- operations: [deprovisioning]
+  states: [failed]
+  trackable: true
+  reason: deprovisioning_failed
+- operations: [update]
+  states: [failed]
+  trackable: false
+  reason: update_failed
+- operations: [provisioning, unsuspension]
+  states: [succeeded]
+  trackable: true
+  reason: provisioned
+`
+
+func TestTrackingPolicy(t *testing.T) {
+	created := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	newRuntime := func(plan string, lastOperation func(*kebruntime.RuntimeStatus, kebruntime.Operation), state kebruntime.State) kmcruntime.Runtime {
+		runtime := kebruntime.RuntimeDTO{ServicePlanName: plan}
+		runtime.Status.Provisioning = &kebruntime.Operation{State: string(kebruntime.StateSucceeded), CreatedAt: created}
+
+		if lastOperation != nil {
+			lastOperation(&runtime.Status, kebruntime.Operation{State: string(state), CreatedAt: created.Add(time.Hour)})
+		}
+
+		return kmcruntime.Runtime{RuntimeDTO: runtime}
+	}
+
+	deprovisioningOp := func(status *kebruntime.RuntimeStatus, op kebruntime.Operation) { status.Deprovisioning = &op }
+	updateOp := func(status *kebruntime.RuntimeStatus, op kebruntime.Operation) {
+		status.Update = &kebruntime.OperationsData{Data: []kebruntime.Operation{op}}
+	}
+	suspensionOp := func(status *kebruntime.RuntimeStatus, op kebruntime.Operation) {
+		status.Suspension = &kebruntime.OperationsData{Data: []kebruntime.Operation{op}}
+	}
+
+	custom, err := ParseTrackingPolicy([]byte(customTrackingPolicy))
+	require.NoError(t, err)
+
+	billable := true
+	notBillable := false
+
+	testCases := []struct {
+		name              string
+		policy            *TrackingPolicy
+		runtime           kmcruntime.Runtime
+		expectedTrackable bool
+		expectedReason    string
+	}{
+		{
+			name:           "default policy stops billing on failed deprovisioning",
+			policy:         DefaultTrackingPolicy(),
+			runtime:        newRuntime("aws", deprovisioningOp, kebruntime.StateFailed),
+			expectedReason: ReasonDeprovisioned,
+		},
+		{
+			name:              "default policy bills after failed update",
+			policy:            DefaultTrackingPolicy(),
+			runtime:           newRuntime("aws", updateOp, kebruntime.StateFailed),
+			expectedTrackable: true,
+			expectedReason:    ReasonActive,
+		},
+		{
+			name:              "default policy bills trial plans",
+			policy:            DefaultTrackingPolicy(),
+			runtime:           newRuntime("trial", nil, ""),
+			expectedTrackable: true,
+			expectedReason:    ReasonProvisioned,
+		},
+		{
+			name:              "custom policy keeps billing during failed deprovisioning",
+			policy:            custom,
+			runtime:           newRuntime("aws", deprovisioningOp, kebruntime.StateFailed),
+			expectedTrackable: true,
+			expectedReason:    "deprovisioning_failed",
+		},
+		{
+			name:           "custom policy does not bill after failed update",
+			policy:         custom,
+			runtime:        newRuntime("aws", updateOp, kebruntime.StateFailed),
+			expectedReason: "update_failed",
+		},
+		{
+			name:           "custom policy never bills trial plans",
+			policy:         custom,
+			runtime:        newRuntime("Trial", nil, ""),
+			expectedReason: "trial",
+		},
+		{
+			name:              "custom policy bills provisioned runtimes",
+			policy:            custom,
+			runtime:           newRuntime("aws", nil, ""),
+			expectedTrackable: true,
+			expectedReason:    ReasonProvisioned,
+		},
+		{
+			name:           "no matching rule is not trackable",
+			policy:         custom,
+			runtime:        newRuntime("aws", suspensionOp, kebruntime.StateSucceeded),
+			expectedReason: ReasonNoMatchingRule,
+		},
+		{
+			name:           "custom policy never bills trial plans flagged as billable by the source",
+			policy:         custom,
+			runtime:        kmcruntime.Runtime{RuntimeDTO: kebruntime.RuntimeDTO{ServicePlanName: "trial"}, Billable: &billable},
+			expectedReason: "trial",
+		},
+		{
+			name:              "custom policy bills other plans flagged as billable by the source",
+			policy:            custom,
+			runtime:           kmcruntime.Runtime{RuntimeDTO: kebruntime.RuntimeDTO{ServicePlanName: "aws"}, Billable: &billable},
+			expectedTrackable: true,
+			expectedReason:    ReasonSourceBillable,
+		},
+		{
+			name:              "default policy bills runtimes flagged as billable by the source",
+			policy:            DefaultTrackingPolicy(),
+			runtime:           kmcruntime.Runtime{RuntimeDTO: kebruntime.RuntimeDTO{ServicePlanName: "trial"}, Billable: &billable},
+			expectedTrackable: true,
+			expectedReason:    ReasonSourceBillable,
+		},
+		{
+			name:           "policy does not bill runtimes flagged as not billable by the source",
+			policy:         custom,
+			runtime:        kmcruntime.Runtime{RuntimeDTO: kebruntime.RuntimeDTO{ServicePlanName: "aws"}, Billable: &notBillable},
+			expectedReason: ReasonSourceNotBillable,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			decision := tc.policy.decide(tc.runtime)
+			require.Equal(t, tc.expectedTrackable, decision.Trackable)
+			require.Equal(t, tc.expectedReason, decision.Reason)
+		})
+	}
+}
+
+func TestParseTrackingPolicy(t *testing.T) {
+	testCases := []struct {
+		name        string
+		data        string
+		expectedErr string
+	}{
+		{name: "valid", data: customTrackingPolicy},
+		{name: "json", data: `{"rules": [{"operations": ["none"], "trackable": false, "reason": "no_operations"}]}`},
+		{name: "no rules", data: "rules: []", expectedErr: "no rules defined"},
+		{name: "missing reason", data: "rules:\n- trackable: true\n", expectedErr: "rule 0 does not define a reason"},
+		{name: "unknown operation", data: "rules:\n- {operations: [upgrade], reason: a}\n", expectedErr: `unknown operation "upgrade"`},
+		{name: "unknown field", data: "rules:\n- {operation: [update], reason: a}\n", expectedErr: "failed to unmarshal"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseTrackingPolicy([]byte(tc.data))
+			if tc.expectedErr != "" {
+				require.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestLoadTrackingPolicy(t *testing.T) {
+	policy, err := LoadTrackingPolicy("")
+	require.NoError(t, err)
+	require.Equal(t, DefaultTrackingPolicy(), policy)
+	require.NoError(t, policy.validate())
+
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(customTrackingPolicy), 0o600))
+
+	policy, err = LoadTrackingPolicy(path)
+	require.NoError(t, err)
+	require.Len(t, policy.Rules, 4)
+
+	_, err = LoadTrackingPolicy(filepath.Join(t.TempDir(), "missing.yaml"))
+	require.ErrorContains(t, err, "failed to read tracking policy")
+}
@@ -37,3 +37,15 @@ type ScanConverter interface {
 	UMMeasurementConverter
 	EDPMeasurementConverter
 }
+
+// SpecsVersioned is implemented by scans which are converted using the public cloud specs.
+type SpecsVersioned interface {
+	// SpecsVersion returns the version of the public cloud specs which was effective at the time of the scan.
+	SpecsVersion() string
+}
+
+// NodeBreakdowner is implemented by scans which break the nodes of a runtime down per worker pool and zone.
+type NodeBreakdowner interface {
+	// NodeBreakdown returns the breakdown of the nodes. Nodes which cannot be converted are skipped and reported in the error.
+	NodeBreakdown() (NodeBreakdown, error)
+}
@@ -0,0 +1,106 @@
+package node
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kyma-project/kyma-metrics-collector/pkg/config"
+	"github.com/kyma-project/kyma-metrics-collector/pkg/resource"
+)
+
+const (
+	workerPoolLabel   = "worker.gardener.cloud/pool"
+	zoneLabel         = "topology.kubernetes.io/zone"
+	architectureLabel = "kubernetes.io/arch"
+)
+
+// NodeBreakdown returns the nodes per Gardener worker pool and per zone.
+func (s *Scan) NodeBreakdown() (resource.NodeBreakdown, error) {
+	pools := nodeGroups{}
+	zones := nodeGroups{}
+
+	var errs []error
+
+	for _, node := range s.list.Items {
+		nodeType := strings.ToLower(node.Labels[nodeInstanceTypeLabel])
+
+		vmFeature := s.specs.ResolveFeature(s.providerType, s.region, s.plan, nodeType)
+		if vmFeature == nil {
+			errs = append(errs, fmt.Errorf("%w: provider: %s, node: %s", ErrUnknownVM, s.providerType, nodeType))
+			continue
+		}
+
+		architecture := node.Labels[architectureLabel]
+		pools.add(node.Labels[workerPoolLabel], architecture, nodeType, vmFeature)
+		zones.add(node.Labels[zoneLabel], architecture, nodeType, vmFeature)
+	}
+
+	return resource.NodeBreakdown{
+		Pools: pools.sorted(),
+		Zones: zones.sorted(),
+	}, errors.Join(errs...)
+}
+
+// nodeGroups collects the nodes per group name.
+type nodeGroups map[string]*nodeGroup
+
+type nodeGroup struct {
+	resource.NodeGroup
+
+	architectures map[string]struct{}
+	vmTypes       map[string]int
+}
+
+func (g nodeGroups) add(name, architecture, vmType string, vmFeature *config.Feature) {
+	group, found := g[name]
+	if !found {
+		group = &nodeGroup{
+			NodeGroup:     resource.NodeGroup{Name: name},
+			architectures: map[string]struct{}{},
+			vmTypes:       map[string]int{},
+		}
+		g[name] = group
+	}
+
+	group.Count++
+	group.ProvisionedCPUs += vmFeature.CpuCores
+	group.ProvisionedRAMGb += vmFeature.Memory
+	group.vmTypes[vmType]++
+
+	if architecture != "" {
+		group.architectures[architecture] = struct{}{}
+	}
+}
+
+// sorted returns the groups, their architectures and VM types sorted by name.
+func (g nodeGroups) sorted() []resource.NodeGroup {
+	groups := make([]resource.NodeGroup, 0, len(g))
+
+	for _, group := range g {
+		result := group.NodeGroup
+
+		for architecture := range group.architectures {
+			result.Architectures = append(result.Architectures, architecture)
+		}
+
+		sort.Strings(result.Architectures)
+
+		for vmType, count := range group.vmTypes {
+			result.VMTypes = append(result.VMTypes, resource.VMType{Name: vmType, Count: count})
+		}
+
+		sort.Slice(result.VMTypes, func(i, j int) bool {
+			return result.VMTypes[i].Name < result.VMTypes[j].Name
+		})
+
+		groups = append(groups, result)
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].Name < groups[j].Name
+	})
+
+	return groups
+}
@@ -0,0 +1,112 @@
+package node
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kyma-project/kyma-metrics-collector/pkg/config"
+	"github.com/kyma-project/kyma-metrics-collector/pkg/resource"
+)
+
+func TestScan_NodeBreakdown(t *testing.T) {
+	newNode := func(vmType, pool, zone, architecture string) metav1.PartialObjectMetadata {
+		return metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{
+			nodeInstanceTypeLabel: vmType,
+			workerPoolLabel:       pool,
+			zoneLabel:             zone,
+			architectureLabel:     architecture,
+		}}}
+	}
+
+	scan := &Scan{
+		providerType: config.AWS,
+		specs: &config.PublicCloudSpecs{
+			Providers: config.Providers{
+				AWS: map[string]config.Feature{
+					"m5.large":  {CpuCores: 2, Memory: 8},
+					"m6g.large": {CpuCores: 2, Memory: 8},
+					"m5.xlarge": {CpuCores: 4, Memory: 16},
+				},
+			},
+		},
+		list: metav1.PartialObjectMetadataList{
+			Items: []metav1.PartialObjectMetadata{
+				newNode("m5.large", "cpu-worker-0", "eu-central-1a", "amd64"),
+				newNode("m5.large", "cpu-worker-0", "eu-central-1b", "amd64"),
+				newNode("m5.xlarge", "cpu-worker-1", "eu-central-1a", "amd64"),
+				newNode("m6g.large", "arm-worker", "eu-central-1a", "arm64"),
+				newNode("unknown", "cpu-worker-0", "eu-central-1a", "amd64"),
+				{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{nodeInstanceTypeLabel: "m5.large"}}},
+			},
+		},
+	}
+
+	breakdown, err := scan.NodeBreakdown()
+	require.ErrorIs(t, err, ErrUnknownVM)
+	require.Equal(t, resource.NodeBreakdown{
+		Pools: []resource.NodeGroup{
+			{
+				Name:             "",
+				VMTypes:          []resource.VMType{{Name: "m5.large", Count: 1}},
+				Count:            1,
+				ProvisionedCPUs:  2,
+				ProvisionedRAMGb: 8,
+			},
+			{
+				Name:             "arm-worker",
+				Architectures:    []string{"arm64"},
+				VMTypes:          []resource.VMType{{Name: "m6g.large", Count: 1}},
+				Count:            1,
+				ProvisionedCPUs:  2,
+				ProvisionedRAMGb: 8,
+			},
+			{
+				Name:             "cpu-worker-0",
+				Architectures:    []string{"amd64"},
+				VMTypes:          []resource.VMType{{Name: "m5.large", Count: 2}},
+				Count:            2,
+				ProvisionedCPUs:  4,
+				ProvisionedRAMGb: 16,
+			},
+			{
+				Name:             "cpu-worker-1",
+				Architectures:    []string{"amd64"},
+				VMTypes:          []resource.VMType{{Name: "m5.xlarge", Count: 1}},
+				Count:            1,
+				ProvisionedCPUs:  4,
+				ProvisionedRAMGb: 16,
+			},
+		},
+		Zones: []resource.NodeGroup{
+			{
+				Name:             "",
+				VMTypes:          []resource.VMType{{Name: "m5.large", Count: 1}},
+				Count:            1,
+				ProvisionedCPUs:  2,
+				ProvisionedRAMGb: 8,
+			},
+			{
+				Name:             "eu-central-1a",
+				Architectures:    []string{"amd64", "arm64"},
+				VMTypes:          []resource.VMType{{Name: "m5.large", Count: 1}, {Name: "m5.xlarge", Count: 1}, {Name: "m6g.large", Count: 1}},
+				Count:            3,
+				ProvisionedCPUs:  8,
+				ProvisionedRAMGb: 32,
+			},
+			{
+				Name:             "eu-central-1b",
+				Architectures:    []string{"amd64"},
+				VMTypes:          []resource.VMType{{Name: "m5.large", Count: 1}},
+				Count:            1,
+				ProvisionedCPUs:  2,
+				ProvisionedRAMGb: 8,
+			},
+		},
+	}, breakdown)
+
+	um, err := scan.UM(0)
+	require.ErrorIs(t, err, ErrUnknownVM)
+	require.Equal(t, &breakdown, um.Nodes)
+}
@@ -0,0 +1,60 @@
+package node
+
+import (
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kyma-project/kyma-metrics-collector/pkg/config"
+)
+
+// spotLabels are the labels marking nodes running on spot or preemptible capacity, with their lowercase values.
+var spotLabels = map[string][]string{
+	"cloud.google.com/gke-preemptible":      {"true"},
+	"cloud.google.com/gke-spot":             {"true"},
+	"eks.amazonaws.com/capacitytype":        {"spot"},
+	"karpenter.sh/capacity-type":            {"spot"},
+	"kubernetes.azure.com/scalesetpriority": {"spot"},
+	"node.kubernetes.io/lifecycle":          {"spot", "preemptible"},
+}
+
+// isSpot returns true if the node runs on spot or preemptible capacity, because of its own labels or because its
+// worker pool is a spot pool.
+func (s *Scan) isSpot(node *metav1.PartialObjectMetadata) bool {
+	if s.pools[node.Labels[workerPoolLabel]].Spot {
+		return true
+	}
+
+	for key, value := range node.Labels {
+		if isSpotLabel(key, value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isSpotLabel returns true if the label, or taint, marks spot or preemptible capacity.
+func isSpotLabel(key, value string) bool {
+	for _, spotValue := range spotLabels[strings.ToLower(key)] {
+		if strings.EqualFold(value, spotValue) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// billedCPUAndMemory returns the CPU and memory of the VM type of the node, reduced by the spot discount of the
+// runtime if the node runs on spot capacity, and the CPU and memory by which they were reduced.
+func (s *Scan) billedCPUAndMemory(node *metav1.PartialObjectMetadata, vmFeature *config.Feature) (cpu, memory, cpuDiscount, memoryDiscount float64) {
+	if !s.isSpot(node) {
+		return vmFeature.CpuCores, vmFeature.Memory, 0, 0
+	}
+
+	discount := s.specs.ResolveSpotDiscount(s.providerType, s.region, s.plan)
+	cpuDiscount = vmFeature.CpuCores * discount
+	memoryDiscount = vmFeature.Memory * discount
+
+	return vmFeature.CpuCores - cpuDiscount, vmFeature.Memory - memoryDiscount, cpuDiscount, memoryDiscount
+}
@@ -0,0 +1,135 @@
+package node
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kyma-project/kyma-metrics-collector/pkg/config"
+	"github.com/kyma-project/kyma-metrics-collector/pkg/resource"
+)
+
+func TestIsSpot(t *testing.T) {
+	tests := []struct {
+		name     string
+		labels   map[string]string
+		expected bool
+	}{
+		{name: "no labels", labels: nil, expected: false},
+		{name: "GKE preemptible", labels: map[string]string{"cloud.google.com/gke-preemptible": "true"}, expected: true},
+		{name: "GKE spot", labels: map[string]string{"cloud.google.com/gke-spot": "true"}, expected: true},
+		{name: "EKS spot", labels: map[string]string{"eks.amazonaws.com/capacityType": "SPOT"}, expected: true},
+		{name: "EKS on-demand", labels: map[string]string{"eks.amazonaws.com/capacityType": "ON_DEMAND"}, expected: false},
+		{name: "karpenter spot", labels: map[string]string{"karpenter.sh/capacity-type": "spot"}, expected: true},
+		{name: "Azure spot", labels: map[string]string{"kubernetes.azure.com/scalesetpriority": "spot"}, expected: true},
+		{name: "worker pool lifecycle", labels: map[string]string{"node.kubernetes.io/lifecycle": "preemptible"}, expected: true},
+		{name: "worker pool normal lifecycle", labels: map[string]string{"node.kubernetes.io/lifecycle": "normal"}, expected: false},
+		{name: "spot worker pool in Gardener", labels: map[string]string{workerPoolLabel: "spot-pool"}, expected: true},
+		{name: "on-demand worker pool in Gardener", labels: map[string]string{workerPoolLabel: "on-demand-pool"}, expected: false},
+	}
+
+	scan := &Scan{pools: map[string]WorkerPool{"spot-pool": {Spot: true}, "on-demand-pool": {}}}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, scan.isSpot(&metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Labels: tc.labels}}))
+		})
+	}
+}
+
+func TestScan_SpotNodes(t *testing.T) {
+	spotLabel := map[string]string{"karpenter.sh/capacity-type": "spot"}
+
+	scan := &Scan{
+		providerType: config.AWS,
+		specs: &config.PublicCloudSpecs{
+			Providers: config.Providers{
+				AWS: map[string]config.Feature{
+					"m5.large": {CpuCores: 2, Memory: 8},
+				},
+			},
+			SpotDiscounts: map[string]float64{config.AWS: 0.75},
+		},
+		list: metav1.PartialObjectMetadataList{
+			Items: []metav1.PartialObjectMetadata{
+				newTypedNode("on-demand", "m5.large", nil),
+				newTypedNode("spot-1", "m5.large", spotLabel),
+				newTypedNode("spot-2", "m5.large", spotLabel),
+			},
+		},
+		scannedAt: time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC),
+	}
+
+	edp, err := scan.EDP()
+	require.NoError(t, err)
+	require.InDelta(t, 3, edp.ProvisionedCPUs, 1e-9)
+	require.InDelta(t, 12, edp.ProvisionedRAMGb, 1e-9)
+	require.Equal(t, []resource.VMType{{Name: "m5.large", Count: 3}}, edp.VMTypes)
+	require.Equal(t, &resource.NodeCapacityTypes{OnDemandNodes: 1, SpotNodes: 2, SpotDiscountCPUs: 3, SpotDiscountRAMGb: 12}, edp.NodeCapacityTypes)
+
+	um, err := scan.UM(time.Hour)
+	require.NoError(t, err)
+	require.InDelta(t, 3, um.Usage.NodeHours, 1e-9)
+	require.InDelta(t, 3, um.Usage.CPUHours, 1e-9)
+	require.InDelta(t, 12, um.Usage.RAMGbHours, 1e-9)
+}
+
+func TestScan_SpotNodesWithoutDiscount(t *testing.T) {
+	scan := &Scan{
+		providerType: config.GCP,
+		specs: &config.PublicCloudSpecs{
+			Providers: config.Providers{
+				GCP: map[string]config.Feature{
+					"n2-standard-4": {CpuCores: 4, Memory: 16},
+				},
+			},
+			SpotDiscounts: map[string]float64{config.AWS: 0.75},
+		},
+		list: metav1.PartialObjectMetadataList{
+			Items: []metav1.PartialObjectMetadata{
+				newTypedNode("spot", "n2-standard-4", map[string]string{"cloud.google.com/gke-preemptible": "true"}),
+			},
+		},
+	}
+
+	edp, err := scan.EDP()
+	require.NoError(t, err)
+	require.InDelta(t, 4, edp.ProvisionedCPUs, 1e-9)
+	require.InDelta(t, 16, edp.ProvisionedRAMGb, 1e-9)
+	require.Equal(t, &resource.NodeCapacityTypes{SpotNodes: 1}, edp.NodeCapacityTypes)
+}
+
+func TestScan_SpotNodesWithOverride(t *testing.T) {
+	regionDiscount := 0.5
+
+	scan := &Scan{
+		providerType: config.AWS,
+		region:       "eu-central-1",
+		plan:         "aws",
+		specs: &config.PublicCloudSpecs{
+			Providers: config.Providers{
+				AWS: map[string]config.Feature{
+					"m5.large": {CpuCores: 2, Memory: 8},
+				},
+			},
+			SpotDiscounts: map[string]float64{config.AWS: 0.75},
+			Overrides: []config.Override{
+				{Provider: config.AWS, Region: "eu-central-1", SpotDiscount: &regionDiscount},
+			},
+		},
+		list: metav1.PartialObjectMetadataList{
+			Items: []metav1.PartialObjectMetadata{
+				newTypedNode("spot", "m5.large", map[string]string{workerPoolLabel: "spot-pool"}),
+			},
+		},
+		pools: map[string]WorkerPool{"spot-pool": {Spot: true}},
+	}
+
+	edp, err := scan.EDP()
+	require.NoError(t, err)
+	require.InDelta(t, 1, edp.ProvisionedCPUs, 1e-9)
+	require.InDelta(t, 4, edp.ProvisionedRAMGb, 1e-9)
+	require.Equal(t, &resource.NodeCapacityTypes{SpotNodes: 1, SpotDiscountCPUs: 1, SpotDiscountRAMGb: 4}, edp.NodeCapacityTypes)
+}
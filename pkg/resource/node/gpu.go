@@ -0,0 +1,92 @@
+package node
+
+import (
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kyma-project/kyma-metrics-collector/pkg/config"
+	"github.com/kyma-project/kyma-metrics-collector/pkg/resource"
+)
+
+const (
+	// gpuResourceName is the extended resource of the NVIDIA device plugin.
+	gpuResourceName corev1.ResourceName = "nvidia.com/gpu"
+	// gpuProductLabel is the GPU model set by the NVIDIA GPU feature discovery.
+	gpuProductLabel = "nvidia.com/gpu.product"
+	// unknownGPUType is the accelerator type of GPU nodes detected from their capacity without product label.
+	unknownGPUType = "nvidia.com/gpu"
+)
+
+// nodeGPU is the accelerators of a single node.
+type nodeGPU struct {
+	accelerator string
+	count       int
+	memoryGb    float64
+}
+
+// gpu returns the accelerators of the node. The accelerators of the VM type in the specs take precedence over the
+// GPU capacity of the node, which is only known if the policy detects GPUs from the capacity. The count is 0 for
+// nodes without GPUs.
+func (s *Scan) gpu(node *metav1.PartialObjectMetadata, vmFeature *config.Feature) nodeGPU {
+	if vmFeature.AcceleratorCount > 0 {
+		return nodeGPU{
+			accelerator: vmFeature.AcceleratorType,
+			count:       vmFeature.AcceleratorCount,
+			memoryGb:    float64(vmFeature.AcceleratorCount) * vmFeature.AcceleratorMemory,
+		}
+	}
+
+	capacity := s.gpuCapacity[node.Name]
+	if capacity == 0 {
+		return nodeGPU{}
+	}
+
+	accelerator := node.Labels[gpuProductLabel]
+	if accelerator == "" {
+		accelerator = unknownGPUType
+	}
+
+	// the memory of accelerators which are not in the specs is unknown
+	return nodeGPU{accelerator: accelerator, count: int(capacity)}
+}
+
+// gpuSummary collects the accelerators of the GPU nodes.
+type gpuSummary struct {
+	gpus  resource.ProvisionedGPUs
+	types map[string]int
+}
+
+func (g *gpuSummary) add(gpu nodeGPU) {
+	if gpu.count == 0 {
+		return
+	}
+
+	if g.types == nil {
+		g.types = make(map[string]int)
+	}
+
+	g.gpus.Nodes++
+	g.gpus.Count += gpu.count
+	g.gpus.MemoryGb += gpu.memoryGb
+	g.types[gpu.accelerator] += gpu.count
+}
+
+// provisioned returns the accelerators sorted by type, or nil if there are no GPU nodes.
+func (g *gpuSummary) provisioned() *resource.ProvisionedGPUs {
+	if g.gpus.Nodes == 0 {
+		return nil
+	}
+
+	gpus := g.gpus
+	for name, count := range g.types {
+		gpus.Types = append(gpus.Types, resource.GPUType{Name: name, Count: count})
+	}
+
+	sort.Slice(gpus.Types, func(i, j int) bool {
+		return gpus.Types[i].Name < gpus.Types[j].Name
+	})
+
+	return &gpus
+}
@@ -0,0 +1,127 @@
+package node
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	k8sresource "k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/kyma-project/kyma-metrics-collector/pkg/config"
+	"github.com/kyma-project/kyma-metrics-collector/pkg/resource"
+	"github.com/kyma-project/kyma-metrics-collector/pkg/runtime"
+	"github.com/kyma-project/kyma-metrics-collector/pkg/runtime/stubs"
+)
+
+func newGPUSpecs() *config.PublicCloudSpecs {
+	return &config.PublicCloudSpecs{
+		Providers: config.Providers{
+			AWS: map[string]config.Feature{
+				"m5.large":    {CpuCores: 2, Memory: 8},
+				"g4dn.xlarge": {CpuCores: 4, Memory: 16, AcceleratorType: "nvidia-t4", AcceleratorCount: 1, AcceleratorMemory: 16},
+				"p3.8xlarge":  {CpuCores: 32, Memory: 244, AcceleratorType: "nvidia-v100", AcceleratorCount: 4, AcceleratorMemory: 16},
+			},
+		},
+	}
+}
+
+func newTypedNode(name, vmType string, labels map[string]string) metav1.PartialObjectMetadata {
+	nodeLabels := map[string]string{nodeInstanceTypeLabel: vmType}
+	for key, value := range labels {
+		nodeLabels[key] = value
+	}
+
+	return metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Name: name, UID: types.UID("uid-" + name), Labels: nodeLabels}}
+}
+
+func TestScan_GPUs(t *testing.T) {
+	scan := &Scan{
+		providerType: config.AWS,
+		specs:        newGPUSpecs(),
+		list: metav1.PartialObjectMetadataList{
+			Items: []metav1.PartialObjectMetadata{
+				newTypedNode("cpu", "m5.large", nil),
+				newTypedNode("t4-1", "g4dn.xlarge", nil),
+				newTypedNode("t4-2", "g4dn.xlarge", nil),
+				newTypedNode("v100", "p3.8xlarge", nil),
+				newTypedNode("a100", "m5.large", map[string]string{gpuProductLabel: "NVIDIA-A100-SXM4-40GB"}),
+				newTypedNode("unlabeled", "m5.large", nil),
+			},
+		},
+		gpuCapacity: map[string]int64{
+			"a100":      2,
+			"unlabeled": 1,
+			// the accelerators of the VM type in the specs take precedence
+			"v100": 8,
+		},
+		scannedAt: time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC),
+	}
+
+	expectedGPUs := &resource.ProvisionedGPUs{
+		Nodes:    5,
+		Count:    9,
+		MemoryGb: 96,
+		Types: []resource.GPUType{
+			{Name: "NVIDIA-A100-SXM4-40GB", Count: 2},
+			{Name: "nvidia-t4", Count: 2},
+			{Name: "nvidia-v100", Count: 4},
+			{Name: unknownGPUType, Count: 1},
+		},
+	}
+
+	edp, err := scan.EDP()
+	require.NoError(t, err)
+	require.Equal(t, expectedGPUs, edp.ProvisionedGPUs)
+
+	um, err := scan.UM(time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, expectedGPUs, um.GPUs)
+	require.InDelta(t, 9, um.Usage.GPUHours, 1e-9)
+}
+
+func TestScan_GPUs_NoGPUNodes(t *testing.T) {
+	scan := &Scan{
+		providerType: config.AWS,
+		specs:        newGPUSpecs(),
+		list: metav1.PartialObjectMetadataList{
+			Items: []metav1.PartialObjectMetadata{newTypedNode("cpu", "m5.large", nil)},
+		},
+	}
+
+	edp, err := scan.EDP()
+	require.NoError(t, err)
+	require.Nil(t, edp.ProvisionedGPUs)
+
+	um, err := scan.UM(time.Hour)
+	require.NoError(t, err)
+	require.Nil(t, um.GPUs)
+	require.Zero(t, um.Usage.GPUHours)
+}
+
+func TestScanner_Scan_DetectGPUCapacity(t *testing.T) {
+	clients := stubs.Clients{
+		KubernetesInterface: k8sfake.NewClientset(
+			&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "cpu"}},
+			&corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: "gpu"},
+				Status: corev1.NodeStatus{Capacity: corev1.ResourceList{
+					gpuResourceName: k8sresource.MustParse("2"),
+				}},
+			},
+		),
+	}
+
+	scanner := NewScanner(&config.PublicCloudSpecs{}, Policy{DetectGPUCapacity: true}, nil)
+
+	result, err := scanner.Scan(t.Context(), &runtime.Info{}, clients)
+	require.NoError(t, err)
+
+	nodeScan, ok := result.(*Scan)
+	require.True(t, ok)
+	require.Len(t, nodeScan.list.Items, 2)
+	require.Equal(t, map[string]int64{"gpu": 2}, nodeScan.gpuCapacity)
+}
@@ -0,0 +1,46 @@
+package node
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/kyma-project/kyma-metrics-collector/pkg/runtime"
+)
+
+const (
+	namespace          = "kmc"
+	subsystem          = "node"
+	reasonLabel        = "reason"
+	shootNameLabel     = "shoot_name"
+	instanceIdLabel    = "instance_id"
+	runtimeIdLabel     = "runtime_id"
+	subAccountLabel    = "sub_account_id"
+	globalAccountLabel = "global_account_id"
+)
+
+// ExcludedNodes is exported so the series of a runtime can be deleted once it is not tracked anymore.
+var ExcludedNodes = promauto.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "excluded",
+		Help:      "Number of nodes excluded from billing in the last scan of the runtime per reason.",
+	},
+	[]string{reasonLabel, shootNameLabel, instanceIdLabel, runtimeIdLabel, subAccountLabel, globalAccountLabel},
+)
+
+// recordExcludedNodes sets the number of excluded nodes of the runtime for every reason, so reasons without
+// excluded nodes in the scan are reset to 0.
+func recordExcludedNodes(runtimeInfo runtime.Info, excluded map[string]int) {
+	for _, reason := range []string{ExclusionReasonDeleting, ExclusionReasonAutoscalerDeletion, ExclusionReasonNotReady} {
+		// the order of the values should be same as defined in the metric declaration.
+		ExcludedNodes.WithLabelValues(
+			reason,
+			runtimeInfo.ShootName,
+			runtimeInfo.InstanceID,
+			runtimeInfo.RuntimeID,
+			runtimeInfo.SubAccountID,
+			runtimeInfo.GlobalAccountID,
+		).Set(float64(excluded[reason]))
+	}
+}
@@ -0,0 +1,67 @@
+package node
+
+import (
+	"slices"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// toBeDeletedTaint is the taint of the cluster autoscaler on nodes which it is about to delete.
+const toBeDeletedTaint = "ToBeDeletedByClusterAutoscaler"
+
+// exclusion reasons of nodes which are not billed
+const (
+	ExclusionReasonDeleting           = "deleting"
+	ExclusionReasonAutoscalerDeletion = "autoscaler_deletion"
+	ExclusionReasonNotReady           = "not_ready"
+)
+
+// Policy decides which nodes are excluded from billing, how GPU nodes are detected, and whether node volumes are
+// billed. The zero value bills all nodes without their volumes and detects GPU nodes from the VM types in the specs only.
+type Policy struct {
+	// ExcludeDeleting excludes nodes which are being deleted or are tainted for deletion by the cluster autoscaler.
+	ExcludeDeleting bool
+	// NotReadyThreshold excludes nodes which are not ready for longer than the threshold. Zero disables the exclusion.
+	NotReadyThreshold time.Duration
+	// DetectGPUCapacity detects GPU nodes from their nvidia.com/gpu capacity if their VM type has no accelerators in the specs.
+	DetectGPUCapacity bool
+	// VolumeProviders are the providers whose runtimes are billed for the root volumes of their nodes.
+	VolumeProviders []string
+}
+
+// billsVolumes returns true if the root volumes of the nodes of the provider are billed.
+func (p Policy) billsVolumes(provider string) bool {
+	return slices.Contains(p.VolumeProviders, provider)
+}
+
+// inspectsNodes returns true if the policy needs the spec and status of the nodes, not only their metadata.
+func (p Policy) inspectsNodes() bool {
+	return p.ExcludeDeleting || p.NotReadyThreshold > 0 || p.DetectGPUCapacity
+}
+
+// exclusionReason returns why the node is excluded from billing at the given time, or false if it is billed.
+func (p Policy) exclusionReason(node *corev1.Node, now time.Time) (string, bool) {
+	if p.ExcludeDeleting {
+		if node.DeletionTimestamp != nil {
+			return ExclusionReasonDeleting, true
+		}
+
+		for _, taint := range node.Spec.Taints {
+			if taint.Key == toBeDeletedTaint {
+				return ExclusionReasonAutoscalerDeletion, true
+			}
+		}
+	}
+
+	if p.NotReadyThreshold > 0 {
+		for _, condition := range node.Status.Conditions {
+			if condition.Type == corev1.NodeReady && condition.Status != corev1.ConditionTrue &&
+				now.Sub(condition.LastTransitionTime.Time) > p.NotReadyThreshold {
+				return ExclusionReasonNotReady, true
+			}
+		}
+	}
+
+	return "", false
+}
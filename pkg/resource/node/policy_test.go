@@ -0,0 +1,87 @@
+package node
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPolicy_ExclusionReason(t *testing.T) {
+	now := time.Now()
+
+	readyCondition := func(status corev1.ConditionStatus, since time.Time) corev1.NodeStatus {
+		return corev1.NodeStatus{Conditions: []corev1.NodeCondition{
+			{Type: corev1.NodeReady, Status: status, LastTransitionTime: metav1.NewTime(since)},
+		}}
+	}
+
+	policy := Policy{ExcludeDeleting: true, NotReadyThreshold: 10 * time.Minute}
+
+	tests := []struct {
+		name           string
+		policy         Policy
+		node           corev1.Node
+		expectedReason string
+		expectedFound  bool
+	}{
+		{
+			name:   "ready node",
+			policy: policy,
+			node:   corev1.Node{Status: readyCondition(corev1.ConditionTrue, now.Add(-time.Hour))},
+		},
+		{
+			name:           "deleting node",
+			policy:         policy,
+			node:           corev1.Node{ObjectMeta: metav1.ObjectMeta{DeletionTimestamp: &metav1.Time{Time: now}}},
+			expectedReason: ExclusionReasonDeleting,
+			expectedFound:  true,
+		},
+		{
+			name:   "deleting node without policy",
+			policy: Policy{},
+			node:   corev1.Node{ObjectMeta: metav1.ObjectMeta{DeletionTimestamp: &metav1.Time{Time: now}}},
+		},
+		{
+			name:           "node tainted by the cluster autoscaler",
+			policy:         policy,
+			node:           corev1.Node{Spec: corev1.NodeSpec{Taints: []corev1.Taint{{Key: toBeDeletedTaint}}}},
+			expectedReason: ExclusionReasonAutoscalerDeletion,
+			expectedFound:  true,
+		},
+		{
+			name:   "node not ready shorter than the threshold",
+			policy: policy,
+			node:   corev1.Node{Status: readyCondition(corev1.ConditionFalse, now.Add(-time.Minute))},
+		},
+		{
+			name:           "node not ready longer than the threshold",
+			policy:         policy,
+			node:           corev1.Node{Status: readyCondition(corev1.ConditionFalse, now.Add(-time.Hour))},
+			expectedReason: ExclusionReasonNotReady,
+			expectedFound:  true,
+		},
+		{
+			name:           "node unknown longer than the threshold",
+			policy:         policy,
+			node:           corev1.Node{Status: readyCondition(corev1.ConditionUnknown, now.Add(-time.Hour))},
+			expectedReason: ExclusionReasonNotReady,
+			expectedFound:  true,
+		},
+		{
+			name:   "node not ready without threshold",
+			policy: Policy{ExcludeDeleting: true},
+			node:   corev1.Node{Status: readyCondition(corev1.ConditionFalse, now.Add(-time.Hour))},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			reason, found := tc.policy.exclusionReason(&tc.node, now)
+			require.Equal(t, tc.expectedFound, found)
+			require.Equal(t, tc.expectedReason, reason)
+		})
+	}
+}
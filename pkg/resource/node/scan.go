@@ -16,19 +16,86 @@ const nodeInstanceTypeLabel = "node.kubernetes.io/instance-type"
 
 var ErrUnknownVM = errors.New("unknown provider and node type combination")
 
-var _ resource.ScanConverter = &Scan{}
+var (
+	_ resource.ScanConverter   = &Scan{}
+	_ resource.SpecsVersioned  = &Scan{}
+	_ resource.TimeWeighted    = &Scan{}
+	_ resource.NodeBreakdowner = &Scan{}
+)
 
 type Scan struct {
 	providerType string
+	region       string
+	plan         string
 	specs        *config.PublicCloudSpecs
 
 	list v1.PartialObjectMetadataList
+	// gpuCapacity is the nvidia.com/gpu capacity of the GPU nodes by name, if the policy detects GPUs from the capacity.
+	gpuCapacity map[string]int64
+	// billVolumes is true if the root volumes of the nodes are billed.
+	billVolumes bool
+	// pools are the worker pools of the runtime by name, if they are read from Gardener.
+	pools     map[string]WorkerPool
+	scannedAt time.Time
+}
+
+func (s *Scan) SpecsVersion() string {
+	return s.specs.Version
 }
 
 func (s *Scan) UM(duration time.Duration) (resource.UMMeasurement, error) {
-	return resource.UMMeasurement{}, nil
+	rates, err := s.Rates()
+	// the nodes of unknown VM types are skipped and reported by Rates as well
+	breakdown, _ := s.NodeBreakdown()
+
+	return resource.UMMeasurement{
+		Usage: resource.Integrate(nil, rates, s.scannedAt.Add(-duration), s.scannedAt),
+		Nodes: &breakdown,
+		GPUs:  s.gpus(),
+	}, err
+}
+
+func (s *Scan) ScannedAt() time.Time {
+	return s.scannedAt
 }
 
+// Rates returns a node hour and the CPU, memory and accelerators of its VM type per hour for every node, and the size
+// of its root volume if node volumes are billed. The CPU and memory of spot nodes are discounted.
+func (s *Scan) Rates() ([]resource.Rate, error) {
+	rates := make([]resource.Rate, 0, len(s.list.Items))
+
+	var errs []error
+
+	for _, node := range s.list.Items {
+		nodeType := strings.ToLower(node.Labels[nodeInstanceTypeLabel])
+
+		vmFeature := s.specs.ResolveFeature(s.providerType, s.region, s.plan, nodeType)
+		if vmFeature == nil {
+			errs = append(errs, fmt.Errorf("%w: provider: %s, node: %s", ErrUnknownVM, s.providerType, nodeType))
+			continue
+		}
+
+		cpu, memory, _, _ := s.billedCPUAndMemory(&node, vmFeature)
+
+		rates = append(rates, resource.Rate{
+			Key:       resource.ObjectKey(&node),
+			CreatedAt: node.CreationTimestamp.Time,
+			PerHour: resource.Usage{
+				NodeHours:      1,
+				CPUHours:       cpu,
+				RAMGbHours:     memory,
+				GPUHours:       float64(s.gpu(&node, vmFeature).count),
+				StorageGbHours: float64(s.volumeSizeInGB(&node, vmFeature)),
+			},
+		})
+	}
+
+	return rates, errors.Join(errs...)
+}
+
+// EDP returns the CPU and memory of the VM types of the nodes, discounting spot nodes, and the root volumes of the
+// nodes if node volumes are billed. The number of spot and on-demand nodes and the discounted CPU and memory are only
+// added for runtimes with spot nodes, as the VM types count the spot nodes with the undiscounted CPU and memory.
 func (s *Scan) EDP() (resource.EDPMeasurement, error) {
 	edp := resource.EDPMeasurement{}
 
@@ -36,19 +103,46 @@ func (s *Scan) EDP() (resource.EDPMeasurement, error) {
 
 	vmTypes := make(map[string]int)
 
+	var gpus gpuSummary
+
+	var capacityTypes resource.NodeCapacityTypes
+
 	for _, node := range s.list.Items {
 		nodeType := node.Labels[nodeInstanceTypeLabel]
 		nodeType = strings.ToLower(nodeType)
 
-		vmFeature := s.specs.GetFeature(s.providerType, nodeType)
+		vmFeature := s.specs.ResolveFeature(s.providerType, s.region, s.plan, nodeType)
 		if vmFeature == nil {
 			errs = append(errs, fmt.Errorf("%w: provider: %s, node: %s", ErrUnknownVM, s.providerType, nodeType))
 			continue
 		}
 
-		edp.ProvisionedCPUs += vmFeature.CpuCores
-		edp.ProvisionedRAMGb += vmFeature.Memory
+		cpu, memory, cpuDiscount, memoryDiscount := s.billedCPUAndMemory(&node, vmFeature)
+		edp.ProvisionedCPUs += cpu
+		edp.ProvisionedRAMGb += memory
 		vmTypes[nodeType] += 1
+
+		gpus.add(s.gpu(&node, vmFeature))
+
+		if size := s.volumeSizeInGB(&node, vmFeature); size > 0 {
+			edp.ProvisionedVolumes.SizeGbTotal += size
+			edp.ProvisionedVolumes.SizeGbRounded += resource.GetVolumeRoundedToFactor(size)
+			edp.ProvisionedVolumes.Count += 1
+		}
+
+		if s.isSpot(&node) {
+			capacityTypes.SpotNodes++
+			capacityTypes.SpotDiscountCPUs += cpuDiscount
+			capacityTypes.SpotDiscountRAMGb += memoryDiscount
+		} else {
+			capacityTypes.OnDemandNodes++
+		}
+	}
+
+	edp.ProvisionedGPUs = gpus.provisioned()
+
+	if capacityTypes.SpotNodes > 0 {
+		edp.NodeCapacityTypes = &capacityTypes
 	}
 
 	for vmType, count := range vmTypes {
@@ -60,3 +154,17 @@ func (s *Scan) EDP() (resource.EDPMeasurement, error) {
 
 	return edp, errors.Join(errs...)
 }
+
+// gpus returns the accelerators of the nodes of known VM types, or nil if there are no GPU nodes.
+func (s *Scan) gpus() *resource.ProvisionedGPUs {
+	var gpus gpuSummary
+
+	for _, node := range s.list.Items {
+		vmFeature := s.specs.ResolveFeature(s.providerType, s.region, s.plan, strings.ToLower(node.Labels[nodeInstanceTypeLabel]))
+		if vmFeature != nil {
+			gpus.add(s.gpu(&node, vmFeature))
+		}
+	}
+
+	return gpus.provisioned()
+}
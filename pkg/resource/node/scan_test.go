@@ -2,6 +2,7 @@ package node
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -210,3 +211,88 @@ func TestScan_EDP(t *testing.T) {
 		})
 	}
 }
+
+func TestScan_EDP_WithOverride(t *testing.T) {
+	specs := &config.PublicCloudSpecs{
+		Providers: config.Providers{
+			AWS: map[string]config.Feature{
+				"m5.large": {CpuCores: 2, Memory: 8},
+			},
+		},
+		Overrides: []config.Override{
+			{
+				Provider: config.AWS,
+				Region:   "eu-central-1",
+				VMTypes: map[string]config.Feature{
+					"m5.large": {CpuCores: 2.5, Memory: 10},
+				},
+			},
+		},
+	}
+
+	list := metav1.PartialObjectMetadataList{
+		Items: []metav1.PartialObjectMetadata{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"node.kubernetes.io/instance-type": "m5.large"},
+				},
+			},
+		},
+	}
+
+	scan := &Scan{
+		providerType: config.AWS,
+		region:       "eu-central-1",
+		plan:         "aws",
+		specs:        specs,
+		list:         list,
+	}
+
+	actualEDP, err := scan.EDP()
+	require.NoError(t, err)
+	require.InDelta(t, 2.5, actualEDP.ProvisionedCPUs, kmctesting.Delta)
+	require.InDelta(t, 10, actualEDP.ProvisionedRAMGb, kmctesting.Delta)
+
+	scan.region = "us-east-1"
+
+	actualEDP, err = scan.EDP()
+	require.NoError(t, err)
+	require.InDelta(t, 2, actualEDP.ProvisionedCPUs, kmctesting.Delta)
+	require.InDelta(t, 8, actualEDP.ProvisionedRAMGb, kmctesting.Delta)
+}
+
+func TestScan_UM_ProratedFromCreation(t *testing.T) {
+	scannedAt := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	scan := &Scan{
+		providerType: config.AWS,
+		specs: &config.PublicCloudSpecs{
+			Providers: config.Providers{
+				AWS: map[string]config.Feature{
+					"m5.large": {CpuCores: 2, Memory: 8},
+				},
+			},
+		},
+		list: metav1.PartialObjectMetadataList{
+			Items: []metav1.PartialObjectMetadata{
+				{ObjectMeta: metav1.ObjectMeta{
+					Name:              "old",
+					UID:               "old",
+					CreationTimestamp: metav1.NewTime(scannedAt.Add(-24 * time.Hour)),
+					Labels:            map[string]string{nodeInstanceTypeLabel: "m5.large"},
+				}},
+				{ObjectMeta: metav1.ObjectMeta{
+					Name:              "new",
+					UID:               "new",
+					CreationTimestamp: metav1.NewTime(scannedAt.Add(-15 * time.Minute)),
+					Labels:            map[string]string{nodeInstanceTypeLabel: "m5.large"},
+				}},
+			},
+		},
+		scannedAt: scannedAt,
+	}
+
+	um, err := scan.UM(time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, resource.Usage{NodeHours: 1.25, CPUHours: 2.5, RAMGbHours: 10}, um.Usage)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/codes"
@@ -21,12 +22,18 @@ var _ resource.Scanner = &Scanner{}
 var ErrNoNodesFound = errors.New("no nodes found")
 
 type Scanner struct {
-	specs *config.PublicCloudSpecs
+	specs       *config.PublicCloudSpecs
+	policy      Policy
+	workerPools WorkerPools
 }
 
-func NewScanner(specs *config.PublicCloudSpecs) *Scanner {
+// NewScanner creates a Scanner billing the nodes according to the policy. The optional workerPools provide the
+// volume sizes of the worker pools, which take precedence over the volume sizes in the specs, and the spot pools.
+func NewScanner(specs *config.PublicCloudSpecs, policy Policy, workerPools WorkerPools) *Scanner {
 	return &Scanner{
-		specs: specs,
+		specs:       specs,
+		policy:      policy,
+		workerPools: workerPools,
 	}
 }
 
@@ -38,7 +45,9 @@ func (s *Scanner) Scan(ctx context.Context, runtime *runtime.Info, clients runti
 	ctx, span := otel.Tracer("").Start(ctx, "node_scan", kmcotel.SpanAttributes(runtime))
 	defer span.End()
 
-	list, err := clients.Metadata().Resource(schema.GroupVersionResource{Group: "", Version: "v1", Resource: "nodes"}).List(ctx, metav1.ListOptions{})
+	now := time.Now()
+
+	list, gpuCapacity, err := s.list(ctx, runtime, clients, now)
 	if err != nil {
 		retErr := fmt.Errorf("failed to list list: %w", err)
 		span.RecordError(err)
@@ -48,13 +57,94 @@ func (s *Scanner) Scan(ctx context.Context, runtime *runtime.Info, clients runti
 	}
 
 	// a cluster with no nodes is not a valid cluster
-	if len(list.Items) == 0 {
+	if list == nil {
 		return nil, ErrNoNodesFound
 	}
 
+	var pools map[string]WorkerPool
+
+	if s.workerPools != nil {
+		pools, err = s.workerPools.WorkerPools(ctx, runtime)
+		if err != nil {
+			retErr := fmt.Errorf("failed to get worker pools: %w", err)
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+
+			return nil, retErr
+		}
+	}
+
 	return &Scan{
 		providerType: runtime.ProviderType,
-		specs:        s.specs,
+		region:       runtime.Region,
+		plan:         runtime.PlanName,
+		specs:        s.specs.At(now),
 		list:         *list,
+		gpuCapacity:  gpuCapacity,
+		billVolumes:  s.policy.billsVolumes(runtime.ProviderType),
+		pools:        pools,
+		scannedAt:    now,
 	}, nil
 }
+
+// list returns the metadata of the nodes to bill, or nil if the cluster has no nodes. The nodes are only fetched
+// completely if the policy needs their spec and status, to exclude nodes or to detect GPUs from their capacity.
+// In that case, the GPU capacity of the nodes with GPUs is returned by node name, and the excluded nodes are recorded.
+func (s *Scanner) list(ctx context.Context, runtimeInfo *runtime.Info, clients runtime.Interface, now time.Time) (*metav1.PartialObjectMetadataList, map[string]int64, error) {
+	if !s.policy.inspectsNodes() {
+		list, err := clients.Metadata().Resource(schema.GroupVersionResource{Group: "", Version: "v1", Resource: "nodes"}).List(ctx, metav1.ListOptions{})
+		if err != nil || len(list.Items) == 0 {
+			return nil, nil, err
+		}
+
+		return list, nil, nil
+	}
+
+	nodes, err := clients.K8s().CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	excludedNodes := make(map[string]int)
+	defer recordExcludedNodes(*runtimeInfo, excludedNodes)
+
+	if len(nodes.Items) == 0 {
+		return nil, nil, nil
+	}
+
+	var gpuCapacity map[string]int64
+
+	list := &metav1.PartialObjectMetadataList{
+		TypeMeta: nodes.TypeMeta,
+		ListMeta: nodes.ListMeta,
+		Items:    make([]metav1.PartialObjectMetadata, 0, len(nodes.Items)),
+	}
+
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+
+		if reason, excluded := s.policy.exclusionReason(node, now); excluded {
+			excludedNodes[reason]++
+			continue
+		}
+
+		list.Items = append(list.Items, metav1.PartialObjectMetadata{
+			TypeMeta:   node.TypeMeta,
+			ObjectMeta: node.ObjectMeta,
+		})
+
+		if !s.policy.DetectGPUCapacity {
+			continue
+		}
+
+		if capacity, found := node.Status.Capacity[gpuResourceName]; found && capacity.Value() > 0 {
+			if gpuCapacity == nil {
+				gpuCapacity = make(map[string]int64)
+			}
+
+			gpuCapacity[node.Name] = capacity.Value()
+		}
+	}
+
+	return list, gpuCapacity, nil
+}
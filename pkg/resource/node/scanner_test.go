@@ -3,11 +3,14 @@ package node
 import (
 	"errors"
 	"testing"
+	"time"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/metadata/fake"
 	k8stesting "k8s.io/client-go/testing"
 
@@ -48,6 +51,8 @@ func TestScanner_Scan_Successful(t *testing.T) {
 
 	result, err := scanner.Scan(t.Context(), &runtime.Info{
 		ProviderType: provider,
+		Region:       "eu-central-1",
+		PlanName:     "aws",
 	}, clients)
 	require.NoError(t, err)
 	require.NotNil(t, result)
@@ -55,10 +60,43 @@ func TestScanner_Scan_Successful(t *testing.T) {
 	nodeScan, ok := result.(*Scan)
 	require.True(t, ok)
 	require.Equal(t, provider, nodeScan.providerType)
+	require.Equal(t, "eu-central-1", nodeScan.region)
+	require.Equal(t, "aws", nodeScan.plan)
 	require.Equal(t, nodes.Items, nodeScan.list.Items)
 	require.Equal(t, scanner.specs, nodeScan.specs)
 }
 
+func TestScanner_Scan_VersionedSpecs(t *testing.T) {
+	nodes := &metav1.PartialObjectMetadataList{
+		Items: []metav1.PartialObjectMetadata{
+			{ObjectMeta: metav1.ObjectMeta{Name: "node1"}, TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Node"}},
+		},
+	}
+
+	scheme := fake.NewTestScheme()
+	scheme.AddKnownTypes(corev1.SchemeGroupVersion, &metav1.PartialObjectMetadata{}, &metav1.PartialObjectMetadataList{})
+
+	clients := stubs.Clients{
+		MetadataInterface: fake.NewSimpleMetadataClient(scheme, nodes),
+	}
+
+	scanner := Scanner{
+		specs: &config.PublicCloudSpecs{
+			Versions: []config.PublicCloudSpecs{
+				{Version: "past", EffectiveFrom: time.Now().Add(-time.Hour)},
+				{Version: "future", EffectiveFrom: time.Now().Add(time.Hour)},
+			},
+		},
+	}
+
+	result, err := scanner.Scan(t.Context(), &runtime.Info{}, clients)
+	require.NoError(t, err)
+
+	nodeScan, ok := result.(*Scan)
+	require.True(t, ok)
+	require.Equal(t, "past", nodeScan.SpecsVersion())
+}
+
 func TestScanner_Scan_Error(t *testing.T) {
 	scheme := fake.NewTestScheme()
 	scheme.AddKnownTypes(corev1.SchemeGroupVersion, &corev1.Node{}, &corev1.NodeList{})
@@ -79,3 +117,93 @@ func TestScanner_Scan_Error(t *testing.T) {
 	require.Error(t, err)
 	require.Nil(t, result)
 }
+
+func TestScanner_Scan_Policy(t *testing.T) {
+	now := time.Now()
+
+	notReady := func(since time.Time) corev1.NodeStatus {
+		return corev1.NodeStatus{Conditions: []corev1.NodeCondition{
+			{Type: corev1.NodeReady, Status: corev1.ConditionFalse, LastTransitionTime: metav1.NewTime(since)},
+		}}
+	}
+
+	clients := stubs.Clients{
+		KubernetesInterface: k8sfake.NewClientset(
+			&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "ready"}},
+			&corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: "deleting", DeletionTimestamp: &metav1.Time{Time: now}, Finalizers: []string{"test"}},
+			},
+			&corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: "scaled-down"},
+				Spec:       corev1.NodeSpec{Taints: []corev1.Taint{{Key: toBeDeletedTaint, Effect: corev1.TaintEffectNoSchedule}}},
+			},
+			&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "not-ready-briefly"}, Status: notReady(now.Add(-time.Minute))},
+			&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "not-ready"}, Status: notReady(now.Add(-time.Hour))},
+		),
+	}
+
+	tests := []struct {
+		name             string
+		policy           Policy
+		expectedNodes    []string
+		expectedExcluded map[string]float64
+	}{
+		{
+			name:             "exclude deleting nodes",
+			policy:           Policy{ExcludeDeleting: true},
+			expectedNodes:    []string{"not-ready", "not-ready-briefly", "ready"},
+			expectedExcluded: map[string]float64{ExclusionReasonDeleting: 1, ExclusionReasonAutoscalerDeletion: 1},
+		},
+		{
+			name:             "exclude not ready nodes",
+			policy:           Policy{NotReadyThreshold: 10 * time.Minute},
+			expectedNodes:    []string{"deleting", "not-ready-briefly", "ready", "scaled-down"},
+			expectedExcluded: map[string]float64{ExclusionReasonNotReady: 1},
+		},
+		{
+			name:             "exclude deleting and not ready nodes",
+			policy:           Policy{ExcludeDeleting: true, NotReadyThreshold: 10 * time.Minute},
+			expectedNodes:    []string{"not-ready-briefly", "ready"},
+			expectedExcluded: map[string]float64{ExclusionReasonDeleting: 1, ExclusionReasonAutoscalerDeletion: 1, ExclusionReasonNotReady: 1},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			scanner := NewScanner(&config.PublicCloudSpecs{}, tc.policy, nil)
+
+			runtimeInfo := runtime.Info{RuntimeID: "runtime-id", SubAccountID: "subaccount-id", ShootName: "shoot"}
+
+			result, err := scanner.Scan(t.Context(), &runtimeInfo, clients)
+			require.NoError(t, err)
+
+			nodeScan, ok := result.(*Scan)
+			require.True(t, ok)
+
+			var names []string
+			for _, node := range nodeScan.list.Items {
+				names = append(names, node.Name)
+			}
+
+			require.ElementsMatch(t, tc.expectedNodes, names)
+
+			// the excluded nodes of the last scan are recorded per runtime, and the other reasons are reset
+			for _, reason := range []string{ExclusionReasonDeleting, ExclusionReasonAutoscalerDeletion, ExclusionReasonNotReady} {
+				gauge := ExcludedNodes.WithLabelValues(reason, runtimeInfo.ShootName, "", runtimeInfo.RuntimeID, runtimeInfo.SubAccountID, "")
+				require.InDelta(t, tc.expectedExcluded[reason], testutil.ToFloat64(gauge), 0)
+			}
+		})
+	}
+}
+
+func TestScanner_Scan_PolicyNoNodes(t *testing.T) {
+	clients := stubs.Clients{
+		KubernetesInterface: k8sfake.NewClientset(),
+	}
+
+	scanner := NewScanner(&config.PublicCloudSpecs{}, Policy{ExcludeDeleting: true}, nil)
+
+	result, err := scanner.Scan(t.Context(), &runtime.Info{}, clients)
+	require.ErrorIs(t, err, ErrNoNodesFound)
+	require.Nil(t, result)
+}
@@ -0,0 +1,21 @@
+package node
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kyma-project/kyma-metrics-collector/pkg/config"
+)
+
+// volumeSizeInGB returns the size of the root volume of the node, or 0 if the node volumes are not billed.
+// The volume size of the worker pool in Gardener takes precedence over the volume size of the VM type in the specs.
+func (s *Scan) volumeSizeInGB(node *metav1.PartialObjectMetadata, vmFeature *config.Feature) int64 {
+	if !s.billVolumes {
+		return 0
+	}
+
+	if size := s.pools[node.Labels[workerPoolLabel]].VolumeSizeGb; size > 0 {
+		return size
+	}
+
+	return vmFeature.VolumeSizeGb
+}
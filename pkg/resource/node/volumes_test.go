@@ -0,0 +1,119 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/metadata/fake"
+
+	"github.com/kyma-project/kyma-metrics-collector/pkg/config"
+	"github.com/kyma-project/kyma-metrics-collector/pkg/resource"
+	"github.com/kyma-project/kyma-metrics-collector/pkg/runtime"
+	"github.com/kyma-project/kyma-metrics-collector/pkg/runtime/stubs"
+)
+
+// workerPoolsFunc provides the worker pools of a function.
+type workerPoolsFunc func(ctx context.Context, runtime *runtime.Info) (map[string]WorkerPool, error)
+
+func (f workerPoolsFunc) WorkerPools(ctx context.Context, runtime *runtime.Info) (map[string]WorkerPool, error) {
+	return f(ctx, runtime)
+}
+
+func TestScan_NodeVolumes(t *testing.T) {
+	specs := &config.PublicCloudSpecs{
+		Providers: config.Providers{
+			AWS: map[string]config.Feature{
+				"m5.large":  {CpuCores: 2, Memory: 8, VolumeSizeGb: 50},
+				"m5.xlarge": {CpuCores: 4, Memory: 16},
+			},
+		},
+	}
+
+	list := metav1.PartialObjectMetadataList{
+		Items: []metav1.PartialObjectMetadata{
+			newTypedNode("node-1", "m5.large", map[string]string{workerPoolLabel: "cpu-worker-0"}),
+			newTypedNode("node-2", "m5.large", map[string]string{workerPoolLabel: "cpu-worker-1"}),
+			newTypedNode("node-3", "m5.xlarge", map[string]string{workerPoolLabel: "cpu-worker-2"}),
+		},
+	}
+
+	t.Run("volumes of worker pools and specs", func(t *testing.T) {
+		scan := &Scan{
+			providerType: config.AWS,
+			specs:        specs,
+			list:         list,
+			billVolumes:  true,
+			pools:        map[string]WorkerPool{"cpu-worker-0": {VolumeSizeGb: 80}},
+			scannedAt:    time.Now(),
+		}
+
+		edp, err := scan.EDP()
+		require.NoError(t, err)
+		require.Equal(t, resource.ProvisionedVolumes{SizeGbTotal: 130, Count: 2, SizeGbRounded: 160}, edp.ProvisionedVolumes)
+
+		um, err := scan.UM(time.Hour)
+		require.NoError(t, err)
+		require.InDelta(t, 130, um.Usage.StorageGbHours, 1e-9)
+	})
+
+	t.Run("volumes are not billed", func(t *testing.T) {
+		scan := &Scan{
+			providerType: config.AWS,
+			specs:        specs,
+			list:         list,
+			pools:        map[string]WorkerPool{"cpu-worker-0": {VolumeSizeGb: 80}},
+		}
+
+		edp, err := scan.EDP()
+		require.NoError(t, err)
+		require.Equal(t, resource.ProvisionedVolumes{}, edp.ProvisionedVolumes)
+	})
+}
+
+func TestScanner_Scan_NodeVolumes(t *testing.T) {
+	nodes := &metav1.PartialObjectMetadataList{
+		Items: []metav1.PartialObjectMetadata{
+			{ObjectMeta: metav1.ObjectMeta{Name: "node1"}, TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Node"}},
+		},
+	}
+
+	newClients := func() stubs.Clients {
+		scheme := fake.NewTestScheme()
+		scheme.AddKnownTypes(corev1.SchemeGroupVersion, &metav1.PartialObjectMetadata{}, &metav1.PartialObjectMetadataList{})
+
+		return stubs.Clients{MetadataInterface: fake.NewSimpleMetadataClient(scheme, nodes)}
+	}
+
+	policy := Policy{VolumeProviders: []string{config.AWS}}
+
+	t.Run("reads the worker pools", func(t *testing.T) {
+		scanner := NewScanner(&config.PublicCloudSpecs{}, policy, workerPoolsFunc(func(_ context.Context, runtime *runtime.Info) (map[string]WorkerPool, error) {
+			return map[string]WorkerPool{"cpu-worker-0": {VolumeSizeGb: 80}}, nil
+		}))
+
+		for provider, billVolumes := range map[string]bool{config.AWS: true, config.GCP: false} {
+			result, err := scanner.Scan(t.Context(), &runtime.Info{ProviderType: provider}, newClients())
+			require.NoError(t, err)
+
+			nodeScan, ok := result.(*Scan)
+			require.True(t, ok)
+			require.Equal(t, billVolumes, nodeScan.billVolumes)
+			require.Equal(t, map[string]WorkerPool{"cpu-worker-0": {VolumeSizeGb: 80}}, nodeScan.pools)
+		}
+	})
+
+	t.Run("fails if the worker pools cannot be read", func(t *testing.T) {
+		scanner := NewScanner(&config.PublicCloudSpecs{}, policy, workerPoolsFunc(func(_ context.Context, runtime *runtime.Info) (map[string]WorkerPool, error) {
+			return nil, errors.New("gardener unavailable")
+		}))
+
+		result, err := scanner.Scan(t.Context(), &runtime.Info{ProviderType: config.AWS}, newClients())
+		require.ErrorContains(t, err, "gardener unavailable")
+		require.Nil(t, result)
+	})
+}
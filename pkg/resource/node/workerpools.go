@@ -0,0 +1,157 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	apiresource "k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/kyma-project/kyma-metrics-collector/pkg/gardener"
+	"github.com/kyma-project/kyma-metrics-collector/pkg/resource"
+	"github.com/kyma-project/kyma-metrics-collector/pkg/runtime"
+)
+
+// WorkerPool is the billing relevant configuration of a worker pool of a runtime.
+type WorkerPool struct {
+	// VolumeSizeGb is the size of the root volumes of the nodes of the pool, or 0 if the pool does not define it.
+	VolumeSizeGb int64
+	// Spot is true if the labels or taints of the pool mark its nodes as spot or preemptible.
+	Spot bool
+}
+
+// WorkerPools provides the worker pools of a runtime by name.
+type WorkerPools interface {
+	WorkerPools(ctx context.Context, runtime *runtime.Info) (map[string]WorkerPool, error)
+}
+
+// GardenerWorkerPools reads the worker pools from the Gardener shoots of the runtimes.
+// The worker pools rarely change, so they are cached for the TTL.
+type GardenerWorkerPools struct {
+	client    dynamic.Interface
+	namespace string
+	ttl       time.Duration
+	now       func() time.Time
+
+	mu     sync.Mutex
+	shoots map[string]cachedWorkerPools
+}
+
+type cachedWorkerPools struct {
+	pools     map[string]WorkerPool
+	expiresAt time.Time
+}
+
+// NewGardenerWorkerPools creates a GardenerWorkerPools reading the shoots in the namespace of the Gardener project.
+func NewGardenerWorkerPools(client dynamic.Interface, namespace string, ttl time.Duration) *GardenerWorkerPools {
+	return &GardenerWorkerPools{
+		client:    client,
+		namespace: namespace,
+		ttl:       ttl,
+		now:       time.Now,
+		shoots:    make(map[string]cachedWorkerPools),
+	}
+}
+
+// WorkerPools returns the worker pools of the shoot of the runtime. Shoots which are not found, e.g. because the
+// runtime is not managed by the Gardener project, have no worker pools.
+func (g *GardenerWorkerPools) WorkerPools(ctx context.Context, runtime *runtime.Info) (map[string]WorkerPool, error) {
+	now := g.now()
+
+	g.mu.Lock()
+	cached, found := g.shoots[runtime.ShootName]
+	g.mu.Unlock()
+
+	if found && now.Before(cached.expiresAt) {
+		return cached.pools, nil
+	}
+
+	shoot, err := g.client.Resource(gardener.ShootGVR).Namespace(g.namespace).Get(ctx, runtime.ShootName, metav1.GetOptions{})
+
+	var pools map[string]WorkerPool
+
+	switch {
+	case k8serrors.IsNotFound(err):
+	case err != nil:
+		return nil, fmt.Errorf("failed to get shoot %s: %w", runtime.ShootName, err)
+	default:
+		pools, err = workerPools(shoot)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read worker pools of shoot %s: %w", runtime.ShootName, err)
+		}
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.deleteExpiredLocked(now)
+	g.shoots[runtime.ShootName] = cachedWorkerPools{pools: pools, expiresAt: now.Add(g.ttl)}
+
+	return pools, nil
+}
+
+func (g *GardenerWorkerPools) deleteExpiredLocked(now time.Time) {
+	for shootName, cached := range g.shoots {
+		if !now.Before(cached.expiresAt) {
+			delete(g.shoots, shootName)
+		}
+	}
+}
+
+// workerPools returns the spec.provider.workers[] of the shoot by worker pool name. The volume size is read from
+// volume.size, and the pool is spot if its labels or taints contain one of the spotLabels.
+func workerPools(shoot *unstructured.Unstructured) (map[string]WorkerPool, error) {
+	workers, _, err := unstructured.NestedSlice(shoot.Object, "spec", "provider", "workers")
+	if err != nil {
+		return nil, err
+	}
+
+	pools := make(map[string]WorkerPool, len(workers))
+
+	for _, worker := range workers {
+		workerObj, ok := worker.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		name, _, _ := unstructured.NestedString(workerObj, "name")
+
+		var pool WorkerPool
+
+		if size, found, _ := unstructured.NestedString(workerObj, "volume", "size"); found {
+			quantity, err := apiresource.ParseQuantity(size)
+			if err != nil {
+				return nil, fmt.Errorf("invalid volume size %q of worker pool %s: %w", size, name, err)
+			}
+
+			// partial GBs are rounded up, e.g. 100G is 94 GB and 512Mi is 1 GB
+			pool.VolumeSizeGb = (quantity.Value() + resource.GiB - 1) / resource.GiB
+		}
+
+		labels, _, _ := unstructured.NestedStringMap(workerObj, "labels")
+		for key, value := range labels {
+			pool.Spot = pool.Spot || isSpotLabel(key, value)
+		}
+
+		taints, _, _ := unstructured.NestedSlice(workerObj, "taints")
+		for _, taint := range taints {
+			taintObj, ok := taint.(map[string]any)
+			if !ok {
+				continue
+			}
+
+			key, _, _ := unstructured.NestedString(taintObj, "key")
+			value, _, _ := unstructured.NestedString(taintObj, "value")
+			pool.Spot = pool.Spot || isSpotLabel(key, value)
+		}
+
+		pools[name] = pool
+	}
+
+	return pools, nil
+}
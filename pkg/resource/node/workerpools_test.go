@@ -0,0 +1,88 @@
+package node
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/kyma-project/kyma-metrics-collector/pkg/gardener"
+	"github.com/kyma-project/kyma-metrics-collector/pkg/runtime"
+)
+
+const gardenerNamespace = "garden-kyma"
+
+func newShootWithWorkers(name string, workers ...any) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": gardener.ShootGVR.GroupVersion().String(),
+		"kind":       "Shoot",
+		"metadata":   map[string]any{"name": name, "namespace": gardenerNamespace},
+		"spec":       map[string]any{"provider": map[string]any{"workers": workers}},
+	}}
+}
+
+func TestGardenerWorkerPools(t *testing.T) {
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(k8sruntime.NewScheme(),
+		map[schema.GroupVersionResource]string{gardener.ShootGVR: "ShootList"},
+		newShootWithWorkers("shoot-a",
+			map[string]any{"name": "cpu-worker-0", "volume": map[string]any{"size": "80Gi"}},
+			map[string]any{"name": "cpu-worker-1"},
+			map[string]any{"name": "decimal-volume", "volume": map[string]any{"size": "100G"}},
+			map[string]any{"name": "small-volume", "volume": map[string]any{"size": "512Mi"}},
+			map[string]any{"name": "spot-labels", "labels": map[string]any{"node.kubernetes.io/lifecycle": "spot"}},
+			map[string]any{"name": "spot-taints", "taints": []any{
+				map[string]any{"key": "kubernetes.azure.com/scalesetpriority", "value": "spot", "effect": "NoSchedule"},
+			}},
+			map[string]any{"name": "on-demand", "labels": map[string]any{"node.kubernetes.io/lifecycle": "normal"}},
+		),
+		newShootWithWorkers("shoot-invalid",
+			map[string]any{"name": "cpu-worker-0", "volume": map[string]any{"size": "large"}},
+		),
+	)
+
+	gets := 0
+	client.PrependReactor("get", "shoots", func(action k8stesting.Action) (bool, k8sruntime.Object, error) {
+		gets++
+		return false, nil, nil
+	})
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	workerPools := NewGardenerWorkerPools(client, gardenerNamespace, time.Hour)
+	workerPools.now = func() time.Time { return now }
+
+	pools, err := workerPools.WorkerPools(t.Context(), &runtime.Info{ShootName: "shoot-a"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]WorkerPool{
+		"cpu-worker-0": {VolumeSizeGb: 80},
+		"cpu-worker-1": {},
+		// partial GBs are rounded up
+		"decimal-volume": {VolumeSizeGb: 94},
+		"small-volume":   {VolumeSizeGb: 1},
+		"spot-labels":    {Spot: true},
+		"spot-taints":    {Spot: true},
+		"on-demand":      {},
+	}, pools)
+
+	// the pools are cached
+	_, err = workerPools.WorkerPools(t.Context(), &runtime.Info{ShootName: "shoot-a"})
+	require.NoError(t, err)
+	require.Equal(t, 1, gets)
+
+	now = now.Add(2 * time.Hour)
+	_, err = workerPools.WorkerPools(t.Context(), &runtime.Info{ShootName: "shoot-a"})
+	require.NoError(t, err)
+	require.Equal(t, 2, gets)
+
+	// shoots which are not in the project have no worker pools
+	pools, err = workerPools.WorkerPools(t.Context(), &runtime.Info{ShootName: "shoot-unknown"})
+	require.NoError(t, err)
+	require.Empty(t, pools)
+
+	_, err = workerPools.WorkerPools(t.Context(), &runtime.Info{ShootName: "shoot-invalid"})
+	require.ErrorContains(t, err, `invalid volume size "large" of worker pool cpu-worker-0`)
+}
@@ -1,8 +1,6 @@
 package pvc
 
 import (
-	"errors"
-	"math"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
@@ -11,15 +9,8 @@ import (
 	"github.com/kyma-project/kyma-metrics-collector/pkg/resource"
 )
 
-const (
-	// storageRoundingFactor rounds of storage to 32. E.g. 17 -> 32, 33 -> 64.
-	storageRoundingFactor = 32
-
-	// nfsPriceMultiplier is the factor by which the NFS PVCs are multiplied to compensate for the higher price.
-	nfsPriceMultiplier = 3
-
-	GiB = 1 << (10 * 3) //nolint:mnd // 1 GiB = 1024^3 bytes
-)
+// nfsPriceMultiplier is the factor by which the NFS PVCs are multiplied to compensate for the higher price.
+const nfsPriceMultiplier = 3
 
 var nfsLabels = map[string]string{
 	"app.kubernetes.io/component":  "cloud-manager",
@@ -29,54 +20,88 @@ var nfsLabels = map[string]string{
 
 const nfsCapacityLabel = "cloud-resources.kyma-project.io/nfsVolumeStorageCapacity"
 
-var _ resource.ScanConverter = &Scan{}
+var (
+	_ resource.ScanConverter = &Scan{}
+	_ resource.TimeWeighted  = &Scan{}
+)
 
 type Scan struct {
-	pvcs corev1.PersistentVolumeClaimList
+	pvcs      corev1.PersistentVolumeClaimList
+	scannedAt time.Time
 }
 
 func (s *Scan) UM(duration time.Duration) (resource.UMMeasurement, error) {
-	return resource.UMMeasurement{}, nil
+	rates, err := s.Rates()
+
+	return resource.UMMeasurement{
+		Usage: resource.Integrate(nil, rates, s.scannedAt.Add(-duration), s.scannedAt),
+	}, err
 }
 
-func (s *Scan) EDP() (resource.EDPMeasurement, error) {
-	edp := resource.EDPMeasurement{}
+func (s *Scan) ScannedAt() time.Time {
+	return s.scannedAt
+}
 
-	var errs []error
+// Rates returns the billed size of every bound PVC per hour.
+func (s *Scan) Rates() ([]resource.Rate, error) {
+	var rates []resource.Rate
 
 	for _, pvc := range s.pvcs.Items {
-		// if the pvc has all labels defined in nfsLabels then it is an NFS PVC
-		if hasAllLabels(pvc.Labels, nfsLabels) {
-			if pvc.Status.Phase == corev1.ClaimBound {
-				currPVC := getSizeInGB(pvc.Status.Capacity.Storage())
-
-				// label is used as primary source of truth for size - when present, and valid
-				if sizeFromLabel, ok := pvc.Labels[nfsCapacityLabel]; ok {
-					quantityFromLabel, err := apiresource.ParseQuantity(sizeFromLabel)
-					if err == nil {
-						currPVC = getSizeInGB(&quantityFromLabel)
-					}
-				}
-
-				// for NFS PVCs we multiply the used capacity by 3 to compensate for the higher price
-				nfsPVCStorage := currPVC * nfsPriceMultiplier
-				edp.ProvisionedVolumes.SizeGbTotal += nfsPVCStorage
-				edp.ProvisionedVolumes.SizeGbRounded += getVolumeRoundedToFactor(nfsPVCStorage)
-				edp.ProvisionedVolumes.Count += 1
-			}
+		size, bound := billedSizeInGB(pvc)
+		if !bound {
+			continue
+		}
+
+		rates = append(rates, resource.Rate{
+			Key:       resource.ObjectKey(&pvc),
+			CreatedAt: pvc.CreationTimestamp.Time,
+			PerHour:   resource.Usage{StorageGbHours: float64(size)},
+		})
+	}
 
+	return rates, nil
+}
+
+func (s *Scan) EDP() (resource.EDPMeasurement, error) {
+	edp := resource.EDPMeasurement{}
+
+	for _, pvc := range s.pvcs.Items {
+		size, bound := billedSizeInGB(pvc)
+		if !bound {
 			continue
 		}
 
-		if pvc.Status.Phase == corev1.ClaimBound {
-			currPVC := getSizeInGB(pvc.Status.Capacity.Storage())
-			edp.ProvisionedVolumes.SizeGbTotal += currPVC
-			edp.ProvisionedVolumes.SizeGbRounded += getVolumeRoundedToFactor(currPVC)
-			edp.ProvisionedVolumes.Count += 1
+		edp.ProvisionedVolumes.SizeGbTotal += size
+		edp.ProvisionedVolumes.SizeGbRounded += resource.GetVolumeRoundedToFactor(size)
+		edp.ProvisionedVolumes.Count += 1
+	}
+
+	return edp, nil
+}
+
+// billedSizeInGB returns the billed size of the PVC, and false if the PVC is not bound and hence not billed.
+func billedSizeInGB(pvc corev1.PersistentVolumeClaim) (int64, bool) {
+	if pvc.Status.Phase != corev1.ClaimBound {
+		return 0, false
+	}
+
+	currPVC := getSizeInGB(pvc.Status.Capacity.Storage())
+
+	// if the pvc has all labels defined in nfsLabels then it is an NFS PVC
+	if !hasAllLabels(pvc.Labels, nfsLabels) {
+		return currPVC, true
+	}
+
+	// label is used as primary source of truth for size - when present, and valid
+	if sizeFromLabel, ok := pvc.Labels[nfsCapacityLabel]; ok {
+		quantityFromLabel, err := apiresource.ParseQuantity(sizeFromLabel)
+		if err == nil {
+			currPVC = getSizeInGB(&quantityFromLabel)
 		}
 	}
 
-	return edp, errors.Join(errs...)
+	// for NFS PVCs we multiply the used capacity by 3 to compensate for the higher price
+	return currPVC * nfsPriceMultiplier, true
 }
 
 // hasAllLabels checks if the labels map contains all the labels in want.
@@ -90,10 +115,6 @@ func hasAllLabels(has, want map[string]string) bool {
 	return true
 }
 
-func getVolumeRoundedToFactor(size int64) int64 {
-	return int64(math.Ceil(float64(size)/storageRoundingFactor) * storageRoundingFactor)
-}
-
 // getSizeInGB converts any value in binarySI representation to GB
 // More info: https://github.com/kubernetes/apimachinery/blob/master/pkg/api/resource/quantity.go#L31
 func getSizeInGB(value *apiresource.Quantity) int64 {
@@ -101,7 +122,7 @@ func getSizeInGB(value *apiresource.Quantity) int64 {
 	milliVal := value.MilliValue()
 
 	// Converting back from milli to original
-	gVal := int64((float64(milliVal) / GiB) / 1000) //nolint:mnd // 1000 is the factor to convert from milli to original
+	gVal := int64((float64(milliVal) / resource.GiB) / 1000) //nolint:mnd // 1000 is the factor to convert from milli to original
 
 	return gVal
 }
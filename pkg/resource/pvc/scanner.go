@@ -3,6 +3,7 @@ package pvc
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/codes"
@@ -43,6 +44,7 @@ func (s *Scanner) Scan(ctx context.Context, runtime *runtime.Info, clients runti
 	}
 
 	return &Scan{
-		pvcs: *pvcs,
+		pvcs:      *pvcs,
+		scannedAt: time.Now(),
 	}, nil
 }
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	cloudresourcesv1beta1 "github.com/kyma-project/cloud-manager/api/cloud-resources/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/kyma-project/kyma-metrics-collector/pkg/config"
 	"github.com/kyma-project/kyma-metrics-collector/pkg/resource"
@@ -13,18 +14,63 @@ import (
 
 var ErrUnknownRedisTier = errors.New("redis tier not defined")
 
-var _ resource.ScanConverter = &Scan{}
+var (
+	_ resource.ScanConverter  = &Scan{}
+	_ resource.SpecsVersioned = &Scan{}
+	_ resource.TimeWeighted   = &Scan{}
+)
 
 type Scan struct {
-	specs *config.PublicCloudSpecs
+	providerType string
+	region       string
+	plan         string
+	specs        *config.PublicCloudSpecs
 
 	aws   cloudresourcesv1beta1.AwsRedisInstanceList
 	azure cloudresourcesv1beta1.AzureRedisInstanceList
 	gcp   cloudresourcesv1beta1.GcpRedisInstanceList
+
+	scannedAt time.Time
+}
+
+func (s *Scan) SpecsVersion() string {
+	return s.specs.Version
 }
 
 func (s *Scan) UM(duration time.Duration) (resource.UMMeasurement, error) {
-	return resource.UMMeasurement{}, nil
+	rates, err := s.Rates()
+
+	return resource.UMMeasurement{
+		Usage: resource.Integrate(nil, rates, s.scannedAt.Add(-duration), s.scannedAt),
+	}, err
+}
+
+func (s *Scan) ScannedAt() time.Time {
+	return s.scannedAt
+}
+
+// Rates returns the priced storage of the tier of every Redis instance per hour.
+func (s *Scan) Rates() ([]resource.Rate, error) {
+	var (
+		rates []resource.Rate
+		errs  []error
+	)
+
+	for _, instance := range s.listInstances() {
+		redisStorage := s.specs.ResolveRedisInfo(s.providerType, s.region, s.plan, instance.tier)
+		if redisStorage == nil {
+			errs = append(errs, fmt.Errorf("%w: %s", ErrUnknownRedisTier, instance.tier))
+			continue
+		}
+
+		rates = append(rates, resource.Rate{
+			Key:       resource.ObjectKey(instance.meta),
+			CreatedAt: instance.meta.GetCreationTimestamp().Time,
+			PerHour:   resource.Usage{StorageGbHours: float64(redisStorage.PriceStorageGB)},
+		})
+	}
+
+	return rates, errors.Join(errs...)
 }
 
 func (s *Scan) EDP() (resource.EDPMeasurement, error) {
@@ -33,7 +79,7 @@ func (s *Scan) EDP() (resource.EDPMeasurement, error) {
 	var errs []error
 
 	for _, tier := range s.listTiers() {
-		redisStorage := s.specs.GetRedisInfo(tier)
+		redisStorage := s.specs.ResolveRedisInfo(s.providerType, s.region, s.plan, tier)
 		if redisStorage == nil {
 			errs = append(errs, fmt.Errorf("%w: %s", ErrUnknownRedisTier, tier))
 			continue
@@ -48,6 +94,29 @@ func (s *Scan) EDP() (resource.EDPMeasurement, error) {
 	return edp, errors.Join(errs...)
 }
 
+type redisInstance struct {
+	meta metav1.Object
+	tier string
+}
+
+func (s *Scan) listInstances() []redisInstance {
+	var instances []redisInstance
+
+	for i := range s.aws.Items {
+		instances = append(instances, redisInstance{meta: &s.aws.Items[i], tier: string(s.aws.Items[i].Spec.RedisTier)})
+	}
+
+	for i := range s.azure.Items {
+		instances = append(instances, redisInstance{meta: &s.azure.Items[i], tier: string(s.azure.Items[i].Spec.RedisTier)})
+	}
+
+	for i := range s.gcp.Items {
+		instances = append(instances, redisInstance{meta: &s.gcp.Items[i], tier: string(s.gcp.Items[i].Spec.RedisTier)})
+	}
+
+	return instances
+}
+
 func (s *Scan) listTiers() []string {
 	var tiers []string
 
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/codes"
@@ -58,7 +59,9 @@ func (s *Scanner) Scan(ctx context.Context, runtime *runtime.Info, clients runti
 	gcp := dynamicClient.Resource(gcpRedisGVR)
 
 	scan := Scan{
-		specs: s.specs,
+		providerType: runtime.ProviderType,
+		region:       runtime.Region,
+		plan:         runtime.PlanName,
 	}
 
 	var errs []error
@@ -82,6 +85,9 @@ func (s *Scanner) Scan(ctx context.Context, runtime *runtime.Info, clients runti
 	}
 
 	if len(errs) == 0 {
+		scan.scannedAt = time.Now()
+		scan.specs = s.specs.At(scan.scannedAt)
+
 		return &scan, nil
 	}
 
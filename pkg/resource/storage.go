@@ -0,0 +1,15 @@
+package resource
+
+import "math"
+
+const (
+	// storageRoundingFactor rounds of storage to 32. E.g. 17 -> 32, 33 -> 64.
+	storageRoundingFactor = 32
+
+	GiB = 1 << (10 * 3) //nolint:mnd // 1 GiB = 1024^3 bytes
+)
+
+// GetVolumeRoundedToFactor rounds the size of a volume in GB up to a multiple of 32 GB.
+func GetVolumeRoundedToFactor(size int64) int64 {
+	return int64(math.Ceil(float64(size)/storageRoundingFactor) * storageRoundingFactor)
+}
@@ -5,6 +5,20 @@ type EDPMeasurement struct {
 	ProvisionedCPUs    float64            `json:"provisioned_cpus"    validate:"numeric"`
 	ProvisionedRAMGb   float64            `json:"provisioned_ram_gb"  validate:"numeric"`
 	ProvisionedVolumes ProvisionedVolumes `json:"provisioned_volumes" validate:"required"`
+	// ProvisionedGPUs is only set for runtimes with GPU nodes, so the payload of other runtimes is unchanged.
+	ProvisionedGPUs *ProvisionedGPUs `json:"provisioned_gpus,omitempty"`
+	// NodeCapacityTypes is only set for runtimes with spot nodes, so the payload of other runtimes is unchanged.
+	NodeCapacityTypes *NodeCapacityTypes `json:"node_capacity_types,omitempty"`
+}
+
+// NodeCapacityTypes is the number of nodes running on spot or preemptible capacity and on on-demand capacity.
+// SpotDiscountCPUs and SpotDiscountRAMGb are the CPU and memory by which the spot discount reduced ProvisionedCPUs and
+// ProvisionedRAMGb, so adding them results in the CPU and memory of the VMTypes.
+type NodeCapacityTypes struct {
+	OnDemandNodes     int     `json:"on_demand_nodes"      validate:"numeric"`
+	SpotNodes         int     `json:"spot_nodes"           validate:"numeric"`
+	SpotDiscountCPUs  float64 `json:"spot_discount_cpus"   validate:"numeric"`
+	SpotDiscountRAMGb float64 `json:"spot_discount_ram_gb" validate:"numeric"`
 }
 
 type VMType struct {
@@ -18,4 +32,66 @@ type ProvisionedVolumes struct {
 	SizeGbRounded int64 `json:"size_gb_rounded" validate:"numeric"`
 }
 
-type UMMeasurement struct{}
+// ProvisionedGPUs summarizes the accelerators of the GPU nodes.
+type ProvisionedGPUs struct {
+	Nodes    int       `json:"nodes"     validate:"numeric"`
+	Count    int       `json:"count"     validate:"numeric"`
+	MemoryGb float64   `json:"memory_gb" validate:"numeric"`
+	Types    []GPUType `json:"types"`
+}
+
+// GPUType is the number of accelerators of a type.
+type GPUType struct {
+	Name  string `json:"name"  validate:"required"`
+	Count int    `json:"count" validate:"numeric"`
+}
+
+// Add adds the other GPUs to the GPUs.
+func (g *ProvisionedGPUs) Add(other ProvisionedGPUs) {
+	g.Nodes += other.Nodes
+	g.Count += other.Count
+	g.MemoryGb += other.MemoryGb
+
+	for _, otherType := range other.Types {
+		found := false
+
+		for i := range g.Types {
+			if g.Types[i].Name == otherType.Name {
+				g.Types[i].Count += otherType.Count
+				found = true
+
+				break
+			}
+		}
+
+		if !found {
+			g.Types = append(g.Types, otherType)
+		}
+	}
+}
+
+// NodeBreakdown breaks the nodes of a runtime down per worker pool and per zone.
+type NodeBreakdown struct {
+	Pools []NodeGroup `json:"pools"`
+	Zones []NodeGroup `json:"zones"`
+}
+
+// NodeGroup summarizes the nodes of a worker pool or a zone. The name is empty for the nodes without pool or zone.
+type NodeGroup struct {
+	Name             string   `json:"name"`
+	Architectures    []string `json:"architectures,omitempty"`
+	VMTypes          []VMType `json:"vm_types"`
+	Count            int      `json:"count"`
+	ProvisionedCPUs  float64  `json:"provisioned_cpus"`
+	ProvisionedRAMGb float64  `json:"provisioned_ram_gb"`
+}
+
+// UMMeasurement is the measurement of a scan for a unified metering record.
+type UMMeasurement struct {
+	// Usage is the usage of the resources of the scan over the duration passed to UM.
+	Usage Usage
+	// Nodes is the breakdown of the nodes per worker pool and zone. It is only set for node scans.
+	Nodes *NodeBreakdown
+	// GPUs are the accelerators of the nodes. It is only set for node scans with GPU nodes.
+	GPUs *ProvisionedGPUs
+}
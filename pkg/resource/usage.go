@@ -0,0 +1,109 @@
+package resource
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Usage is the usage of resources integrated over a period of time.
+type Usage struct {
+	NodeHours      float64 `json:"node_hours"       validate:"numeric"`
+	CPUHours       float64 `json:"cpu_hours"        validate:"numeric"`
+	RAMGbHours     float64 `json:"ram_gb_hours"     validate:"numeric"`
+	StorageGbHours float64 `json:"storage_gb_hours" validate:"numeric"`
+	// GPUHours are the accelerator hours of GPU nodes. It is omitted for runtimes without GPUs.
+	GPUHours float64 `json:"gpu_hours,omitempty" validate:"numeric"`
+}
+
+// Add adds the other usage to the usage.
+func (u *Usage) Add(other Usage) {
+	u.NodeHours += other.NodeHours
+	u.CPUHours += other.CPUHours
+	u.RAMGbHours += other.RAMGbHours
+	u.StorageGbHours += other.StorageGbHours
+	u.GPUHours += other.GPUHours
+}
+
+// over returns the usage of a rate over the duration.
+func (u Usage) over(duration time.Duration) Usage {
+	hours := duration.Hours()
+
+	return Usage{
+		NodeHours:      u.NodeHours * hours,
+		CPUHours:       u.CPUHours * hours,
+		RAMGbHours:     u.RAMGbHours * hours,
+		StorageGbHours: u.StorageGbHours * hours,
+		GPUHours:       u.GPUHours * hours,
+	}
+}
+
+// Rate is the usage of a single resource per hour, e.g. a node with its CPUs and memory, or a volume with its size.
+type Rate struct {
+	// Key identifies the resource across scans, e.g. its UID.
+	Key string
+	// CreatedAt is the creation time of the resource. It is zero if unknown.
+	CreatedAt time.Time
+	// PerHour is the usage of the resource per hour of its existence.
+	PerHour Usage
+}
+
+// ObjectKey returns the key of a Kubernetes object for a Rate. Objects are identified by UID, so a resource which is
+// recreated with the same name is billed as a new resource.
+func ObjectKey(obj metav1.Object) string {
+	if uid := obj.GetUID(); uid != "" {
+		return string(uid)
+	}
+
+	return obj.GetNamespace() + "/" + obj.GetName()
+}
+
+// TimeWeighted is implemented by scans whose usage can be integrated over the time between consecutive scans.
+type TimeWeighted interface {
+	// ScannedAt returns the time of the scan.
+	ScannedAt() time.Time
+	// Rates returns the usage rates of the resources at the time of the scan.
+	// Resources which cannot be converted are skipped and reported in the error.
+	Rates() ([]Rate, error)
+}
+
+// Integrate returns the usage of the resources in the period from..to, where previous are the rates of the scan at
+// from and current the rates of the scan at to. Resources are only known at the times of the scans, so:
+//   - resources of the current scan are billed from their creation, or from the start of the period if they are older.
+//   - resources of the previous scan which are gone are billed until the middle of the period.
+func Integrate(previous, current []Rate, from, to time.Time) Usage {
+	var usage Usage
+
+	period := to.Sub(from)
+	if period <= 0 {
+		return usage
+	}
+
+	currentKeys := make(map[string]struct{}, len(current))
+
+	for _, rate := range current {
+		currentKeys[rate.Key] = struct{}{}
+
+		start := from
+		if rate.CreatedAt.After(start) {
+			start = rate.CreatedAt
+		}
+
+		if start.After(to) {
+			// the clocks of KMC and the runtime are skewed
+			continue
+		}
+
+		usage.Add(rate.PerHour.over(to.Sub(start)))
+	}
+
+	for _, rate := range previous {
+		if _, found := currentKeys[rate.Key]; found {
+			continue
+		}
+
+		usage.Add(rate.PerHour.over(period / 2)) //nolint:mnd // the resource was removed in the middle of the period
+	}
+
+	return usage
+}
@@ -0,0 +1,105 @@
+package resource
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestIntegrate(t *testing.T) {
+	from := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	to := from.Add(time.Hour)
+	node := Usage{NodeHours: 1, CPUHours: 4, RAMGbHours: 16}
+	volume := Usage{StorageGbHours: 32}
+
+	tests := []struct {
+		name     string
+		previous []Rate
+		current  []Rate
+		from     time.Time
+		to       time.Time
+		expected Usage
+	}{
+		{
+			name:     "no resources",
+			from:     from,
+			to:       to,
+			expected: Usage{},
+		},
+		{
+			name:     "resources existing during the whole period",
+			previous: []Rate{{Key: "node", PerHour: node}, {Key: "volume", PerHour: volume}},
+			current:  []Rate{{Key: "node", PerHour: node}, {Key: "volume", PerHour: volume}},
+			from:     from,
+			to:       to,
+			expected: Usage{NodeHours: 1, CPUHours: 4, RAMGbHours: 16, StorageGbHours: 32},
+		},
+		{
+			name:     "resource created during the period",
+			current:  []Rate{{Key: "node", CreatedAt: from.Add(45 * time.Minute), PerHour: node}},
+			from:     from,
+			to:       to,
+			expected: Usage{NodeHours: 0.25, CPUHours: 1, RAMGbHours: 4},
+		},
+		{
+			name:     "resource created before the period",
+			current:  []Rate{{Key: "node", CreatedAt: from.Add(-time.Hour), PerHour: node}},
+			from:     from,
+			to:       to,
+			expected: Usage{NodeHours: 1, CPUHours: 4, RAMGbHours: 16},
+		},
+		{
+			name:     "resource created after the scan",
+			current:  []Rate{{Key: "node", CreatedAt: to.Add(time.Minute), PerHour: node}},
+			from:     from,
+			to:       to,
+			expected: Usage{},
+		},
+		{
+			name:     "resource deleted during the period",
+			previous: []Rate{{Key: "volume", PerHour: volume}},
+			from:     from,
+			to:       to,
+			expected: Usage{StorageGbHours: 16},
+		},
+		{
+			name:     "resource recreated during the period",
+			previous: []Rate{{Key: "old-node", PerHour: node}},
+			current:  []Rate{{Key: "new-node", CreatedAt: from.Add(30 * time.Minute), PerHour: node}},
+			from:     from,
+			to:       to,
+			expected: Usage{NodeHours: 1, CPUHours: 4, RAMGbHours: 16},
+		},
+		{
+			name:     "empty period",
+			previous: []Rate{{Key: "node", PerHour: node}},
+			current:  []Rate{{Key: "node", PerHour: node}},
+			from:     to,
+			to:       to,
+			expected: Usage{},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			require.InDeltaMapValues(t, usageMap(tc.expected), usageMap(Integrate(tc.previous, tc.current, tc.from, tc.to)), 1e-9)
+		})
+	}
+}
+
+func TestObjectKey(t *testing.T) {
+	require.Equal(t, "uid", ObjectKey(&metav1.ObjectMeta{UID: "uid", Namespace: "ns", Name: "name"}))
+	require.Equal(t, "ns/name", ObjectKey(&metav1.ObjectMeta{Namespace: "ns", Name: "name"}))
+}
+
+func usageMap(usage Usage) map[string]float64 {
+	return map[string]float64{
+		"node_hours":       usage.NodeHours,
+		"cpu_hours":        usage.CPUHours,
+		"ram_gb_hours":     usage.RAMGbHours,
+		"storage_gb_hours": usage.StorageGbHours,
+		"gpu_hours":        usage.GPUHours,
+	}
+}
@@ -3,7 +3,6 @@ package vsc
 import (
 	"errors"
 	"fmt"
-	"math"
 	"time"
 
 	v1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
@@ -11,24 +10,55 @@ import (
 	"github.com/kyma-project/kyma-metrics-collector/pkg/resource"
 )
 
-const (
-	// storageRoundingFactor rounds of storage to 32. E.g. 17 -> 32, 33 -> 64.
-	storageRoundingFactor = 32
-
-	GiB = 1 << (10 * 3) //nolint:mnd // 1 GiB = 1024^3 bytes
-)
-
 var (
 	_                    resource.ScanConverter = &Scan{}
+	_                    resource.TimeWeighted  = &Scan{}
 	ErrRestoreSizeNotSet                        = fmt.Errorf("VolumeSnapshotContent: RestoreSize not set")
 )
 
 type Scan struct {
-	vscs v1.VolumeSnapshotContentList
+	vscs      v1.VolumeSnapshotContentList
+	scannedAt time.Time
 }
 
 func (s *Scan) UM(duration time.Duration) (resource.UMMeasurement, error) {
-	return resource.UMMeasurement{}, nil
+	rates, err := s.Rates()
+
+	return resource.UMMeasurement{
+		Usage: resource.Integrate(nil, rates, s.scannedAt.Add(-duration), s.scannedAt),
+	}, err
+}
+
+func (s *Scan) ScannedAt() time.Time {
+	return s.scannedAt
+}
+
+// Rates returns the restore size of every snapshot which is ready to use per hour.
+func (s *Scan) Rates() ([]resource.Rate, error) {
+	var (
+		rates []resource.Rate
+		errs  []error
+	)
+
+	for _, vsc := range s.vscs.Items {
+		size, ready, err := billedSizeInGB(vsc)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		if !ready {
+			continue
+		}
+
+		rates = append(rates, resource.Rate{
+			Key:       resource.ObjectKey(&vsc),
+			CreatedAt: vsc.CreationTimestamp.Time,
+			PerHour:   resource.Usage{StorageGbHours: float64(size)},
+		})
+	}
+
+	return rates, errors.Join(errs...)
 }
 
 func (s *Scan) EDP() (resource.EDPMeasurement, error) {
@@ -36,33 +66,44 @@ func (s *Scan) EDP() (resource.EDPMeasurement, error) {
 	edp := resource.EDPMeasurement{}
 
 	for _, vsc := range s.vscs.Items {
-		if vsc.Status == nil {
-			// Skip VSCs without status
+		currVSC, ready, err := billedSizeInGB(vsc)
+		if err != nil {
+			errs = append(errs, err)
 			continue
 		}
 
-		if vsc.Status.ReadyToUse != nil && *vsc.Status.ReadyToUse {
-			if vsc.Status.RestoreSize == nil {
-				errs = append(errs, fmt.Errorf("%w: %s", ErrRestoreSizeNotSet, vsc.Name))
-				continue
-			}
-
-			currVSC := getSizeInGB(*vsc.Status.RestoreSize)
-			edp.ProvisionedVolumes.SizeGbTotal += currVSC
-			edp.ProvisionedVolumes.SizeGbRounded += getVolumeRoundedToFactor(currVSC)
-			edp.ProvisionedVolumes.Count += 1
+		if !ready {
+			continue
 		}
+
+		edp.ProvisionedVolumes.SizeGbTotal += currVSC
+		edp.ProvisionedVolumes.SizeGbRounded += resource.GetVolumeRoundedToFactor(currVSC)
+		edp.ProvisionedVolumes.Count += 1
 	}
 
 	return edp, errors.Join(errs...)
 }
 
-func getSizeInGB(value int64) int64 {
-	gVal := int64(float64(value) / GiB)
+// billedSizeInGB returns the restore size of the snapshot, and false if the snapshot is not ready to use and hence not billed.
+func billedSizeInGB(vsc v1.VolumeSnapshotContent) (int64, bool, error) {
+	if vsc.Status == nil {
+		// Skip VSCs without status
+		return 0, false, nil
+	}
 
-	return gVal
+	if vsc.Status.ReadyToUse == nil || !*vsc.Status.ReadyToUse {
+		return 0, false, nil
+	}
+
+	if vsc.Status.RestoreSize == nil {
+		return 0, false, fmt.Errorf("%w: %s", ErrRestoreSizeNotSet, vsc.Name)
+	}
+
+	return getSizeInGB(*vsc.Status.RestoreSize), true, nil
 }
 
-func getVolumeRoundedToFactor(size int64) int64 {
-	return int64(math.Ceil(float64(size)/storageRoundingFactor) * storageRoundingFactor)
+func getSizeInGB(value int64) int64 {
+	gVal := int64(float64(value) / resource.GiB)
+
+	return gVal
 }
@@ -3,6 +3,7 @@ package vsc
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/codes"
@@ -39,6 +40,7 @@ func (s *Scanner) Scan(ctx context.Context, runtime *runtime.Info, clients runti
 	}
 
 	return &Scan{
-		vscs: *vscs,
+		vscs:      *vscs,
+		scannedAt: time.Now(),
 	}, nil
 }
@@ -0,0 +1,260 @@
+package runtime
+
+import (
+	"context"
+	"crypto/sha256"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// Reasons of a client pool eviction recorded in the reason label of the evictions metric.
+const (
+	EvictionReasonKubeconfigChanged = "kubeconfig_changed"
+	EvictionReasonRemoved           = "removed"
+	EvictionReasonIdle              = "idle"
+)
+
+var (
+	clientPoolSize = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "kmc",
+			Subsystem: "skr_client_pool",
+			Name:      "size",
+			Help:      "Number of runtimes with pooled clients.",
+		})
+	clientPoolHits = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "kmc",
+			Subsystem: "skr_client_pool",
+			Name:      "hits_total",
+			Help:      "Number of requests for clients served from the pool.",
+		})
+	clientPoolMisses = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "kmc",
+			Subsystem: "skr_client_pool",
+			Name:      "misses_total",
+			Help:      "Number of requests for clients which created new clients.",
+		})
+	clientPoolEvictions = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "kmc",
+			Subsystem: "skr_client_pool",
+			Name:      "evictions_total",
+			Help:      "Number of pooled clients evicted from the pool per reason.",
+		}, []string{"reason"})
+)
+
+// ClientPool keeps the clients of every runtime across scrapes, so the connections to the runtimes are reused
+// instead of doing a new TLS handshake for every scrape. The clients are keyed by runtime ID and the hash of the
+// kubeconfig, so they are replaced as soon as the kubeconfig changes. Evicted clients which are still in use are
+// closed once they are released.
+type ClientPool struct {
+	factory     ClientFactory
+	idleTimeout time.Duration
+	now         func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*pooledClients
+}
+
+type pooledClients struct {
+	kubeconfigHash [sha256.Size]byte
+	clients        InterfaceCloser
+	lastUsed       time.Time
+	// refs is the number of unreleased Get calls which returned the clients.
+	refs    int
+	evicted bool
+}
+
+// NewClientPool creates a pool which evicts the clients of a runtime if they were not used for the idle timeout.
+// The idle connections of pooled clients are kept open for the idle timeout as well.
+func NewClientPool(factory ClientFactory, idleTimeout time.Duration) *ClientPool {
+	return &ClientPool{
+		factory:     factory,
+		idleTimeout: idleTimeout,
+		now:         time.Now,
+		entries:     make(map[string]*pooledClients),
+	}
+}
+
+// Get returns the pooled clients of the runtime, or creates new clients if there are none for the kubeconfig.
+// The returned release func must be called once the clients are not used anymore.
+func (p *ClientPool) Get(runtimeID string, kubeconfig []byte) (Interface, func(), error) {
+	hash := sha256.Sum256(kubeconfig)
+
+	if entry := p.acquire(runtimeID, hash); entry != nil {
+		clientPoolHits.Inc()
+
+		return entry.clients, p.releaseFunc(entry), nil
+	}
+
+	clientPoolMisses.Inc()
+
+	// the clients are created without holding the lock, so slow kubeconfigs do not block the other workers
+	config, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	p.keepIdleConnections(config)
+
+	clients, err := p.factory.NewClients(config)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	entry, found := p.entries[runtimeID]
+	if found && entry.kubeconfigHash == hash {
+		// another worker created the clients for the same kubeconfig in the meantime
+		clients.CloseConnections()
+
+		entry.lastUsed = p.now()
+		entry.refs++
+
+		return entry.clients, p.releaseFunc(entry), nil
+	}
+
+	if found {
+		p.evictLocked(runtimeID, EvictionReasonKubeconfigChanged)
+	}
+
+	entry = &pooledClients{
+		kubeconfigHash: hash,
+		clients:        clients,
+		lastUsed:       p.now(),
+		refs:           1,
+	}
+	p.entries[runtimeID] = entry
+	clientPoolSize.Set(float64(len(p.entries)))
+
+	return clients, p.releaseFunc(entry), nil
+}
+
+// acquire returns the pooled clients of the runtime for the kubeconfig hash, or nil if there are none.
+func (p *ClientPool) acquire(runtimeID string, hash [sha256.Size]byte) *pooledClients {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	entry, found := p.entries[runtimeID]
+	if !found || entry.kubeconfigHash != hash {
+		return nil
+	}
+
+	entry.lastUsed = p.now()
+	entry.refs++
+
+	return entry
+}
+
+// releaseFunc returns the func which releases the clients of the entry. The clients of an evicted entry are closed
+// when they are released by the last user. Calling the func more than once has no effect.
+func (p *ClientPool) releaseFunc(entry *pooledClients) func() {
+	var once sync.Once
+
+	return func() {
+		once.Do(func() {
+			p.mu.Lock()
+			defer p.mu.Unlock()
+
+			entry.refs--
+			if entry.evicted && entry.refs == 0 {
+				entry.clients.CloseConnections()
+			}
+		})
+	}
+}
+
+// Evict removes the clients of a runtime which is not tracked anymore and closes them once they are released.
+func (p *ClientPool) Evict(runtimeID string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, found := p.entries[runtimeID]; found {
+		p.evictLocked(runtimeID, EvictionReasonRemoved)
+	}
+}
+
+// EvictIdle closes and removes all unused clients which were not used for the idle timeout, and returns their number.
+func (p *ClientPool) EvictIdle() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	evicted := 0
+
+	for runtimeID, entry := range p.entries {
+		// clients in use are not idle, even if the scrape took longer than the idle timeout
+		if entry.refs == 0 && p.now().Sub(entry.lastUsed) >= p.idleTimeout {
+			p.evictLocked(runtimeID, EvictionReasonIdle)
+
+			evicted++
+		}
+	}
+
+	return evicted
+}
+
+// Run evicts the idle clients periodically until the context is done.
+func (p *ClientPool) Run(ctx context.Context) {
+	ticker := time.NewTicker(max(p.idleTimeout/2, time.Second))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.EvictIdle()
+		}
+	}
+}
+
+// Len returns the number of runtimes with pooled clients.
+func (p *ClientPool) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return len(p.entries)
+}
+
+// evictLocked removes the clients of the runtime from the pool. They are closed now if they are not in use,
+// otherwise once they are released.
+func (p *ClientPool) evictLocked(runtimeID, reason string) {
+	entry := p.entries[runtimeID]
+	entry.evicted = true
+
+	if entry.refs == 0 {
+		entry.clients.CloseConnections()
+	}
+
+	delete(p.entries, runtimeID)
+
+	clientPoolEvictions.WithLabelValues(reason).Inc()
+	clientPoolSize.Set(float64(len(p.entries)))
+}
+
+// keepIdleConnections keeps the idle connections open until the clients are evicted. By default, the transport
+// closes them after 90 seconds, which is shorter than the scrape interval.
+func (p *ClientPool) keepIdleConnections(config *rest.Config) {
+	wrap := config.WrapTransport
+	config.WrapTransport = func(rt http.RoundTripper) http.RoundTripper {
+		if transport, ok := rt.(*http.Transport); ok {
+			transport.IdleConnTimeout = p.idleTimeout
+		}
+
+		if wrap != nil {
+			return wrap(rt)
+		}
+
+		return rt
+	}
+}
@@ -0,0 +1,204 @@
+package runtime
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/rest"
+)
+
+const testKubeconfig = `
+apiVersion: v1
+kind: Config
+clusters:
+- name: skr
+  cluster:
+    server: https://%s.example.com
+contexts:
+- name: skr
+  context:
+    cluster: skr
+    user: skr
+current-context: skr
+users:
+- name: skr
+  user:
+    token: token
+`
+
+type countingClients struct {
+	Interface
+	closed int
+}
+
+func (c *countingClients) CloseConnections() {
+	c.closed++
+}
+
+type countingFactory struct {
+	created []*countingClients
+	configs []*rest.Config
+}
+
+func (f *countingFactory) NewClients(config *rest.Config) (InterfaceCloser, error) {
+	clients := &countingClients{}
+	f.created = append(f.created, clients)
+	f.configs = append(f.configs, config)
+
+	return clients, nil
+}
+
+func kubeconfig(server string) []byte {
+	return []byte(fmt.Sprintf(testKubeconfig, server))
+}
+
+func TestClientPool(t *testing.T) {
+	factory := &countingFactory{}
+	pool := NewClientPool(factory, 10*time.Minute)
+	now := time.Now()
+	pool.now = func() time.Time { return now }
+
+	hitsBefore := testutil.ToFloat64(clientPoolHits)
+
+	first, release, err := pool.Get("runtime-1", kubeconfig("first"))
+	require.NoError(t, err)
+	require.Len(t, factory.created, 1)
+	require.Equal(t, "https://first.example.com", factory.configs[0].Host)
+	release()
+
+	// the clients are reused for the same kubeconfig
+	second, release, err := pool.Get("runtime-1", kubeconfig("first"))
+	require.NoError(t, err)
+	require.Same(t, first, second)
+	require.Len(t, factory.created, 1)
+	require.Equal(t, hitsBefore+1, testutil.ToFloat64(clientPoolHits))
+	release()
+
+	// the clients are replaced if the kubeconfig changed
+	changedBefore := testutil.ToFloat64(clientPoolEvictions.WithLabelValues(EvictionReasonKubeconfigChanged))
+
+	rotated, release, err := pool.Get("runtime-1", kubeconfig("rotated"))
+	require.NoError(t, err)
+	require.NotSame(t, first, rotated)
+	require.Equal(t, 1, factory.created[0].closed)
+	require.Equal(t, 1, pool.Len())
+	require.Equal(t, changedBefore+1, testutil.ToFloat64(clientPoolEvictions.WithLabelValues(EvictionReasonKubeconfigChanged)))
+	release()
+
+	// an invalid kubeconfig keeps the previous clients
+	_, _, err = pool.Get("runtime-1", []byte("invalid"))
+	require.Error(t, err)
+	require.Equal(t, 1, pool.Len())
+	require.Equal(t, 0, factory.created[1].closed)
+
+	_, release, err = pool.Get("runtime-2", kubeconfig("second"))
+	require.NoError(t, err)
+	release()
+	require.Equal(t, 2, pool.Len())
+
+	// removed runtimes are evicted
+	pool.Evict("runtime-2")
+	require.Equal(t, 1, pool.Len())
+	require.Equal(t, 1, factory.created[2].closed)
+
+	// idle clients are evicted
+	now = now.Add(5 * time.Minute)
+	require.Equal(t, 0, pool.EvictIdle())
+
+	now = now.Add(5 * time.Minute)
+	require.Equal(t, 1, pool.EvictIdle())
+	require.Equal(t, 0, pool.Len())
+	require.Equal(t, 1, factory.created[1].closed)
+}
+
+func TestClientPoolClosesEvictedClientsOnRelease(t *testing.T) {
+	factory := &countingFactory{}
+	pool := NewClientPool(factory, 10*time.Minute)
+	now := time.Now()
+	pool.now = func() time.Time { return now }
+
+	_, releaseFirst, err := pool.Get("runtime-1", kubeconfig("first"))
+	require.NoError(t, err)
+	_, releaseSecond, err := pool.Get("runtime-1", kubeconfig("first"))
+	require.NoError(t, err)
+
+	// clients in use are not idle
+	now = now.Add(time.Hour)
+	require.Equal(t, 0, pool.EvictIdle())
+
+	// evicted clients in use are closed once they are released by all users
+	pool.Evict("runtime-1")
+	require.Equal(t, 0, pool.Len())
+	require.Equal(t, 0, factory.created[0].closed)
+
+	releaseFirst()
+	releaseFirst()
+	require.Equal(t, 0, factory.created[0].closed)
+
+	releaseSecond()
+	require.Equal(t, 1, factory.created[0].closed)
+}
+
+// blockingFactory creates the clients only once the expected number of calls are waiting, so the calls overlap.
+type blockingFactory struct {
+	countingFactory
+
+	mu      sync.Mutex
+	waiting sync.WaitGroup
+}
+
+func (f *blockingFactory) NewClients(config *rest.Config) (InterfaceCloser, error) {
+	f.waiting.Done()
+	f.waiting.Wait()
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.countingFactory.NewClients(config)
+}
+
+func TestClientPoolCreatesClientsWithoutLock(t *testing.T) {
+	factory := &blockingFactory{}
+	factory.waiting.Add(2)
+	pool := NewClientPool(factory, 10*time.Minute)
+
+	results := make(chan Interface, 2)
+
+	for range 2 {
+		go func() {
+			clients, release, err := pool.Get("runtime-1", kubeconfig("first"))
+			if err == nil {
+				release()
+			}
+
+			results <- clients
+		}()
+	}
+
+	first, second := <-results, <-results
+	require.NotNil(t, first)
+
+	// the clients created last are closed, and both calls return the pooled clients
+	require.Same(t, first, second)
+	require.Len(t, factory.created, 2)
+	require.Equal(t, 1, pool.Len())
+	require.Equal(t, 1, factory.created[0].closed+factory.created[1].closed)
+}
+
+func TestClientPoolKeepsIdleConnections(t *testing.T) {
+	factory := &countingFactory{}
+	pool := NewClientPool(factory, 10*time.Minute)
+
+	_, release, err := pool.Get("runtime-1", kubeconfig("first"))
+	require.NoError(t, err)
+	release()
+
+	transport := &http.Transport{IdleConnTimeout: 90 * time.Second}
+	factory.configs[0].WrapTransport(transport)
+	require.Equal(t, 10*time.Minute, transport.IdleConnTimeout)
+}
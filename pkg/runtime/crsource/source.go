@@ -0,0 +1,232 @@
+// Package crsource provides the runtimes from the Runtime CRs of infrastructure-manager in the KCP cluster.
+package crsource
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	kebruntime "github.com/kyma-project/kyma-environment-broker/common/runtime"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/kyma-project/kyma-metrics-collector/pkg/config"
+	log "github.com/kyma-project/kyma-metrics-collector/pkg/logger"
+	kmcruntime "github.com/kyma-project/kyma-metrics-collector/pkg/runtime"
+)
+
+const (
+	// labels set by KEB on the Runtime CRs.
+	labelInstanceID      = "kyma-project.io/instance-id"
+	labelRuntimeID       = "kyma-project.io/runtime-id"
+	labelGlobalAccountID = "kyma-project.io/global-account-id"
+	labelSubAccountID    = "kyma-project.io/subaccount-id"
+	labelShootName       = "kyma-project.io/shoot-name"
+	labelRegion          = "kyma-project.io/region"
+	labelPlanID          = "kyma-project.io/broker-plan-id"
+	labelPlanName        = "kyma-project.io/broker-plan-name"
+
+	// providerOpenStack is the Gardener provider type of SAP Converged Cloud.
+	providerOpenStack = "openstack"
+)
+
+// RuntimeGVR is the resource of the Runtime CRs of infrastructure-manager.
+var RuntimeGVR = schema.GroupVersionResource{
+	Group:    "infrastructuremanager.kyma-project.io",
+	Version:  "v1",
+	Resource: "runtimes",
+}
+
+// Source watches the Runtime CRs and passes every added, updated and deleted runtime to the handler, and resyncs the
+// handler with all Runtime CRs once synced and then every resync period.
+// A runtime is billable if the billable flag in the spec says so. Without the flag, a runtime is billable until it is
+// being deleted, so it is still billed while its Runtime CR is not Ready during an update.
+type Source struct {
+	client       dynamic.Interface
+	namespace    string
+	resyncPeriod time.Duration
+	logger       *zap.SugaredLogger
+
+	// handlerMu serializes the calls of the handler from the informer and the resyncs.
+	handlerMu sync.Mutex
+}
+
+var _ kmcruntime.Source = &Source{}
+
+func New(client dynamic.Interface, namespace string, resyncPeriod time.Duration, logger *zap.SugaredLogger) *Source {
+	return &Source{
+		client:       client,
+		namespace:    namespace,
+		resyncPeriod: resyncPeriod,
+		logger:       logger,
+	}
+}
+
+// Start watches the Runtime CRs until the context is done.
+func (s *Source) Start(ctx context.Context, handler kmcruntime.SourceHandler) error {
+	// the informer does not resync, as the handler is resynced with all Runtime CRs instead
+	factory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(s.client, 0, s.namespace, nil)
+	informer := factory.ForResource(RuntimeGVR).Informer()
+
+	_, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj any) {
+			s.update(handler, obj)
+		},
+		UpdateFunc: func(_, obj any) {
+			s.update(handler, obj)
+		},
+		DeleteFunc: func(obj any) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+
+			runtime, err := ToRuntime(obj)
+			if err != nil {
+				s.namedLogger().With(log.KeyResult, log.ValueFail).With(log.KeyError, err.Error()).Error("convert deleted Runtime CR")
+				return
+			}
+
+			s.handlerMu.Lock()
+			defer s.handlerMu.Unlock()
+
+			handler.Delete(runtime)
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add event handler for Runtime CRs: %w", err)
+	}
+
+	factory.Start(ctx.Done())
+
+	if !cache.WaitForCacheSync(ctx.Done(), informer.HasSynced) {
+		return fmt.Errorf("failed to sync the Runtime CRs informer")
+	}
+
+	s.namedLogger().Info("synced Runtime CRs informer")
+	s.resync(handler, informer.GetStore())
+
+	var tick <-chan time.Time
+
+	if s.resyncPeriod > 0 {
+		ticker := time.NewTicker(s.resyncPeriod)
+		defer ticker.Stop()
+
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			factory.Shutdown()
+
+			return nil
+		case <-tick:
+			s.resync(handler, informer.GetStore())
+		}
+	}
+}
+
+// resync passes all Runtime CRs of the store to the handler.
+func (s *Source) resync(handler kmcruntime.SourceHandler, store cache.Store) {
+	objs := store.List()
+	runtimes := make([]kmcruntime.Runtime, 0, len(objs))
+
+	for _, obj := range objs {
+		runtime, err := ToRuntime(obj)
+		if err != nil {
+			s.namedLogger().With(log.KeyResult, log.ValueFail).With(log.KeyError, err.Error()).Error("convert Runtime CR")
+			continue
+		}
+
+		runtimes = append(runtimes, runtime)
+	}
+
+	s.handlerMu.Lock()
+	defer s.handlerMu.Unlock()
+
+	handler.Resync(runtimes)
+}
+
+func (s *Source) update(handler kmcruntime.SourceHandler, obj any) {
+	runtime, err := ToRuntime(obj)
+	if err != nil {
+		s.namedLogger().With(log.KeyResult, log.ValueFail).With(log.KeyError, err.Error()).Error("convert Runtime CR")
+		return
+	}
+
+	s.handlerMu.Lock()
+	defer s.handlerMu.Unlock()
+
+	handler.Update([]kmcruntime.Runtime{runtime})
+}
+
+// ToRuntime converts a Runtime CR to a runtime. The IDs are taken from the labels set by KEB,
+// the shoot name, provider and region from the spec of the shoot.
+func ToRuntime(obj any) (kmcruntime.Runtime, error) {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return kmcruntime.Runtime{}, fmt.Errorf("unexpected object type %T", obj)
+	}
+
+	labels := u.GetLabels()
+
+	shootName, _, _ := unstructured.NestedString(u.Object, "spec", "shoot", "name")
+	if shootName == "" {
+		shootName = labels[labelShootName]
+	}
+
+	region, _, _ := unstructured.NestedString(u.Object, "spec", "shoot", "region")
+	if region == "" {
+		region = labels[labelRegion]
+	}
+
+	provider, _, _ := unstructured.NestedString(u.Object, "spec", "shoot", "provider", "type")
+	if strings.EqualFold(provider, providerOpenStack) {
+		provider = config.CCEE
+	}
+
+	runtimeID := labels[labelRuntimeID]
+	if runtimeID == "" {
+		runtimeID = u.GetName()
+	}
+
+	state, _, _ := unstructured.NestedString(u.Object, "status", "state")
+
+	billable, found, err := unstructured.NestedBool(u.Object, "spec", "billable")
+	if err != nil {
+		return kmcruntime.Runtime{}, fmt.Errorf("invalid billable flag of Runtime CR %s: %w", u.GetName(), err)
+	}
+
+	if !found {
+		billable = u.GetDeletionTimestamp() == nil
+	}
+
+	return kmcruntime.Runtime{
+		RuntimeDTO: kebruntime.RuntimeDTO{
+			InstanceID:      labels[labelInstanceID],
+			RuntimeID:       runtimeID,
+			GlobalAccountID: labels[labelGlobalAccountID],
+			SubAccountID:    labels[labelSubAccountID],
+			ShootName:       shootName,
+			ProviderRegion:  region,
+			Provider:        provider,
+			ServicePlanID:   labels[labelPlanID],
+			ServicePlanName: labels[labelPlanName],
+			Status: kebruntime.RuntimeStatus{
+				CreatedAt: u.GetCreationTimestamp().Time,
+				State:     kebruntime.State(state),
+			},
+		},
+		Billable: &billable,
+	}, nil
+}
+
+func (s *Source) namedLogger() *zap.SugaredLogger {
+	return s.logger.With("component", "runtime-cr-source")
+}
@@ -0,0 +1,209 @@
+package crsource
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+
+	"github.com/kyma-project/kyma-metrics-collector/pkg/logger"
+	kmcruntime "github.com/kyma-project/kyma-metrics-collector/pkg/runtime"
+)
+
+const namespace = "kcp-system"
+
+func newRuntimeCR(name, state string, billable *bool) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": RuntimeGVR.GroupVersion().String(),
+		"kind":       "Runtime",
+		"metadata": map[string]any{
+			"name":      name,
+			"namespace": namespace,
+			"labels": map[string]any{
+				labelInstanceID:      "instance-" + name,
+				labelGlobalAccountID: "global-account",
+				labelSubAccountID:    "subaccount-" + name,
+				labelPlanName:        "aws",
+				labelRegion:          "eu-central-1",
+			},
+		},
+		"spec": map[string]any{
+			"shoot": map[string]any{
+				"name":     "shoot-" + name,
+				"region":   "eu-central-1",
+				"provider": map[string]any{"type": "aws"},
+			},
+		},
+		"status": map[string]any{"state": state},
+	}}
+
+	if billable != nil {
+		obj.Object["spec"].(map[string]any)["billable"] = *billable
+	}
+
+	return obj
+}
+
+func TestToRuntime(t *testing.T) {
+	billable := true
+	notBillable := false
+	deleted := newRuntimeCR("deleted", "Ready", nil)
+	deleted.SetDeletionTimestamp(&metav1.Time{Time: time.Now()})
+
+	billableDeleted := newRuntimeCR("flag", "Terminating", &billable)
+	billableDeleted.SetDeletionTimestamp(&metav1.Time{Time: time.Now()})
+
+	openStack := newRuntimeCR("openstack", "Ready", nil)
+	openStack.Object["spec"].(map[string]any)["shoot"].(map[string]any)["provider"] = map[string]any{"type": "openstack"}
+
+	testCases := []struct {
+		name             string
+		obj              *unstructured.Unstructured
+		expectedBillable bool
+		expectedProvider string
+	}{
+		{name: "ready", obj: newRuntimeCR("ready", "Ready", nil), expectedBillable: true, expectedProvider: "aws"},
+		{name: "pending during an update", obj: newRuntimeCR("pending", "Pending", nil), expectedBillable: true, expectedProvider: "aws"},
+		{name: "failed", obj: newRuntimeCR("failed", "Failed", nil), expectedBillable: true, expectedProvider: "aws"},
+		{name: "being deleted", obj: deleted, expectedProvider: "aws"},
+		{name: "billable flag overrides deletion", obj: billableDeleted, expectedBillable: true, expectedProvider: "aws"},
+		{name: "not billable flag overrides state", obj: newRuntimeCR("noflag", "Ready", &notBillable), expectedProvider: "aws"},
+		{name: "openstack is SAP Converged Cloud", obj: openStack, expectedBillable: true, expectedProvider: "sapconvergedcloud"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			runtime, err := ToRuntime(tc.obj)
+			require.NoError(t, err)
+			require.NotNil(t, runtime.Billable)
+			require.Equal(t, tc.expectedBillable, *runtime.Billable)
+			require.Equal(t, tc.expectedProvider, runtime.Provider)
+			require.Equal(t, "subaccount-"+tc.obj.GetName(), runtime.SubAccountID)
+			require.Equal(t, "instance-"+tc.obj.GetName(), runtime.InstanceID)
+			require.Equal(t, tc.obj.GetName(), runtime.RuntimeID)
+			require.Equal(t, "shoot-"+tc.obj.GetName(), runtime.ShootName)
+			require.Equal(t, "eu-central-1", runtime.ProviderRegion)
+			require.Equal(t, "aws", runtime.ServicePlanName)
+		})
+	}
+
+	_, err := ToRuntime("not a runtime")
+	require.Error(t, err)
+}
+
+type recordingHandler struct {
+	mu      sync.Mutex
+	resyncs [][]kmcruntime.Runtime
+	updated []kmcruntime.Runtime
+	deleted []kmcruntime.Runtime
+}
+
+func (h *recordingHandler) Resync(runtimes []kmcruntime.Runtime) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.resyncs = append(h.resyncs, runtimes)
+}
+
+func (h *recordingHandler) Update(runtimes []kmcruntime.Runtime) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.updated = append(h.updated, runtimes...)
+}
+
+func (h *recordingHandler) Delete(runtime kmcruntime.Runtime) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.deleted = append(h.deleted, runtime)
+}
+
+func (h *recordingHandler) resyncCount() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return len(h.resyncs)
+}
+
+func (h *recordingHandler) counts() (int, int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return len(h.updated), len(h.deleted)
+}
+
+func TestSource(t *testing.T) {
+	existing := newRuntimeCR("existing", "Ready", nil)
+	client := fake.NewSimpleDynamicClientWithCustomListKinds(k8sruntime.NewScheme(),
+		map[schema.GroupVersionResource]string{RuntimeGVR: "RuntimeList"}, existing)
+
+	source := New(client, namespace, 0, logger.NewLogger(zapcore.InfoLevel))
+	handler := &recordingHandler{}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		_ = source.Start(ctx, handler)
+	}()
+
+	// the existing Runtime CR is delivered as added, and the handler is resynced once the informer is synced
+	require.Eventually(t, func() bool {
+		updated, _ := handler.counts()
+		return updated == 1 && handler.resyncCount() == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	added := newRuntimeCR("added", "Pending", nil)
+	_, err := client.Resource(RuntimeGVR).Namespace(namespace).Create(ctx, added, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		updated, _ := handler.counts()
+		return updated == 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	err = client.Resource(RuntimeGVR).Namespace(namespace).Delete(ctx, "existing", metav1.DeleteOptions{})
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		_, deleted := handler.counts()
+		return deleted == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	handler.mu.Lock()
+	defer handler.mu.Unlock()
+
+	require.Len(t, handler.resyncs[0], 1)
+	require.Equal(t, "subaccount-existing", handler.resyncs[0][0].SubAccountID)
+	require.True(t, *handler.updated[0].Billable)
+	require.True(t, *handler.updated[1].Billable)
+	require.Equal(t, "subaccount-existing", handler.deleted[0].SubAccountID)
+}
+
+func TestSource_PeriodicResync(t *testing.T) {
+	client := fake.NewSimpleDynamicClientWithCustomListKinds(k8sruntime.NewScheme(),
+		map[schema.GroupVersionResource]string{RuntimeGVR: "RuntimeList"}, newRuntimeCR("existing", "Ready", nil))
+
+	source := New(client, namespace, 50*time.Millisecond, logger.NewLogger(zapcore.InfoLevel))
+	handler := &recordingHandler{}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		_ = source.Start(ctx, handler)
+	}()
+
+	require.Eventually(t, func() bool {
+		return handler.resyncCount() >= 3
+	}, 5*time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,224 @@
+// Package filesource provides the runtimes from a static YAML or JSON file, e.g. for local development
+// or to run KMC against a few clusters without KEB.
+package filesource
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	kebruntime "github.com/kyma-project/kyma-environment-broker/common/runtime"
+	"go.uber.org/zap"
+	"sigs.k8s.io/yaml"
+
+	log "github.com/kyma-project/kyma-metrics-collector/pkg/logger"
+	kmcruntime "github.com/kyma-project/kyma-metrics-collector/pkg/runtime"
+	"github.com/kyma-project/kyma-metrics-collector/pkg/runtime/kubeconfigprovider"
+)
+
+// ErrNotFound is returned for the kubeconfig of a runtime which is not listed in the file.
+// It wraps kubeconfigprovider.ErrNotFound, so it is handled like a missing kubeconfig secret.
+var ErrNotFound = fmt.Errorf("runtime not found in runtimes file: %w", kubeconfigprovider.ErrNotFound)
+
+// Runtimes is the content of a runtimes file.
+type Runtimes struct {
+	Runtimes []Runtime `json:"runtimes"`
+}
+
+// Runtime is a runtime listed in a runtimes file. Relative kubeconfig paths are resolved from the directory of the file.
+type Runtime struct {
+	SubAccountID    string `json:"subAccountID"`
+	GlobalAccountID string `json:"globalAccountID"`
+	RuntimeID       string `json:"runtimeID"`
+	InstanceID      string `json:"instanceID,omitempty"`
+	ShootName       string `json:"shootName"`
+	Provider        string `json:"provider"`
+	Region          string `json:"region,omitempty"`
+	Plan            string `json:"plan,omitempty"`
+	KubeconfigPath  string `json:"kubeconfigPath"`
+}
+
+// Source reads the runtimes from a file and passes them to the handler as a full list on start and on every change
+// of the file. If a changed file is invalid, the previous runtimes are kept.
+// It also provides the kubeconfigs of the runtimes from the paths listed in the file.
+type Source struct {
+	path         string
+	pollInterval time.Duration
+	logger       *zap.SugaredLogger
+
+	mu              sync.RWMutex
+	kubeconfigPaths map[string]string
+}
+
+var (
+	_ kmcruntime.Source         = &Source{}
+	_ kmcruntime.ConfigProvider = &Source{}
+)
+
+func New(path string, pollInterval time.Duration, logger *zap.SugaredLogger) *Source {
+	return &Source{
+		path:            path,
+		pollInterval:    pollInterval,
+		logger:          logger,
+		kubeconfigPaths: make(map[string]string),
+	}
+}
+
+// Start reads the file and checks it for changes every poll interval until the context is done.
+// It fails if the file cannot be read on start.
+func (s *Source) Start(ctx context.Context, handler kmcruntime.SourceHandler) error {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return fmt.Errorf("failed to read runtimes file: %w", err)
+	}
+
+	if err := s.load(data, handler); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		changed, err := os.ReadFile(s.path)
+		if err != nil {
+			s.namedLogger().With(log.KeyResult, log.ValueFail).With(log.KeyError, err.Error()).
+				Error("read runtimes file, keeping the previous runtimes")
+
+			continue
+		}
+
+		if bytes.Equal(changed, data) {
+			continue
+		}
+
+		data = changed
+		if err := s.load(data, handler); err != nil {
+			s.namedLogger().With(log.KeyResult, log.ValueFail).With(log.KeyError, err.Error()).
+				Error("reload runtimes file, keeping the previous runtimes")
+
+			continue
+		}
+
+		s.namedLogger().Info("reloaded runtimes file")
+	}
+}
+
+func (s *Source) load(data []byte, handler kmcruntime.SourceHandler) error {
+	runtimes, err := Parse(data)
+	if err != nil {
+		return err
+	}
+
+	kubeconfigPaths := make(map[string]string, len(runtimes.Runtimes))
+	converted := make([]kmcruntime.Runtime, 0, len(runtimes.Runtimes))
+
+	for _, runtime := range runtimes.Runtimes {
+		kubeconfigPath := runtime.KubeconfigPath
+		if !filepath.IsAbs(kubeconfigPath) {
+			kubeconfigPath = filepath.Join(filepath.Dir(s.path), kubeconfigPath)
+		}
+
+		kubeconfigPaths[runtime.RuntimeID] = kubeconfigPath
+		converted = append(converted, runtime.toRuntime())
+	}
+
+	s.mu.Lock()
+	s.kubeconfigPaths = kubeconfigPaths
+	s.mu.Unlock()
+
+	handler.Resync(converted)
+
+	return nil
+}
+
+// Get returns the kubeconfig of the runtime from the path listed in the file.
+// The kubeconfig is read on every call, so rotated kubeconfigs are used without reloading the runtimes file.
+func (s *Source) Get(runtimeID string) ([]byte, error) {
+	s.mu.RLock()
+	kubeconfigPath, ok := s.kubeconfigPaths[runtimeID]
+	s.mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, runtimeID)
+	}
+
+	kubeconfig, err := os.ReadFile(kubeconfigPath)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to read kubeconfig of runtime %s: %w", kubeconfigprovider.ErrTransient, runtimeID, err)
+	}
+
+	return kubeconfig, nil
+}
+
+// Parse parses and validates the content of a runtimes file in YAML or JSON format.
+func Parse(data []byte) (*Runtimes, error) {
+	var runtimes Runtimes
+	if err := yaml.UnmarshalStrict(data, &runtimes); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal runtimes file: %w", err)
+	}
+
+	subAccounts := make(map[string]struct{}, len(runtimes.Runtimes))
+	runtimeIDs := make(map[string]struct{}, len(runtimes.Runtimes))
+
+	for i, runtime := range runtimes.Runtimes {
+		switch {
+		case runtime.SubAccountID == "":
+			return nil, fmt.Errorf("runtime %d does not define a subAccountID", i)
+		case runtime.RuntimeID == "":
+			return nil, fmt.Errorf("runtime %d does not define a runtimeID", i)
+		case runtime.ShootName == "":
+			return nil, fmt.Errorf("runtime %d does not define a shootName", i)
+		case runtime.Provider == "":
+			return nil, fmt.Errorf("runtime %d does not define a provider", i)
+		case runtime.KubeconfigPath == "":
+			return nil, fmt.Errorf("runtime %d does not define a kubeconfigPath", i)
+		}
+
+		if _, ok := subAccounts[runtime.SubAccountID]; ok {
+			return nil, fmt.Errorf("subAccountID %s is listed more than once", runtime.SubAccountID)
+		}
+
+		if _, ok := runtimeIDs[runtime.RuntimeID]; ok {
+			return nil, fmt.Errorf("runtimeID %s is listed more than once", runtime.RuntimeID)
+		}
+
+		subAccounts[runtime.SubAccountID] = struct{}{}
+		runtimeIDs[runtime.RuntimeID] = struct{}{}
+	}
+
+	return &runtimes, nil
+}
+
+// toRuntime converts the listed runtime. Listed runtimes are always billable.
+func (r Runtime) toRuntime() kmcruntime.Runtime {
+	billable := true
+
+	return kmcruntime.Runtime{
+		RuntimeDTO: kebruntime.RuntimeDTO{
+			InstanceID:      r.InstanceID,
+			RuntimeID:       r.RuntimeID,
+			GlobalAccountID: r.GlobalAccountID,
+			SubAccountID:    r.SubAccountID,
+			ShootName:       r.ShootName,
+			Provider:        r.Provider,
+			ProviderRegion:  r.Region,
+			ServicePlanName: r.Plan,
+		},
+		Billable: &billable,
+	}
+}
+
+func (s *Source) namedLogger() *zap.SugaredLogger {
+	return s.logger.With("component", "runtime-file-source")
+}
@@ -0,0 +1,167 @@
+package filesource
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/kyma-project/kyma-metrics-collector/pkg/logger"
+	kmcruntime "github.com/kyma-project/kyma-metrics-collector/pkg/runtime"
+)
+
+const runtimesYAML = `runtimes:
+- subAccountID: subaccount-1
+  globalAccountID: global-account
+  runtimeID: runtime-1
+  shootName: shoot-1
+  provider: aws
+  region: eu-central-1
+  kubeconfigPath: kubeconfig-1
+`
+
+const runtimesJSON = `{"runtimes": [
+  {"subAccountID": "subaccount-1", "runtimeID": "runtime-1", "shootName": "shoot-1", "provider": "aws", "kubeconfigPath": "kubeconfig-1"},
+  {"subAccountID": "subaccount-2", "runtimeID": "runtime-2", "shootName": "shoot-2", "provider": "gcp", "kubeconfigPath": "/abs/kubeconfig-2"}
+]}`
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		name          string
+		data          string
+		expectedCount int
+		expectedErr   string
+	}{
+		{name: "yaml", data: runtimesYAML, expectedCount: 1},
+		{name: "json", data: runtimesJSON, expectedCount: 2},
+		{name: "empty", data: "runtimes: []", expectedCount: 0},
+		{name: "unknown field", data: "runtimes:\n- subAccountID: a\n  unknown: b\n", expectedErr: "failed to unmarshal"},
+		{
+			name:        "missing kubeconfig path",
+			data:        "runtimes:\n- {subAccountID: a, runtimeID: r, shootName: s, provider: aws}\n",
+			expectedErr: "runtime 0 does not define a kubeconfigPath",
+		},
+		{
+			name: "duplicate subaccount",
+			data: "runtimes:\n- {subAccountID: a, runtimeID: r1, shootName: s, provider: aws, kubeconfigPath: k}\n" +
+				"- {subAccountID: a, runtimeID: r2, shootName: s, provider: aws, kubeconfigPath: k}\n",
+			expectedErr: "subAccountID a is listed more than once",
+		},
+		{
+			name: "duplicate runtime ID",
+			data: "runtimes:\n- {subAccountID: a, runtimeID: r, shootName: s, provider: aws, kubeconfigPath: k}\n" +
+				"- {subAccountID: b, runtimeID: r, shootName: s, provider: aws, kubeconfigPath: k}\n",
+			expectedErr: "runtimeID r is listed more than once",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			runtimes, err := Parse([]byte(tc.data))
+			if tc.expectedErr != "" {
+				require.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Len(t, runtimes.Runtimes, tc.expectedCount)
+		})
+	}
+}
+
+type recordingHandler struct {
+	mu       sync.Mutex
+	resynced [][]kmcruntime.Runtime
+}
+
+func (h *recordingHandler) Resync(runtimes []kmcruntime.Runtime) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.resynced = append(h.resynced, runtimes)
+}
+
+func (h *recordingHandler) Update([]kmcruntime.Runtime) {}
+
+func (h *recordingHandler) Delete(kmcruntime.Runtime) {}
+
+func (h *recordingHandler) last() (int, []kmcruntime.Runtime) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.resynced) == 0 {
+		return 0, nil
+	}
+
+	return len(h.resynced), h.resynced[len(h.resynced)-1]
+}
+
+func TestSource(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "runtimes.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(runtimesYAML), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "kubeconfig-1"), []byte("kubeconfig"), 0o600))
+
+	source := New(path, 10*time.Millisecond, logger.NewLogger(zapcore.InfoLevel))
+	handler := &recordingHandler{}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		_ = source.Start(ctx, handler)
+	}()
+
+	require.Eventually(t, func() bool {
+		count, _ := handler.last()
+		return count == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	_, runtimes := handler.last()
+	require.Len(t, runtimes, 1)
+	require.Equal(t, "subaccount-1", runtimes[0].SubAccountID)
+	require.Equal(t, "eu-central-1", runtimes[0].ProviderRegion)
+	require.True(t, *runtimes[0].Billable)
+
+	// relative kubeconfig paths are resolved from the directory of the runtimes file
+	kubeconfig, err := source.Get("runtime-1")
+	require.NoError(t, err)
+	require.Equal(t, "kubeconfig", string(kubeconfig))
+
+	_, err = source.Get("unknown")
+	require.ErrorIs(t, err, ErrNotFound)
+
+	// an invalid file keeps the previous runtimes
+	require.NoError(t, os.WriteFile(path, []byte("runtimes: [{subAccountID: a}]"), 0o600))
+	require.Never(t, func() bool {
+		count, _ := handler.last()
+		return count > 1
+	}, 100*time.Millisecond, 10*time.Millisecond)
+
+	_, err = source.Get("runtime-1")
+	require.NoError(t, err)
+
+	// a valid change is passed as full list
+	require.NoError(t, os.WriteFile(path, []byte(runtimesJSON), 0o600))
+	require.Eventually(t, func() bool {
+		count, _ := handler.last()
+		return count == 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	_, runtimes = handler.last()
+	require.Len(t, runtimes, 2)
+
+	_, err = source.Get("runtime-2")
+	require.ErrorContains(t, err, "/abs/kubeconfig-2")
+}
+
+func TestSourceMissingFile(t *testing.T) {
+	source := New(filepath.Join(t.TempDir(), "missing.yaml"), time.Second, logger.NewLogger(zapcore.InfoLevel))
+	err := source.Start(context.Background(), &recordingHandler{})
+	require.ErrorContains(t, err, "failed to read runtimes file")
+}
@@ -12,6 +12,12 @@ type ConfigProvider interface {
 	Get(runtimeID string) ([]byte, error)
 }
 
+// ConfigInvalidator is implemented by the ConfigProviders which cache kubeconfigs, so a kubeconfig whose credentials
+// were rejected by the runtime can be dropped and loaded again.
+type ConfigInvalidator interface {
+	Invalidate(runtimeID string)
+}
+
 type ClientFactory interface {
 	NewClients(config *rest.Config) (InterfaceCloser, error)
 }
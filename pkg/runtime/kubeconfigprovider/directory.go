@@ -0,0 +1,65 @@
+package kubeconfigprovider
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// kubeconfigFileExtension is the extension of the kubeconfig files in the directory of a DirectoryProvider.
+const kubeconfigFileExtension = ".yaml"
+
+// DirectoryProvider provides the kubeconfigs from a local directory containing a <runtimeID>.yaml file per runtime,
+// e.g. a mounted secret or a directory synced by an external tool. The files are read on every Get, so rotated
+// kubeconfigs are used as soon as the file changes.
+type DirectoryProvider struct {
+	dir  string
+	name string
+}
+
+// NewDirectory creates a DirectoryProvider reading the kubeconfig files from dir.
+// name is used to identify the provider in the metrics.
+func NewDirectory(dir, name string) *DirectoryProvider {
+	return &DirectoryProvider{dir: dir, name: name}
+}
+
+// Get returns the content of the kubeconfig file of the runtime.
+func (d *DirectoryProvider) Get(runtimeID string) ([]byte, error) {
+	kubeconfig, err := d.get(runtimeID)
+	if err != nil {
+		recordFailure(d.name, err)
+
+		return nil, err
+	}
+
+	return kubeconfig, nil
+}
+
+func (d *DirectoryProvider) get(runtimeID string) ([]byte, error) {
+	// the runtime ID must not escape the directory
+	if runtimeID == "" || strings.ContainsAny(runtimeID, `/\`) || runtimeID == "." || runtimeID == ".." {
+		return nil, fmt.Errorf("%w: invalid runtime ID %q for kubeconfig file", ErrNotFound, runtimeID)
+	}
+
+	path := filepath.Join(d.dir, runtimeID+kubeconfigFileExtension)
+
+	kubeconfig, err := os.ReadFile(path)
+
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, runtimeID)
+	case errors.Is(err, fs.ErrPermission):
+		return nil, fmt.Errorf("%w: %w", ErrForbidden, err)
+	case err != nil:
+		return nil, fmt.Errorf("%w: failed to read kubeconfig file of runtime %s: %w", ErrTransient, runtimeID, err)
+	}
+
+	if len(kubeconfig) == 0 {
+		return nil, fmt.Errorf("%w: kubeconfig file '%s' for runtime '%s' is empty", ErrInvalid, path, runtimeID)
+	}
+
+	return kubeconfig, nil
+}
@@ -0,0 +1,39 @@
+package kubeconfigprovider
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDirectoryProvider_Get(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "test.yaml"), []byte("test"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "empty.yaml"), nil, 0o600))
+
+	provider := NewDirectory(dir, "test")
+
+	got, err := provider.Get("test")
+	require.NoError(t, err)
+	require.Equal(t, []byte("test"), got)
+
+	_, err = provider.Get("empty")
+	require.ErrorContains(t, err, "is empty")
+
+	_, err = provider.Get("missing")
+	require.ErrorIs(t, err, ErrNotFound)
+
+	for _, runtimeID := range []string{"", ".", "..", "../test", `..\test`} {
+		_, err = provider.Get(runtimeID)
+		require.ErrorContains(t, err, "invalid runtime ID", runtimeID)
+	}
+
+	// a rotated kubeconfig is used as soon as the file changes
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "test.yaml"), []byte("rotated"), 0o600))
+
+	got, err = provider.Get("test")
+	require.NoError(t, err)
+	require.Equal(t, []byte("rotated"), got)
+}
@@ -0,0 +1,56 @@
+package kubeconfigprovider
+
+import (
+	"errors"
+	"fmt"
+
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// The errors returned by the kubeconfig providers wrap one of these errors, so callers can tell a missing
+// kubeconfig from a misconfiguration or a failure which is worth retrying.
+var (
+	// ErrNotFound is returned if there is no kubeconfig for the runtime.
+	ErrNotFound = errors.New("kubeconfig not found")
+	// ErrForbidden is returned if KMC is not allowed to read the kubeconfig of the runtime.
+	ErrForbidden = errors.New("access to kubeconfig forbidden")
+	// ErrInvalid is returned if the kubeconfig of the runtime exists but is unusable, e.g. empty.
+	ErrInvalid = errors.New("invalid kubeconfig")
+	// ErrTransient is returned for all other failures, e.g. timeouts of the API server.
+	ErrTransient = errors.New("transient failure loading kubeconfig")
+)
+
+// Reasons of failures returned by FailureReason and recorded in the reason label of the failures metric.
+const (
+	FailureReasonNotFound  = "not_found"
+	FailureReasonForbidden = "forbidden"
+	FailureReasonInvalid   = "invalid"
+	FailureReasonTransient = "transient"
+)
+
+// FailureReason returns the reason of an error returned by a kubeconfig provider.
+// Errors which do not wrap any of the typed errors are treated as transient.
+func FailureReason(err error) string {
+	switch {
+	case errors.Is(err, ErrNotFound):
+		return FailureReasonNotFound
+	case errors.Is(err, ErrForbidden):
+		return FailureReasonForbidden
+	case errors.Is(err, ErrInvalid):
+		return FailureReasonInvalid
+	default:
+		return FailureReasonTransient
+	}
+}
+
+// classifyAPIError wraps an error of the Kubernetes API in the matching typed error.
+func classifyAPIError(err error) error {
+	switch {
+	case k8serr.IsNotFound(err):
+		return fmt.Errorf("%w: %w", ErrNotFound, err)
+	case k8serr.IsForbidden(err), k8serr.IsUnauthorized(err):
+		return fmt.Errorf("%w: %w", ErrForbidden, err)
+	default:
+		return fmt.Errorf("%w: %w", ErrTransient, err)
+	}
+}
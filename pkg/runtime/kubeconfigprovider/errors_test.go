@@ -0,0 +1,32 @@
+package kubeconfigprovider
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestFailureReason(t *testing.T) {
+	secrets := schema.GroupResource{Resource: "secrets"}
+
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{err: fmt.Errorf("%w: test", ErrNotFound), want: FailureReasonNotFound},
+		{err: classifyAPIError(k8serr.NewNotFound(secrets, "kubeconfig-test")), want: FailureReasonNotFound},
+		{err: classifyAPIError(k8serr.NewForbidden(secrets, "kubeconfig-test", errors.New("rbac"))), want: FailureReasonForbidden},
+		{err: classifyAPIError(k8serr.NewUnauthorized("expired")), want: FailureReasonForbidden},
+		{err: classifyAPIError(k8serr.NewTimeoutError("timeout", 1)), want: FailureReasonTransient},
+		{err: fmt.Errorf("%w: empty", ErrInvalid), want: FailureReasonInvalid},
+		{err: errors.New("unknown"), want: FailureReasonTransient},
+	}
+
+	for _, tc := range tests {
+		require.Equal(t, tc.want, FailureReason(tc.err), tc.err.Error())
+	}
+}
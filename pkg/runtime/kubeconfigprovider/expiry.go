@@ -0,0 +1,87 @@
+package kubeconfigprovider
+
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"strings"
+	"time"
+
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// expiryRefreshMargin is the time before the credentials of a kubeconfig expire in which the kubeconfig is loaded
+// again, so a rotated kubeconfig is used before the old one is rejected by the runtime.
+const expiryRefreshMargin = 10 * time.Minute
+
+// kubeconfigExpiry returns when the credentials of the current context of the kubeconfig expire, i.e. the earliest
+// expiry of its client certificate and its token. It returns false if the kubeconfig cannot be parsed or its
+// credentials do not expire, e.g. tokens which are no JWTs.
+func kubeconfigExpiry(kubeconfig []byte) (time.Time, bool) {
+	config, err := clientcmd.Load(kubeconfig)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	kubeContext, found := config.Contexts[config.CurrentContext]
+	if !found {
+		return time.Time{}, false
+	}
+
+	authInfo, found := config.AuthInfos[kubeContext.AuthInfo]
+	if !found {
+		return time.Time{}, false
+	}
+
+	var expiry time.Time
+
+	if notAfter, ok := certificateExpiry(authInfo.ClientCertificateData); ok {
+		expiry = notAfter
+	}
+
+	if exp, ok := tokenExpiry(authInfo.Token); ok && (expiry.IsZero() || exp.Before(expiry)) {
+		expiry = exp
+	}
+
+	return expiry, !expiry.IsZero()
+}
+
+// certificateExpiry returns the NotAfter of the first certificate in the PEM data.
+func certificateExpiry(data []byte) (time.Time, bool) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return time.Time{}, false
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return cert.NotAfter, true
+}
+
+// tokenExpiry returns the exp claim of a JWT. The signature is not verified, as the token is only used to decide
+// when to load the kubeconfig again.
+func tokenExpiry(token string) (time.Time, bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}, false
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Exp == 0 {
+		return time.Time{}, false
+	}
+
+	return time.Unix(claims.Exp, 0), true
+}
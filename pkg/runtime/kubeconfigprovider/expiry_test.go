@@ -0,0 +1,140 @@
+package kubeconfigprovider
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+func newCertificate(t *testing.T, notAfter time.Time) []byte {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "kmc"},
+		NotBefore:    notAfter.Add(-24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func newToken(exp time.Time) string {
+	encode := base64.RawURLEncoding.EncodeToString
+
+	return encode([]byte(`{"alg":"none"}`)) + "." + encode(fmt.Appendf(nil, `{"exp":%d}`, exp.Unix())) + ".signature"
+}
+
+func newKubeconfig(t *testing.T, authInfo *clientcmdapi.AuthInfo) []byte {
+	t.Helper()
+
+	config := clientcmdapi.NewConfig()
+	config.Clusters["skr"] = &clientcmdapi.Cluster{Server: "https://skr.example.com"}
+	config.AuthInfos["skr"] = authInfo
+	config.Contexts["skr"] = &clientcmdapi.Context{Cluster: "skr", AuthInfo: "skr"}
+	config.CurrentContext = "skr"
+
+	kubeconfig, err := clientcmd.Write(*config)
+	require.NoError(t, err)
+
+	return kubeconfig
+}
+
+func TestKubeconfigExpiry(t *testing.T) {
+	certExpiry := time.Now().Add(2 * time.Hour).Truncate(time.Second).UTC()
+	tokenExp := time.Now().Add(time.Hour).Truncate(time.Second)
+
+	tests := []struct {
+		name       string
+		kubeconfig []byte
+		wantExpiry time.Time
+		wantFound  bool
+	}{
+		{
+			name:       "client certificate",
+			kubeconfig: newKubeconfig(t, &clientcmdapi.AuthInfo{ClientCertificateData: newCertificate(t, certExpiry)}),
+			wantExpiry: certExpiry,
+			wantFound:  true,
+		},
+		{
+			name:       "JWT",
+			kubeconfig: newKubeconfig(t, &clientcmdapi.AuthInfo{Token: newToken(tokenExp)}),
+			wantExpiry: tokenExp,
+			wantFound:  true,
+		},
+		{
+			name: "earliest of certificate and JWT",
+			kubeconfig: newKubeconfig(t, &clientcmdapi.AuthInfo{
+				ClientCertificateData: newCertificate(t, certExpiry),
+				Token:                 newToken(tokenExp),
+			}),
+			wantExpiry: tokenExp,
+			wantFound:  true,
+		},
+		{
+			name:       "opaque token",
+			kubeconfig: newKubeconfig(t, &clientcmdapi.AuthInfo{Token: "token"}),
+		},
+		{
+			name:       "invalid kubeconfig",
+			kubeconfig: []byte("invalid"),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			expiry, found := kubeconfigExpiry(tc.kubeconfig)
+			require.Equal(t, tc.wantFound, found)
+			require.True(t, tc.wantExpiry.Equal(expiry), "got %s, want %s", expiry, tc.wantExpiry)
+		})
+	}
+}
+
+func TestKubeconfigProvider_GetExpiringKubeconfig(t *testing.T) {
+	expiring := newKubeconfig(t, &clientcmdapi.AuthInfo{Token: newToken(time.Now().Add(20 * time.Minute))})
+	expired := newKubeconfig(t, &clientcmdapi.AuthInfo{Token: newToken(time.Now().Add(-time.Minute))})
+
+	cs := fake.NewClientset(
+		newKubeconfigSecret("kubeconfig-expiring", string(expiring)),
+		newKubeconfigSecret("kubeconfig-expired", string(expired)),
+	)
+	provider := New(cs.CoreV1(), DefaultSecretConfig(), zap.NewExample().Sugar(), time.Hour, time.Minute, "expiry")
+
+	// the kubeconfig is cached only until the refresh margin before its token expires
+	got, err := provider.Get("expiring")
+	require.NoError(t, err)
+	require.Equal(t, expiring, got)
+
+	item := provider.cache.Get("expiring")
+	require.NotNil(t, item)
+	require.WithinDuration(t, time.Now().Add(20*time.Minute-expiryRefreshMargin), item.ExpiresAt(), 5*time.Second)
+
+	// an expired kubeconfig is returned but not cached
+	got, err = provider.Get("expired")
+	require.NoError(t, err)
+	require.Equal(t, expired, got)
+	require.Nil(t, provider.cache.Get("expired"))
+
+	provider.Invalidate("expiring")
+	require.Nil(t, provider.cache.Get("expiring"))
+}
@@ -0,0 +1,185 @@
+package kubeconfigprovider
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/kyma-project/kyma-metrics-collector/pkg/gardener"
+)
+
+const (
+	// ShootRuntimeIDLabel is the label of the Gardener shoots containing the ID of the runtime.
+	ShootRuntimeIDLabel = "kyma-project.io/runtime-id"
+
+	adminKubeconfigSubresource = "adminkubeconfig"
+	// gardenerRefreshFraction is the fraction of the expiration before a kubeconfig expires in which a new one is requested.
+	gardenerRefreshFraction = 5
+)
+
+// GardenerProvider provides short-lived admin kubeconfigs of the runtimes requested from the adminkubeconfig
+// subresource of their Gardener shoots. The shoot of a runtime is found by the ShootRuntimeIDLabel.
+// The kubeconfigs are cached until shortly before they expire.
+type GardenerProvider struct {
+	client     dynamic.Interface
+	namespace  string
+	expiration time.Duration
+	logger     *zap.SugaredLogger
+	name       string
+	now        func() time.Time
+
+	mu          sync.Mutex
+	kubeconfigs map[string]gardenerKubeconfig
+}
+
+type gardenerKubeconfig struct {
+	kubeconfig []byte
+	refreshAt  time.Time
+}
+
+// NewGardener creates a GardenerProvider requesting kubeconfigs valid for expiration for the shoots in the
+// namespace of the Gardener project. name is used to identify the provider in the metrics.
+func NewGardener(client dynamic.Interface, namespace string, expiration time.Duration, logger *zap.SugaredLogger, name string) *GardenerProvider {
+	return &GardenerProvider{
+		client:      client,
+		namespace:   namespace,
+		expiration:  expiration,
+		logger:      logger,
+		name:        name,
+		now:         time.Now,
+		kubeconfigs: make(map[string]gardenerKubeconfig),
+	}
+}
+
+// Get returns the cached kubeconfig of the runtime, or requests a new one if it is missing or about to expire.
+func (g *GardenerProvider) Get(runtimeID string) ([]byte, error) {
+	kubeconfig, err := g.get(runtimeID)
+	if err != nil {
+		recordFailure(g.name, err)
+
+		return nil, err
+	}
+
+	return kubeconfig, nil
+}
+
+func (g *GardenerProvider) get(runtimeID string) ([]byte, error) {
+	g.mu.Lock()
+	cached, found := g.kubeconfigs[runtimeID]
+	g.mu.Unlock()
+
+	if found && g.now().Before(cached.refreshAt) {
+		return cached.kubeconfig, nil
+	}
+
+	ctx := context.Background()
+
+	shootName, err := g.shootName(ctx, runtimeID)
+	if err != nil {
+		return nil, err
+	}
+
+	kubeconfig, expiresAt, err := g.requestAdminKubeconfig(ctx, shootName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to request admin kubeconfig of shoot %s for runtime %s: %w", shootName, runtimeID, err)
+	}
+
+	g.logger.Debugf("requested admin kubeconfig of shoot %s for runtime %s expiring at %s", shootName, runtimeID, expiresAt)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.kubeconfigs[runtimeID] = gardenerKubeconfig{
+		kubeconfig: kubeconfig,
+		refreshAt:  expiresAt.Add(-g.expiration / gardenerRefreshFraction),
+	}
+	g.deleteExpiredLocked()
+	cacheSizeMetric.With(prometheus.Labels{"name": g.name}).Set(float64(len(g.kubeconfigs)))
+
+	return kubeconfig, nil
+}
+
+// Invalidate removes the cached kubeconfig of the runtime, e.g. because the runtime rejected its credentials,
+// so a new one is requested with the next Get.
+func (g *GardenerProvider) Invalidate(runtimeID string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	delete(g.kubeconfigs, runtimeID)
+}
+
+// shootName returns the name of the shoot labeled with the runtime ID.
+func (g *GardenerProvider) shootName(ctx context.Context, runtimeID string) (string, error) {
+	shoots, err := g.client.Resource(gardener.ShootGVR).Namespace(g.namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: ShootRuntimeIDLabel + "=" + runtimeID,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to list shoots of runtime %s: %w", runtimeID, classifyAPIError(err))
+	}
+
+	switch len(shoots.Items) {
+	case 0:
+		return "", fmt.Errorf("%w: %s", ErrNotFound, runtimeID)
+	case 1:
+		return shoots.Items[0].GetName(), nil
+	default:
+		return "", fmt.Errorf("%w: found %d shoots of runtime %s", ErrInvalid, len(shoots.Items), runtimeID)
+	}
+}
+
+// requestAdminKubeconfig creates an AdminKubeconfigRequest for the shoot and returns the kubeconfig and its expiration.
+func (g *GardenerProvider) requestAdminKubeconfig(ctx context.Context, shootName string) ([]byte, time.Time, error) {
+	request := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "authentication.gardener.cloud/v1alpha1",
+		"kind":       "AdminKubeconfigRequest",
+		// the dynamic client creates the subresource of the object with the name of the request
+		"metadata": map[string]any{"name": shootName},
+		"spec": map[string]any{
+			"expirationSeconds": int64(g.expiration.Seconds()),
+		},
+	}}
+
+	response, err := g.client.Resource(gardener.ShootGVR).Namespace(g.namespace).
+		Create(ctx, request, metav1.CreateOptions{}, adminKubeconfigSubresource)
+	if err != nil {
+		return nil, time.Time{}, classifyAPIError(err)
+	}
+
+	encoded, found, err := unstructured.NestedString(response.Object, "status", "kubeconfig")
+	if err != nil || !found || encoded == "" {
+		return nil, time.Time{}, fmt.Errorf("%w: response does not include a kubeconfig", ErrInvalid)
+	}
+
+	kubeconfig, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("%w: failed to decode kubeconfig: %w", ErrInvalid, err)
+	}
+
+	expiresAt := g.now().Add(g.expiration)
+
+	if timestamp, found, _ := unstructured.NestedString(response.Object, "status", "expirationTimestamp"); found {
+		if parsed, err := time.Parse(time.RFC3339, timestamp); err == nil {
+			expiresAt = parsed
+		}
+	}
+
+	return kubeconfig, expiresAt, nil
+}
+
+// deleteExpiredLocked removes the kubeconfigs of runtimes which were not requested until they had to be refreshed,
+// e.g. because the runtimes are not tracked anymore.
+func (g *GardenerProvider) deleteExpiredLocked() {
+	for runtimeID, cached := range g.kubeconfigs {
+		if !g.now().Before(cached.refreshAt) {
+			delete(g.kubeconfigs, runtimeID)
+		}
+	}
+}
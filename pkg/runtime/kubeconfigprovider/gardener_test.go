@@ -0,0 +1,94 @@
+package kubeconfigprovider
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/kyma-project/kyma-metrics-collector/pkg/gardener"
+)
+
+const gardenerNamespace = "garden-kyma"
+
+func newShoot(name, runtimeID string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": gardener.ShootGVR.GroupVersion().String(),
+		"kind":       "Shoot",
+		"metadata": map[string]any{
+			"name":      name,
+			"namespace": gardenerNamespace,
+			"labels":    map[string]any{ShootRuntimeIDLabel: runtimeID},
+		},
+	}}
+}
+
+func TestGardenerProvider_Get(t *testing.T) {
+	client := fake.NewSimpleDynamicClientWithCustomListKinds(k8sruntime.NewScheme(),
+		map[schema.GroupVersionResource]string{gardener.ShootGVR: "ShootList"},
+		newShoot("shoot-test", "test"),
+		newShoot("shoot-dup-1", "duplicate"),
+		newShoot("shoot-dup-2", "duplicate"),
+	)
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	requests := 0
+
+	client.PrependReactor("create", "shoots", func(action k8stesting.Action) (bool, k8sruntime.Object, error) {
+		createAction := action.(k8stesting.CreateAction)
+		require.Equal(t, adminKubeconfigSubresource, createAction.GetSubresource())
+		require.Equal(t, "shoot-test", createAction.(k8stesting.CreateActionImpl).Name)
+
+		request := createAction.GetObject().(*unstructured.Unstructured)
+		expirationSeconds, _, _ := unstructured.NestedInt64(request.Object, "spec", "expirationSeconds")
+		require.EqualValues(t, 3600, expirationSeconds)
+
+		requests++
+
+		return true, &unstructured.Unstructured{Object: map[string]any{
+			"status": map[string]any{
+				"kubeconfig":          base64.StdEncoding.EncodeToString([]byte("kubeconfig-" + string(rune('0'+requests)))),
+				"expirationTimestamp": now.Add(time.Hour).Format(time.RFC3339),
+			},
+		}}, nil
+	})
+
+	provider := NewGardener(client, gardenerNamespace, time.Hour, zap.NewExample().Sugar(), "test")
+	provider.now = func() time.Time { return now }
+
+	got, err := provider.Get("test")
+	require.NoError(t, err)
+	require.Equal(t, []byte("kubeconfig-1"), got)
+
+	// the kubeconfig is cached until shortly before it expires
+	now = now.Add(47 * time.Minute)
+	got, err = provider.Get("test")
+	require.NoError(t, err)
+	require.Equal(t, []byte("kubeconfig-1"), got)
+	require.Equal(t, 1, requests)
+
+	now = now.Add(time.Minute)
+	got, err = provider.Get("test")
+	require.NoError(t, err)
+	require.Equal(t, []byte("kubeconfig-2"), got)
+	require.Equal(t, 2, requests)
+
+	// an invalidated kubeconfig is requested again
+	provider.Invalidate("test")
+	got, err = provider.Get("test")
+	require.NoError(t, err)
+	require.Equal(t, []byte("kubeconfig-3"), got)
+
+	_, err = provider.Get("missing")
+	require.ErrorIs(t, err, ErrNotFound)
+
+	_, err = provider.Get("duplicate")
+	require.ErrorContains(t, err, "found 2 shoots of runtime duplicate")
+}
@@ -0,0 +1,143 @@
+package kubeconfigprovider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+// InformerProvider provides the kubeconfigs from the kubeconfig secrets watched by a shared informer.
+// In contrast to KubeconfigProvider, rotated kubeconfigs are used as soon as the secret changes, and missing
+// secrets are answered from the local store, so the API requests do not grow with the number of runtimes.
+type InformerProvider struct {
+	factory  informers.SharedInformerFactory
+	informer cache.SharedIndexInformer
+	secrets  SecretConfig
+	logger   *zap.SugaredLogger
+	name     string
+	// size is the number of kubeconfig secrets in the store.
+	size atomic.Int64
+}
+
+// NewInformer creates an InformerProvider watching the secrets in the namespace of the kubeconfig secrets.
+// If labelSelector is set, only the secrets matching it are watched. Secrets whose name does not match the name
+// template are kept in the store without their data. name is used to identify the provider in the metrics.
+func NewInformer(client kubernetes.Interface, secrets SecretConfig, logger *zap.SugaredLogger, resyncPeriod time.Duration, labelSelector, name string) *InformerProvider {
+	factory := informers.NewSharedInformerFactoryWithOptions(client, resyncPeriod,
+		informers.WithNamespace(secrets.Namespace),
+		informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.LabelSelector = labelSelector
+		}),
+	)
+
+	return &InformerProvider{
+		factory:  factory,
+		informer: factory.Core().V1().Secrets().Informer(),
+		secrets:  secrets,
+		logger:   logger,
+		name:     name,
+	}
+}
+
+// Start starts the informer and waits until the kubeconfig secrets are synced.
+func (k *InformerProvider) Start(ctx context.Context) error {
+	if err := k.informer.SetTransform(k.stripNonKubeconfigSecrets); err != nil {
+		return fmt.Errorf("failed to set transform of kubeconfig secrets informer: %w", err)
+	}
+
+	if _, err := k.informer.AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: k.isKubeconfigSecret,
+		Handler: cache.ResourceEventHandlerFuncs{
+			AddFunc: func(any) {
+				k.size.Add(1)
+			},
+			DeleteFunc: func(any) {
+				k.size.Add(-1)
+			},
+		},
+	}); err != nil {
+		return fmt.Errorf("failed to add handler to kubeconfig secrets informer: %w", err)
+	}
+
+	k.factory.Start(ctx.Done())
+
+	if !cache.WaitForCacheSync(ctx.Done(), k.informer.HasSynced) {
+		return errors.New("failed to sync kubeconfig secrets")
+	}
+
+	k.logger.Infof("synced %d kubeconfig secrets", k.size.Load())
+	k.recordMetrics()
+
+	return nil
+}
+
+// Get returns the kubeconfig of the runtime from the local store.
+func (k *InformerProvider) Get(runtimeID string) ([]byte, error) {
+	k.recordMetrics()
+
+	kubeconfig, err := k.get(runtimeID)
+	if err != nil {
+		recordFailure(k.name, err)
+
+		return nil, err
+	}
+
+	return kubeconfig, nil
+}
+
+func (k *InformerProvider) get(runtimeID string) ([]byte, error) {
+	obj, found, err := k.informer.GetStore().GetByKey(k.secrets.Namespace + "/" + k.secrets.secretName(runtimeID))
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to get kubeconfig secret of runtime %s from store: %w", ErrTransient, runtimeID, err)
+	}
+
+	if !found {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, runtimeID)
+	}
+
+	secret, ok := obj.(*corev1.Secret)
+	if !ok {
+		return nil, fmt.Errorf("%w: unexpected object of type %T in kubeconfig secrets store", ErrTransient, obj)
+	}
+
+	return kubeconfigFromSecret(secret, k.secrets.DataKey, runtimeID)
+}
+
+func (k *InformerProvider) recordMetrics() {
+	cacheSizeMetric.WithLabelValues(k.name).Set(float64(k.size.Load()))
+}
+
+func (k *InformerProvider) isKubeconfigSecret(obj any) bool {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+
+	secret, ok := obj.(*corev1.Secret)
+
+	return ok && k.secrets.isSecretName(secret.Name)
+}
+
+// stripNonKubeconfigSecrets drops the data of all other secrets in the namespace, so they do not use memory.
+func (k *InformerProvider) stripNonKubeconfigSecrets(obj any) (any, error) {
+	secret, ok := obj.(*corev1.Secret)
+	if !ok || k.secrets.isSecretName(secret.Name) {
+		return obj, nil
+	}
+
+	stripped := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+		Name:            secret.Name,
+		Namespace:       secret.Namespace,
+		ResourceVersion: secret.ResourceVersion,
+	}}
+
+	return stripped, nil
+}
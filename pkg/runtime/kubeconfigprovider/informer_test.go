@@ -0,0 +1,72 @@
+package kubeconfigprovider
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newKubeconfigSecret(name, kubeconfig string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: DefaultSecretNamespace},
+		Data:       map[string][]byte{DefaultSecretDataKey: []byte(kubeconfig)},
+	}
+}
+
+func TestInformerProvider_Get(t *testing.T) {
+	cs := fake.NewClientset(
+		newKubeconfigSecret("kubeconfig-test", "test"),
+		newKubeconfigSecret("kubeconfig-empty", ""),
+		newKubeconfigSecret("other", "other"),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	provider := NewInformer(cs, DefaultSecretConfig(), zap.NewExample().Sugar(), 0, "", "test")
+	require.NoError(t, provider.Start(ctx))
+	require.EqualValues(t, 2, provider.size.Load())
+
+	got, err := provider.Get("test")
+	require.NoError(t, err)
+	require.Equal(t, []byte("test"), got)
+
+	_, err = provider.Get("empty")
+	require.ErrorContains(t, err, "empty kubeconfig")
+
+	// missing secrets are answered from the store
+	_, err = provider.Get("missing")
+	require.ErrorIs(t, err, ErrNotFound)
+
+	// other secrets are stored without data
+	obj, found, err := provider.informer.GetStore().GetByKey(DefaultSecretNamespace + "/other")
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Empty(t, obj.(*corev1.Secret).Data)
+
+	// a rotated kubeconfig is used as soon as the secret changes
+	_, err = cs.CoreV1().Secrets(DefaultSecretNamespace).Update(ctx, newKubeconfigSecret("kubeconfig-test", "rotated"), metav1.UpdateOptions{})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		got, err := provider.Get("test")
+		return err == nil && string(got) == "rotated"
+	}, 5*time.Second, 10*time.Millisecond)
+
+	err = cs.CoreV1().Secrets(DefaultSecretNamespace).Delete(ctx, "kubeconfig-test", metav1.DeleteOptions{})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		_, err := provider.Get("test")
+		return err != nil && provider.size.Load() == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// the secrets are only listed and watched, never fetched one by one
+	for _, action := range cs.Actions() {
+		require.NotEqual(t, "get", action.GetVerb())
+	}
+}
@@ -15,102 +15,148 @@ import (
 	v1 "k8s.io/client-go/kubernetes/typed/core/v1"
 )
 
-// ErrNotFound is returned when an item is not found in the cache.
-var ErrNotFound = errors.New("item not found in cache")
-
-const (
-	// namespace where the kubeconfig secrets are stored.
-	kcpNamespace = "kcp-system"
-	// prefix for the kubeconfig secrets.
-	kubconfigSecretPrefix = "kubeconfig-"
-)
-
 // KubeconfigProvider is a struct that provides methods to interact with a kubeconfig cache.
 type KubeconfigProvider struct {
-	cache  *ttlcache.Cache[string, []byte]
-	client v1.CoreV1Interface
-	ttl    time.Duration
-	logger *zap.SugaredLogger
-	name   string
+	cache *ttlcache.Cache[string, []byte]
+	// notFound caches the errors of runtimes without a kubeconfig secret, so missing secrets are not requested
+	// again for every scrape.
+	notFound    *ttlcache.Cache[string, error]
+	client      v1.CoreV1Interface
+	secrets     SecretConfig
+	ttl         time.Duration
+	notFoundTTL time.Duration
+	logger      *zap.SugaredLogger
+	name        string
 }
 
 // New creates a new instance of KubeconfigProvider.
-// It initializes the cache with the given TTL and loader function.
-// The loader function is used to get the kubeconfig from the secret.
-// name is used to identify the cache in the metrics.
-func New(client v1.CoreV1Interface, logger *zap.SugaredLogger, ttl time.Duration, name string) *KubeconfigProvider {
-	loader := loaderFunc(client, logger, ttl)
-
+// It caches the kubeconfigs loaded from the secrets described by secrets with the given TTL, and caches missing
+// secrets for notFoundTTL. name is used to identify the cache in the metrics.
+func New(client v1.CoreV1Interface, secrets SecretConfig, logger *zap.SugaredLogger, ttl, notFoundTTL time.Duration, name string) *KubeconfigProvider {
 	return &KubeconfigProvider{
-		client: client,
+		client:  client,
+		secrets: secrets,
 		cache: ttlcache.New[string, []byte](
 			ttlcache.WithTTL[string, []byte](ttl),
 			ttlcache.WithDisableTouchOnHit[string, []byte](),
-			ttlcache.WithLoader[string, []byte](loader),
 		),
-		ttl:    ttl,
-		logger: logger,
-		name:   name,
+		notFound: ttlcache.New[string, error](
+			ttlcache.WithTTL[string, error](notFoundTTL),
+			ttlcache.WithDisableTouchOnHit[string, error](),
+		),
+		ttl:         ttl,
+		notFoundTTL: notFoundTTL,
+		logger:      logger,
+		name:        name,
 	}
 }
 
-// loaderFunc returns a ttlcache.LoaderFunc that loads the kubeconfig from a Kubernetes secret.
-// It logs the loading process and stores the kubeconfig in the cache with a TTL that includes jitter.
-func loaderFunc(client v1.CoreV1Interface, logger *zap.SugaredLogger, ttl time.Duration) ttlcache.LoaderFunc[string, []byte] {
-	return func(c *ttlcache.Cache[string, []byte], key string) *ttlcache.Item[string, []byte] {
-		logger.Infof("loading Kubeconfig for: %v", key)
+// Get retrieves the kubeconfig for the given runtimeID from the cache, or loads it from the secret on a cache miss.
+// It cleans the cache from expired items and records the cache size in the metrics.
+// The returned errors wrap ErrNotFound, ErrForbidden, ErrInvalid or ErrTransient.
+func (k *KubeconfigProvider) Get(runtimeID string) ([]byte, error) {
+	k.cache.DeleteExpired()
+	k.notFound.DeleteExpired()
+	k.recordMetrics()
+
+	if item := k.cache.Get(runtimeID); item != nil {
+		return item.Value(), nil
+	}
+
+	if item := k.notFound.Get(runtimeID); item != nil {
+		recordFailure(k.name, item.Value())
+
+		return nil, item.Value()
+	}
+
+	k.logger.Infof("loading Kubeconfig for: %v", runtimeID)
 
-		kubeconfig, err := getKubeConfigFromSecret(logger, client, key)
-		if err != nil {
-			logger.Errorf("failed to get kubeconfig for runtimeID %s from secret: %s", key, err)
-			return nil
+	kubeconfig, err := getKubeConfigFromSecret(k.logger, k.client, k.secrets, runtimeID)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) && k.notFoundTTL > 0 {
+			k.notFound.Set(runtimeID, err, k.notFoundTTL)
 		}
 
-		logger.Infof("storing Kubeconfig for: %v", key)
+		recordFailure(k.name, err)
+
+		return nil, fmt.Errorf("failed to get kubeconfig for runtimeID %s from secret: %w", runtimeID, err)
+	}
+
+	k.notFound.Delete(runtimeID)
 
-		return c.Set(key, kubeconfig, getJitterTTL(ttl))
+	ttl := k.cacheTTL(runtimeID, kubeconfig)
+	if ttl <= 0 {
+		// the kubeconfig is used once, and loaded again for the next scrape in the hope that it was rotated
+		return kubeconfig, nil
 	}
+
+	k.logger.Infof("storing Kubeconfig for: %v", runtimeID)
+	k.cache.Set(runtimeID, kubeconfig, ttl)
+
+	return kubeconfig, nil
 }
 
-// Get retrieves the kubeconfig for the given runtimeID from the cache.
-// It cleans the cache from expired items and records the cache size in the metrics.
-func (k *KubeconfigProvider) Get(runtimeID string) ([]byte, error) {
-	k.cache.DeleteExpired()
-	k.recordMetrics()
+// Invalidate removes the kubeconfig of the runtime from the cache, e.g. because the runtime rejected its credentials,
+// so it is loaded again with the next Get.
+func (k *KubeconfigProvider) Invalidate(runtimeID string) {
+	k.cache.Delete(runtimeID)
+}
 
-	if item := k.cache.Get(runtimeID); item != nil {
-		return item.Value(), nil
+// cacheTTL returns the TTL of the kubeconfig in the cache. If the credentials of the kubeconfig expire, it is
+// cached only until expiryRefreshMargin before they expire, so a rotated kubeconfig is loaded ahead of the expiry.
+func (k *KubeconfigProvider) cacheTTL(runtimeID string, kubeconfig []byte) time.Duration {
+	ttl := getJitterTTL(k.ttl)
+
+	expiry, found := kubeconfigExpiry(kubeconfig)
+	if !found {
+		return ttl
+	}
+
+	untilRefresh := time.Until(expiry) - expiryRefreshMargin
+	if untilRefresh >= ttl {
+		return ttl
+	}
+
+	if untilRefresh <= 0 {
+		k.logger.Warnf("credentials of kubeconfig for runtimeID %s expire at %s, not caching it", runtimeID, expiry)
+	} else {
+		k.logger.Debugf("credentials of kubeconfig for runtimeID %s expire at %s, caching it for %s", runtimeID, expiry, untilRefresh)
 	}
 
-	return nil, fmt.Errorf("%w: %s", ErrNotFound, runtimeID)
+	return untilRefresh
 }
 
 // getKubeConfigFromSecret retrieves the kubeconfig from the secret.
-func getKubeConfigFromSecret(logger *zap.SugaredLogger, client v1.CoreV1Interface, runtimeID string) ([]byte, error) {
-	secretResourceName := kubconfigSecretPrefix + runtimeID
+func getKubeConfigFromSecret(logger *zap.SugaredLogger, client v1.CoreV1Interface, secrets SecretConfig, runtimeID string) ([]byte, error) {
+	secretResourceName := secrets.secretName(runtimeID)
 
-	secret, err := getKubeConfigSecret(logger, client, runtimeID, secretResourceName)
+	secret, err := getKubeConfigSecret(logger, client, secrets.Namespace, runtimeID, secretResourceName)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get secret: %w", err)
 	}
 
-	kubeconfig, found := secret.Data["config"]
+	return kubeconfigFromSecret(secret, secrets.DataKey, runtimeID)
+}
+
+// kubeconfigFromSecret returns the kubeconfig stored in the kubeconfig secret of the runtime.
+func kubeconfigFromSecret(secret *corev1.Secret, dataKey, runtimeID string) ([]byte, error) {
+	kubeconfig, found := secret.Data[dataKey]
 	if !found {
-		return nil, fmt.Errorf("kubeconfig-secret '%s' for runtime '%s' does not include the data-key 'config'",
-			secretResourceName, runtimeID)
+		return nil, fmt.Errorf("%w: kubeconfig-secret '%s' for runtime '%s' does not include the data-key '%s'",
+			ErrInvalid, secret.Name, runtimeID, dataKey)
 	}
 
 	if len(kubeconfig) == 0 {
-		return nil, fmt.Errorf("kubeconfig-secret '%s' for runtime '%s' includes an empty kubeconfig string",
-			secretResourceName, runtimeID)
+		return nil, fmt.Errorf("%w: kubeconfig-secret '%s' for runtime '%s' includes an empty kubeconfig string",
+			ErrInvalid, secret.Name, runtimeID)
 	}
 
 	return kubeconfig, nil
 }
 
 // getKubeConfigSecret retrieves the kubeconfig secret from the cluster.
-func getKubeConfigSecret(logger *zap.SugaredLogger, client v1.CoreV1Interface, runtimeID, secretResourceName string) (*corev1.Secret, error) {
-	secret, err := client.Secrets(kcpNamespace).Get(context.Background(), secretResourceName, metav1.GetOptions{})
+func getKubeConfigSecret(logger *zap.SugaredLogger, client v1.CoreV1Interface, namespace, runtimeID, secretResourceName string) (*corev1.Secret, error) {
+	secret, err := client.Secrets(namespace).Get(context.Background(), secretResourceName, metav1.GetOptions{})
 	if err != nil {
 		switch {
 		case k8serr.IsNotFound(err):
@@ -118,10 +164,10 @@ func getKubeConfigSecret(logger *zap.SugaredLogger, client v1.CoreV1Interface, r
 		case k8serr.IsForbidden(err):
 			logger.Errorf("kubeconfig provider is not allowed to lookup kubeconfig-secret '%s' for runtimeID %s: %v", secretResourceName, runtimeID, err)
 		default:
-			logger.Errorf("kubeconfig provider failed to lookup kubeconfig-secret '%s' for runtimeID %s: %v", secretResourceName, runtimeID, err)
+			logger.Warnf("kubeconfig provider failed to lookup kubeconfig-secret '%s' for runtimeID %s: %v", secretResourceName, runtimeID, err)
 		}
 
-		return nil, err
+		return nil, classifyAPIError(err)
 	}
 
 	return secret, nil
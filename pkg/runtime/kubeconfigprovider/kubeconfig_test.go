@@ -1,14 +1,18 @@
 package kubeconfigprovider
 
 import (
+	"errors"
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/kubernetes/fake"
 	k8stesting "k8s.io/client-go/testing"
 )
@@ -31,7 +35,7 @@ func TestKubeconfigProvider_Get(t *testing.T) {
 	})
 
 	logger := zap.NewExample().Sugar()
-	provider := New(cs.CoreV1(), logger, 1*time.Second, "test")
+	provider := New(cs.CoreV1(), DefaultSecretConfig(), logger, 1*time.Second, 0, "test")
 	require.Equal(t, 0, provider.cache.Len())
 
 	// Get the kubeconfig from the kubeconfigprovider. Expect the cache to be missed.
@@ -60,3 +64,97 @@ func TestKubeconfigProvider_Get(t *testing.T) {
 	require.Equal(t, []byte("test"), got)
 	require.Equal(t, 2, callCount)
 }
+
+func TestKubeconfigProvider_GetWithSecretConfig(t *testing.T) {
+	cs := fake.NewClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "test.kubeconfig", Namespace: "garden"},
+		Data:       map[string][]byte{"kubeconfig": []byte("test")},
+	})
+	secrets := SecretConfig{Namespace: "garden", NameTemplate: "{runtimeID}.kubeconfig", DataKey: "kubeconfig"}
+
+	provider := New(cs.CoreV1(), secrets, zap.NewExample().Sugar(), time.Minute, time.Minute, "test")
+
+	got, err := provider.Get("test")
+	require.NoError(t, err)
+	require.Equal(t, []byte("test"), got)
+
+	_, err = provider.Get("missing")
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestKubeconfigProvider_GetNotFound(t *testing.T) {
+	cs := fake.NewClientset()
+	getCount := 0
+
+	cs.PrependReactor("get", "secrets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		getCount++
+
+		return false, nil, nil
+	})
+
+	provider := New(cs.CoreV1(), DefaultSecretConfig(), zap.NewExample().Sugar(), time.Minute, 500*time.Millisecond, "not-found")
+
+	// the missing secret is cached, so it is requested only once
+	for range 3 {
+		_, err := provider.Get("missing")
+		require.ErrorIs(t, err, ErrNotFound)
+	}
+
+	require.Equal(t, 1, getCount)
+	require.InDelta(t, 3, testutil.ToFloat64(failuresMetric.WithLabelValues("not-found", FailureReasonNotFound)), 0)
+
+	// the secret is requested again after the negative cache expired
+	time.Sleep(500 * time.Millisecond)
+
+	_, err := provider.Get("missing")
+	require.ErrorIs(t, err, ErrNotFound)
+	require.Equal(t, 2, getCount)
+}
+
+func TestKubeconfigProvider_GetErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		wantErr error
+	}{
+		{
+			name:    "forbidden",
+			err:     k8serr.NewForbidden(schema.GroupResource{Resource: "secrets"}, "kubeconfig-test", errors.New("rbac")),
+			wantErr: ErrForbidden,
+		},
+		{
+			name:    "transient",
+			err:     k8serr.NewServiceUnavailable("unavailable"),
+			wantErr: ErrTransient,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cs := fake.NewClientset()
+			getCount := 0
+
+			cs.PrependReactor("get", "secrets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				getCount++
+
+				return true, nil, tc.err
+			})
+
+			provider := New(cs.CoreV1(), DefaultSecretConfig(), zap.NewExample().Sugar(), time.Minute, time.Minute, tc.name)
+
+			// only missing secrets are cached
+			for range 2 {
+				_, err := provider.Get("test")
+				require.ErrorIs(t, err, tc.wantErr)
+			}
+
+			require.Equal(t, 2, getCount)
+		})
+	}
+
+	cs := fake.NewClientset(newKubeconfigSecret("kubeconfig-empty", ""))
+	provider := New(cs.CoreV1(), DefaultSecretConfig(), zap.NewExample().Sugar(), time.Minute, time.Minute, "invalid")
+
+	_, err := provider.Get("empty")
+	require.ErrorIs(t, err, ErrInvalid)
+}
@@ -5,14 +5,27 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promauto"
 )
 
-var cacheSizeMetric = promauto.NewGaugeVec(
-	prometheus.GaugeOpts{
-		Namespace: "kmc",
-		Subsystem: "kubeconfig_cache",
-		Name:      "size",
-		Help:      "Number of items in the kubeconfig kubeconfigprovider.",
-	}, []string{"name"})
+var (
+	cacheSizeMetric = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "kmc",
+			Subsystem: "kubeconfig_cache",
+			Name:      "size",
+			Help:      "Number of items in the kubeconfig kubeconfigprovider.",
+		}, []string{"name"})
+	failuresMetric = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "kmc",
+			Subsystem: "kubeconfig_provider",
+			Name:      "failures_total",
+			Help:      "Number of failed requests for kubeconfigs per reason, including the ones answered from the negative cache.",
+		}, []string{"name", "reason"})
+)
 
 func (k *KubeconfigProvider) recordMetrics() {
 	cacheSizeMetric.With(prometheus.Labels{"name": k.name}).Set(float64(k.cache.Len()))
 }
+
+func recordFailure(name string, err error) {
+	failuresMetric.With(prometheus.Labels{"name": name, "reason": FailureReason(err)}).Inc()
+}
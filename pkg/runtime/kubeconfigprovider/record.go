@@ -9,5 +9,9 @@ type Record struct {
 	GlobalAccountID string
 	ShootName       string
 	ProviderType    string
+	Region          string
+	PlanName        string
 	ScanMap         collector.ScanMap
+	// KubeconfigMissingScrapes is the number of consecutive scrapes which found no kubeconfig of the runtime.
+	KubeconfigMissingScrapes int
 }
@@ -0,0 +1,70 @@
+package kubeconfigprovider
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	// RuntimeIDPlaceholder is replaced by the runtime ID in the name template of the kubeconfig secrets.
+	RuntimeIDPlaceholder = "{runtimeID}"
+
+	// DefaultSecretNamespace is the namespace where KEB and infrastructure-manager store the kubeconfig secrets.
+	DefaultSecretNamespace = "kcp-system"
+	// DefaultSecretNameTemplate is the name of the kubeconfig secrets of KEB and infrastructure-manager.
+	DefaultSecretNameTemplate = "kubeconfig-" + RuntimeIDPlaceholder
+	// DefaultSecretDataKey is the data key of the kubeconfig in the kubeconfig secrets.
+	DefaultSecretDataKey = "config"
+)
+
+// SecretConfig describes where the kubeconfig secrets of the runtimes are stored.
+type SecretConfig struct {
+	// Namespace is the namespace of the kubeconfig secrets.
+	Namespace string
+	// NameTemplate is the name of the kubeconfig secret of a runtime, in which RuntimeIDPlaceholder is replaced by the runtime ID.
+	NameTemplate string
+	// DataKey is the data key of the kubeconfig in the secret.
+	DataKey string
+}
+
+// DefaultSecretConfig returns the configuration of the kubeconfig secrets of KEB and infrastructure-manager.
+func DefaultSecretConfig() SecretConfig {
+	return SecretConfig{
+		Namespace:    DefaultSecretNamespace,
+		NameTemplate: DefaultSecretNameTemplate,
+		DataKey:      DefaultSecretDataKey,
+	}
+}
+
+// Validate returns an error if the configuration does not identify the kubeconfig secret of every runtime.
+func (c SecretConfig) Validate() error {
+	var errs []error
+
+	if c.Namespace == "" {
+		errs = append(errs, errors.New("namespace of kubeconfig secrets is empty"))
+	}
+
+	if strings.Count(c.NameTemplate, RuntimeIDPlaceholder) != 1 {
+		errs = append(errs, fmt.Errorf("name template of kubeconfig secrets %q must contain %s exactly once",
+			c.NameTemplate, RuntimeIDPlaceholder))
+	}
+
+	if c.DataKey == "" {
+		errs = append(errs, errors.New("data key of kubeconfig secrets is empty"))
+	}
+
+	return errors.Join(errs...)
+}
+
+// secretName returns the name of the kubeconfig secret of the runtime.
+func (c SecretConfig) secretName(runtimeID string) string {
+	return strings.Replace(c.NameTemplate, RuntimeIDPlaceholder, runtimeID, 1)
+}
+
+// isSecretName returns true if the name matches the name template for some runtime ID.
+func (c SecretConfig) isSecretName(name string) bool {
+	prefix, suffix, _ := strings.Cut(c.NameTemplate, RuntimeIDPlaceholder)
+
+	return len(name) > len(prefix)+len(suffix) && strings.HasPrefix(name, prefix) && strings.HasSuffix(name, suffix)
+}
@@ -0,0 +1,27 @@
+package kubeconfigprovider
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecretConfig(t *testing.T) {
+	require.NoError(t, DefaultSecretConfig().Validate())
+	require.Equal(t, "kubeconfig-test", DefaultSecretConfig().secretName("test"))
+
+	config := SecretConfig{Namespace: "garden", NameTemplate: "{runtimeID}.kubeconfig", DataKey: "kubeconfig"}
+	require.NoError(t, config.Validate())
+	require.Equal(t, "test.kubeconfig", config.secretName("test"))
+	require.True(t, config.isSecretName("test.kubeconfig"))
+	require.False(t, config.isSecretName(".kubeconfig"))
+	require.False(t, config.isSecretName("test.config"))
+
+	err := SecretConfig{NameTemplate: "kubeconfig"}.Validate()
+	require.ErrorContains(t, err, "namespace of kubeconfig secrets is empty")
+	require.ErrorContains(t, err, "must contain {runtimeID} exactly once")
+	require.ErrorContains(t, err, "data key of kubeconfig secrets is empty")
+
+	err = SecretConfig{Namespace: "garden", NameTemplate: "{runtimeID}-{runtimeID}", DataKey: "config"}.Validate()
+	require.ErrorContains(t, err, "exactly once")
+}
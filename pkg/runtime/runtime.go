@@ -13,6 +13,8 @@ type Info struct {
 	GlobalAccountID string
 	ShootName       string
 	ProviderType    string
+	Region          string
+	PlanName        string
 	Kubeconfig      rest.Config
 	Client          *http.Client
 }
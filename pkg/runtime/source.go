@@ -0,0 +1,41 @@
+package runtime
+
+import (
+	"context"
+
+	kebruntime "github.com/kyma-project/kyma-environment-broker/common/runtime"
+)
+
+// Runtime is a runtime provided by a Source. Sources other than KEB map their runtimes to the KEB representation,
+// so that all runtimes are tracked the same way.
+type Runtime struct {
+	kebruntime.RuntimeDTO
+	// Billable decides if the runtime is tracked, if set. Otherwise, it is decided from the operations of the runtime.
+	Billable *bool
+}
+
+// FromKEB wraps the runtimes returned by KEB.
+func FromKEB(runtimes []kebruntime.RuntimeDTO) []Runtime {
+	wrapped := make([]Runtime, 0, len(runtimes))
+	for _, runtime := range runtimes {
+		wrapped = append(wrapped, Runtime{RuntimeDTO: runtime})
+	}
+
+	return wrapped
+}
+
+// SourceHandler receives the runtimes of a Source. A Source does not call the handler concurrently.
+type SourceHandler interface {
+	// Resync replaces the tracked runtimes with the complete list of runtimes. Runtimes missing in the list are not tracked anymore.
+	Resync(runtimes []Runtime)
+	// Update adds or updates the given runtimes and keeps all other runtimes.
+	Update(runtimes []Runtime)
+	// Delete stops tracking the runtime.
+	Delete(runtime Runtime)
+}
+
+// Source provides the runtimes which are tracked, e.g. by polling KEB or by watching Runtime CRs.
+type Source interface {
+	// Start passes the runtimes to the handler until the context is done. It blocks until then.
+	Start(ctx context.Context, handler SourceHandler) error
+}